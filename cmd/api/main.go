@@ -4,31 +4,20 @@ import (
 	"context"
 	"log"
 	"os"
+	"time"
 
-	_ "ServiceBookingApp/docs"
 	"ServiceBookingApp/internal/config"
-	"ServiceBookingApp/internal/infrastructure/db"
-	"github.com/gin-gonic/gin"
+	"ServiceBookingApp/internal/domain"
 	"github.com/joho/godotenv"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 
 	authService "ServiceBookingApp/internal/auth"
-	authHandler "ServiceBookingApp/internal/handlers/auth"
 	firebase "firebase.google.com/go/v4"
 	"google.golang.org/api/option"
 
-	"ServiceBookingApp/internal/handlers/services"
-
-	"ServiceBookingApp/internal/handlers/providers"
-
-	"ServiceBookingApp/internal/handlers/appointments"
-
-	"ServiceBookingApp/internal/handlers/schedules"
-
-	"ServiceBookingApp/internal/handlers/users"
-
-	"ServiceBookingApp/internal/handlers/public"
+	"ServiceBookingApp/internal/audit"
+	"ServiceBookingApp/internal/captcha"
+	"ServiceBookingApp/internal/geo"
+	"ServiceBookingApp/internal/retention"
 )
 
 func main() {
@@ -39,12 +28,60 @@ func main() {
 
 	// Initialize Database
 
-	baseRepo, err := db.NewFirestoreRepository()
+	repos, err := newRepositories(context.Background())
 
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
-	defer baseRepo.Close()
+	defer repos.close()
+
+	// Locate provider addresses for radius searches
+
+	geocoder, err := geo.NewGeocoder(config.GetGeocoder(), config.GetGeocoderURL())
+	if err != nil {
+		log.Fatalf("Invalid GEOCODER: %v", err)
+	}
+	// Without one, providers get no coordinates and radius searches find
+	// nothing.
+	if geocoder != nil {
+		repos.providers = geo.NewProvidersRepository(repos.providers, geocoder)
+	}
+
+	// Record every mutation in the audit log
+
+	recorder := audit.NewRecorder(repos.audit)
+	repos.users = audit.NewUsersRepository(repos.users, repos.providers, recorder)
+	repos.appointments = audit.NewAppointmentsRepository(repos.appointments, recorder)
+	repos.services = audit.NewServicesRepository(repos.services, recorder)
+	repos.providers = audit.NewProvidersRepository(repos.providers, recorder)
+	repos.schedules = audit.NewSchedulesRepository(repos.schedules, recorder)
+
+	// Hard-delete soft-deleted documents once they are past retention, 90
+	// days unless SOFT_DELETE_RETENTION_DAYS says otherwise. Cancelled
+	// appointments are kept.
+
+	if days := config.GetSoftDeleteRetentionDays(); days > 0 {
+		job := retention.NewJob(time.Duration(days)*24*time.Hour, map[string]domain.Purger{
+			"appointments": repos.appointments,
+			"services":     repos.services,
+			"providers":    repos.providers,
+		})
+		go job.Run(context.Background(), 24*time.Hour)
+	}
+	if days := config.GetAuditRetentionDays(); days > 0 {
+		job := retention.NewJob(time.Duration(days)*24*time.Hour, map[string]domain.Purger{
+			"audit entries": repos.audit,
+		})
+		go job.Run(context.Background(), 24*time.Hour)
+	}
+
+	// Drop expired booking holds and idempotency keys
+
+	expiryJob := retention.NewJob(0, map[string]domain.Purger{
+		"expired holds":            repos.holds,
+		"expired idempotency keys": repos.idempotency,
+	})
+	go expiryJob.Run(context.Background(), time.Minute)
 
 	// Initialize Auth Service
 
@@ -67,137 +104,26 @@ func main() {
 		}
 		authSvc = &authService.FirebaseAuthService{Client: authClient}
 	}
-	// Initialize User Handler
-
-	userRepo := db.NewUsersRepository(baseRepo.(*db.FirestoreRepository))
-
-	userHdl := authHandler.NewUserHandler(authSvc, userRepo, "users")
-
-	// Setup Router
-	r := gin.Default()
-
-	// Swagger Route
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/swagger/doc.json")))
-
-	// Auth Routes
-	authGroup := r.Group("/auth")
-
-	authGroup.POST("/login", authService.AuthMiddleware(authSvc), userHdl.Login)
 
-	authGroup.GET("/me", authService.AuthMiddleware(authSvc), userHdl.GetMe)
-	authGroup.GET("/roles", authService.AuthMiddleware(authSvc), userHdl.GetRoles)
-
-	// Routes for services
-	{
-
-		repo := db.NewServicesRepository(baseRepo.(*db.FirestoreRepository))
-		providersRepo := db.NewProvidersRepository(baseRepo.(*db.FirestoreRepository))
-
-		handler := services.NewServicesHandler(repo, providersRepo)
-
-		group := r.Group("/api/services")
-
-		group.Use(authService.AuthMiddleware(authSvc))
-		group.Use(authService.UserActiveMiddleware(userRepo))
-
-		group.GET("", handler.List)
-		group.GET("/:id", handler.Get)
-		group.POST("", handler.Create)
-		group.PUT("/:id", handler.Update)
-		group.DELETE("/:id", handler.Delete)
+	// Check a CAPTCHA on public bookings
+
+	var verifier captcha.Verifier
+	switch config.GetCaptchaProvider() {
+	case "recaptcha":
+		verifier = captcha.NewSiteVerifier(captcha.RecaptchaURL, config.GetCaptchaSecret())
+	case "hcaptcha":
+		verifier = captcha.NewSiteVerifier(captcha.HCaptchaURL, config.GetCaptchaSecret())
+	case "turnstile":
+		verifier = captcha.NewSiteVerifier(captcha.TurnstileURL, config.GetCaptchaSecret())
+	case "":
+		// Rate limits alone protect public bookings.
+	default:
+		log.Fatalf("Unknown CAPTCHA_PROVIDER %q", config.GetCaptchaProvider())
 	}
 
-	// Routes for providers
-	{
-
-		repo := db.NewProvidersRepository(baseRepo.(*db.FirestoreRepository))
+	// Register routes
 
-		handler := providers.NewProvidersHandler(repo)
-
-		group := r.Group("/api/providers")
-
-		group.Use(authService.AuthMiddleware(authSvc))
-		group.Use(authService.UserActiveMiddleware(userRepo))
-
-		group.GET("", handler.List)
-		group.GET("/:id", handler.Get)
-		group.POST("", handler.Create)
-		group.PUT("/:id", handler.Update)
-		group.DELETE("/:id", handler.Delete)
-	}
-
-	// Routes for appointments
-	{
-		repo := db.NewAppointmentsRepository(baseRepo.(*db.FirestoreRepository))
-		servicesRepo := db.NewServicesRepository(baseRepo.(*db.FirestoreRepository))
-		providersRepo := db.NewProvidersRepository(baseRepo.(*db.FirestoreRepository))
-		schedulesRepo := db.NewSchedulesRepository(baseRepo.(*db.FirestoreRepository))
-
-		handler := appointments.NewAppointmentsHandler(repo, servicesRepo, providersRepo, schedulesRepo)
-
-		group := r.Group("/api/appointments")
-
-		group.Use(authService.AuthMiddleware(authSvc))
-		group.Use(authService.UserActiveMiddleware(userRepo))
-
-		group.GET("", handler.List)
-		group.GET("/:id", handler.Get)
-		group.POST("", handler.Create)
-		group.PUT("/:id", handler.Update)
-		group.DELETE("/:id", handler.Delete)
-
-		r.GET("/api/slots", authService.AuthMiddleware(authSvc), authService.UserActiveMiddleware(userRepo), handler.GetAvailableSlots)
-	}
-
-	// Routes for schedules
-	{
-		repo := db.NewSchedulesRepository(baseRepo.(*db.FirestoreRepository))
-		providersRepo := db.NewProvidersRepository(baseRepo.(*db.FirestoreRepository))
-		handler := schedules.NewSchedulesHandler(repo, providersRepo)
-		group := r.Group("/api/schedules")
-		group.Use(authService.AuthMiddleware(authSvc))
-		group.Use(authService.UserActiveMiddleware(userRepo))
-
-		group.GET("", handler.GetByProvider)
-		group.PUT("", handler.Upsert)
-	}
-
-	// Routes for users
-	{
-
-		repo := db.NewUsersRepository(baseRepo.(*db.FirestoreRepository))
-
-		handler := users.NewUsersHandler(repo)
-
-		group := r.Group("/api/users")
-
-		group.Use(authService.AuthMiddleware(authSvc))
-
-		group.POST("", handler.Create)
-
-		group.Use(authService.UserActiveMiddleware(userRepo))
-
-		group.GET("", handler.List)
-		group.GET("/:id", handler.Get)
-		group.PUT("/:id", handler.Update)
-		group.DELETE("/:id", handler.Delete)
-	}
-
-	// Routes for public widget
-	{
-		repo := db.NewAppointmentsRepository(baseRepo.(*db.FirestoreRepository))
-		servicesRepo := db.NewServicesRepository(baseRepo.(*db.FirestoreRepository))
-		providersRepo := db.NewProvidersRepository(baseRepo.(*db.FirestoreRepository))
-		schedulesRepo := db.NewSchedulesRepository(baseRepo.(*db.FirestoreRepository))
-
-		handler := public.NewPublicHandler(servicesRepo, schedulesRepo, repo, providersRepo)
-
-		group := r.Group("/public/providers/:provider_id")
-
-		group.GET("/services", handler.GetServices)
-		group.GET("/slots", handler.GetAvailableSlots)
-		group.POST("/appointments", handler.CreateAppointment)
-	}
+	r := newRouter(repos, authSvc, verifier)
 
 	port := os.Getenv("PORT")
 	if port == "" {
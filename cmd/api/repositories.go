@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/db"
+	"ServiceBookingApp/internal/infrastructure/memory"
+	"ServiceBookingApp/internal/infrastructure/mongodb"
+	"ServiceBookingApp/internal/infrastructure/postgres"
+)
+
+// repositories bundles one implementation of every domain port, all backed
+// by the same storage driver.
+type repositories struct {
+	appointments domain.AppointmentsRepository
+	services     domain.ServicesRepository
+	providers    domain.ProvidersRepository
+	schedules    domain.SchedulesRepository
+	users        domain.UsersRepository
+	audit        domain.AuditRepository
+	holds        domain.HoldsRepository
+	idempotency  domain.IdempotencyStore
+	rateLimits   domain.RateLimitStore
+
+	close func()
+}
+
+// newRepositories builds the repositories for the driver named by DB_DRIVER:
+// "firestore" (the default), "postgres" and "mongo", which read
+// DATABASE_URL, or "memory", optionally seeded from the JSON file in
+// MEMORY_SEED.
+func newRepositories(ctx context.Context) (*repositories, error) {
+	driver := os.Getenv("DB_DRIVER")
+
+	switch driver {
+	case "", "firestore":
+		baseRepo, err := db.NewFirestoreRepository()
+		if err != nil {
+			return nil, err
+		}
+		client := baseRepo.(*db.FirestoreRepository)
+		return &repositories{
+			appointments: db.NewAppointmentsRepository(client),
+			services:     db.NewServicesRepository(client),
+			providers:    db.NewProvidersRepository(client),
+			schedules:    db.NewSchedulesRepository(client),
+			users:        db.NewUsersRepository(client),
+			audit:        db.NewAuditRepository(client),
+			holds:        db.NewHoldsRepository(client),
+			idempotency:  db.NewIdempotencyStore(client),
+			rateLimits:   db.NewRateLimitStore(client),
+			close:        baseRepo.Close,
+		}, nil
+
+	case "postgres":
+		databaseURL := os.Getenv("DATABASE_URL")
+		if databaseURL == "" {
+			return nil, fmt.Errorf("DATABASE_URL is required for the postgres driver")
+		}
+		pg, err := postgres.NewPostgresDB(ctx, databaseURL)
+		if err != nil {
+			return nil, err
+		}
+		return &repositories{
+			appointments: postgres.NewAppointmentsRepository(pg),
+			services:     postgres.NewServicesRepository(pg),
+			providers:    postgres.NewProvidersRepository(pg),
+			schedules:    postgres.NewSchedulesRepository(pg),
+			users:        postgres.NewUsersRepository(pg),
+			audit:        postgres.NewAuditRepository(pg),
+			holds:        postgres.NewHoldsRepository(pg),
+			idempotency:  postgres.NewIdempotencyStore(pg),
+			rateLimits:   postgres.NewRateLimitStore(pg),
+			close:        pg.Close,
+		}, nil
+
+	case "mongo":
+		databaseURL := os.Getenv("DATABASE_URL")
+		if databaseURL == "" {
+			return nil, fmt.Errorf("DATABASE_URL is required for the mongo driver")
+		}
+		mdb, err := mongodb.NewMongoDB(ctx, databaseURL)
+		if err != nil {
+			return nil, err
+		}
+		return &repositories{
+			appointments: mongodb.NewAppointmentsRepository(mdb),
+			services:     mongodb.NewServicesRepository(mdb),
+			providers:    mongodb.NewProvidersRepository(mdb),
+			schedules:    mongodb.NewSchedulesRepository(mdb),
+			users:        mongodb.NewUsersRepository(mdb),
+			audit:        mongodb.NewAuditRepository(mdb),
+			holds:        mongodb.NewHoldsRepository(mdb),
+			idempotency:  mongodb.NewIdempotencyStore(mdb),
+			rateLimits:   mongodb.NewRateLimitStore(mdb),
+			close:        mdb.Close,
+		}, nil
+
+	case "memory":
+		store := memory.NewStore()
+		if seed := os.Getenv("MEMORY_SEED"); seed != "" {
+			if err := store.LoadSeedFile(seed); err != nil {
+				return nil, err
+			}
+		}
+		return &repositories{
+			appointments: memory.NewAppointmentsRepository(store),
+			services:     memory.NewServicesRepository(store),
+			providers:    memory.NewProvidersRepository(store),
+			schedules:    memory.NewSchedulesRepository(store),
+			users:        memory.NewUsersRepository(store),
+			audit:        memory.NewAuditRepository(store),
+			holds:        memory.NewHoldsRepository(store),
+			idempotency:  memory.NewIdempotencyStore(store),
+			rateLimits:   memory.NewRateLimitStore(store),
+			close:        func() {},
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER %q", driver)
+	}
+}
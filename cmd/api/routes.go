@@ -0,0 +1,326 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	_ "ServiceBookingApp/docs"
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+
+	authService "ServiceBookingApp/internal/auth"
+	authHandler "ServiceBookingApp/internal/handlers/auth"
+
+	"ServiceBookingApp/internal/handlers/services"
+
+	"ServiceBookingApp/internal/handlers/providers"
+
+	"ServiceBookingApp/internal/handlers/appointments"
+
+	"ServiceBookingApp/internal/handlers/schedules"
+
+	"ServiceBookingApp/internal/handlers/users"
+
+	"ServiceBookingApp/internal/handlers/public"
+
+	"ServiceBookingApp/internal/handlers/caldav"
+
+	statsHandler "ServiceBookingApp/internal/handlers/stats"
+
+	"ServiceBookingApp/internal/handlers/exports"
+
+	"ServiceBookingApp/internal/handlers/imports"
+
+	"ServiceBookingApp/internal/apperrors"
+	"ServiceBookingApp/internal/audit"
+	"ServiceBookingApp/internal/captcha"
+	"ServiceBookingApp/internal/config"
+	auditHandler "ServiceBookingApp/internal/handlers/audit"
+	onboardingHandler "ServiceBookingApp/internal/handlers/onboarding"
+	"ServiceBookingApp/internal/idempotency"
+	"ServiceBookingApp/internal/importer"
+	"ServiceBookingApp/internal/onboarding"
+	"ServiceBookingApp/internal/ratelimit"
+	"ServiceBookingApp/internal/stats"
+)
+
+// newRouter registers every route on a fresh gin engine. It is kept apart
+// from main so tests can drive the real routing and middleware stack. A nil
+// verifier skips the CAPTCHA on public bookings.
+func newRouter(repos *repositories, authSvc authService.AuthService, verifier captcha.Verifier) *gin.Engine {
+	// Initialize User Handler
+
+	userRepo := repos.users
+
+	userHdl := authHandler.NewUserHandler(authSvc, userRepo, "users")
+
+	// Setup Router
+	r := gin.Default()
+	// Client addresses key the public rate limits and are recorded in the
+	// audit log, so X-Forwarded-For only counts from known proxies.
+	if err := r.SetTrustedProxies(config.GetTrustedProxies()); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	r.Use(apperrors.Middleware())
+
+	// Swagger Route
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/swagger/doc.json")))
+
+	// Auth Routes
+	authGroup := r.Group("/auth")
+
+	authGroup.POST("/login", authService.AuthMiddleware(authSvc), audit.Middleware(), userHdl.Login)
+
+	authGroup.GET("/me", authService.AuthMiddleware(authSvc), userHdl.GetMe)
+	authGroup.GET("/roles", authService.AuthMiddleware(authSvc), userHdl.GetRoles)
+
+	// Routes for services
+	{
+
+		repo := repos.services
+		providersRepo := repos.providers
+
+		handler := services.NewServicesHandler(repo, providersRepo)
+
+		group := r.Group("/api/services")
+
+		group.Use(authService.AuthMiddleware(authSvc))
+		group.Use(audit.Middleware())
+		group.Use(authService.UserActiveMiddleware(userRepo))
+
+		group.GET("", handler.List)
+		group.GET("/:id", handler.Get)
+		group.POST("", handler.Create)
+		group.PUT("/order", handler.Reorder)
+		group.PUT("/:id", handler.Update)
+		group.DELETE("/:id", handler.Delete)
+		group.POST("/:id/restore", handler.Restore)
+	}
+
+	// Routes for providers
+	{
+
+		repo := repos.providers
+
+		handler := providers.NewProvidersHandler(repo)
+
+		group := r.Group("/api/providers")
+
+		group.Use(authService.AuthMiddleware(authSvc))
+		group.Use(audit.Middleware())
+		group.Use(authService.UserActiveMiddleware(userRepo))
+
+		group.GET("", handler.List)
+		group.GET("/:id", handler.Get)
+		group.POST("", handler.Create)
+		group.PUT("/:id", handler.Update)
+		group.DELETE("/:id", handler.Delete)
+		group.POST("/:id/restore", handler.Restore)
+	}
+
+	// Routes for appointments
+	{
+		repo := repos.appointments
+		servicesRepo := repos.services
+		providersRepo := repos.providers
+		schedulesRepo := repos.schedules
+
+		handler := appointments.NewAppointmentsHandler(repo, servicesRepo, providersRepo, schedulesRepo, repos.holds)
+
+		group := r.Group("/api/appointments")
+
+		group.Use(authService.AuthMiddleware(authSvc))
+		group.Use(audit.Middleware())
+		group.Use(authService.UserActiveMiddleware(userRepo))
+
+		group.GET("", handler.List)
+		group.GET("/:id", handler.Get)
+		group.POST("", idempotency.Middleware(repos.idempotency), handler.Create)
+		group.PUT("/:id", handler.Update)
+		group.DELETE("/:id", handler.Delete)
+		group.POST("/:id/restore", handler.Restore)
+
+		r.GET("/api/slots", authService.AuthMiddleware(authSvc), authService.UserActiveMiddleware(userRepo), handler.GetAvailableSlots)
+	}
+
+	// Routes for schedules
+	{
+		repo := repos.schedules
+		providersRepo := repos.providers
+		handler := schedules.NewSchedulesHandler(repo, providersRepo)
+		group := r.Group("/api/schedules")
+		group.Use(authService.AuthMiddleware(authSvc))
+		group.Use(audit.Middleware())
+		group.Use(authService.UserActiveMiddleware(userRepo))
+
+		group.GET("", handler.GetByProvider)
+		group.PUT("", handler.Upsert)
+	}
+
+	// Routes for users
+	{
+
+		repo := repos.users
+
+		handler := users.NewUsersHandler(repo)
+
+		group := r.Group("/api/users")
+
+		group.Use(authService.AuthMiddleware(authSvc))
+		group.Use(audit.Middleware())
+
+		group.POST("", handler.Create)
+
+		group.Use(authService.UserActiveMiddleware(userRepo))
+
+		group.GET("", handler.List)
+		group.GET("/:id", handler.Get)
+		group.PUT("/:id", handler.Update)
+		group.DELETE("/:id", handler.Delete)
+	}
+
+	// Routes for public widget
+	{
+		repo := repos.appointments
+		servicesRepo := repos.services
+		providersRepo := repos.providers
+		schedulesRepo := repos.schedules
+
+		handler := public.NewPublicHandler(servicesRepo, schedulesRepo, repo, providersRepo, repos.holds, config.GetMaxUpcomingBookingsPerCustomer())
+
+		r.GET("/public/providers", handler.Search)
+
+		group := r.Group("/public/providers/:provider_id")
+
+		group.Use(audit.Middleware())
+		group.Use(handler.ResolveProvider)
+
+		group.GET("", handler.GetProfile)
+		group.GET("/services", handler.GetServices)
+		group.GET("/slots", handler.GetAvailableSlots)
+		// Booking needs no account, so it is limited per client address and
+		// per provider, and can require a CAPTCHA, to keep scripts from
+		// filling a provider's calendar. Holds block slots too, so they get
+		// limits of their own.
+		group.POST("/holds",
+			ratelimit.Middleware(repos.rateLimits,
+				ratelimit.Rule{Name: "hold-ip", Limit: config.GetBookingRateLimitPerIP(), Window: time.Hour, Key: ratelimit.ByIP},
+				ratelimit.Rule{Name: "hold-provider", Limit: config.GetBookingRateLimitPerProvider(), Window: time.Hour, Key: public.ProviderID},
+			),
+			handler.CreateHold,
+		)
+		group.DELETE("/holds/:hold_id", handler.ReleaseHold)
+		// A retry replays the first response before the limits and the
+		// CAPTCHA, whose tokens are single-use, see it.
+		group.POST("/appointments",
+			idempotency.Middleware(repos.idempotency),
+			ratelimit.Middleware(repos.rateLimits,
+				ratelimit.Rule{Name: "booking-ip", Limit: config.GetBookingRateLimitPerIP(), Window: time.Hour, Key: ratelimit.ByIP},
+				ratelimit.Rule{Name: "booking-provider", Limit: config.GetBookingRateLimitPerProvider(), Window: time.Hour, Key: public.ProviderID},
+			),
+			captcha.Middleware(verifier),
+			handler.CreateAppointment,
+		)
+	}
+
+	// Routes for CalDAV sync
+	{
+		repo := repos.appointments
+		servicesRepo := repos.services
+		providersRepo := repos.providers
+
+		handler := caldav.NewCalDAVHandler(repo, servicesRepo, providersRepo, repos.holds, authSvc)
+
+		group := r.Group("/caldav/providers/:provider_id")
+
+		group.Use(handler.Authenticate)
+		group.Use(audit.Middleware())
+
+		group.Handle(http.MethodOptions, "/", handler.Options)
+		group.Handle("PROPFIND", "/", handler.Propfind)
+		group.Handle("REPORT", "/", handler.Report)
+		group.GET("/:object", handler.Get)
+		group.PUT("/:object", handler.Put)
+	}
+
+	// Routes for dashboard stats
+	{
+		repo := repos.appointments
+		servicesRepo := repos.services
+		providersRepo := repos.providers
+		schedulesRepo := repos.schedules
+
+		service := stats.NewService(repo, servicesRepo, schedulesRepo, 5*time.Minute)
+		handler := statsHandler.NewStatsHandler(service, providersRepo)
+
+		r.GET("/api/stats", authService.AuthMiddleware(authSvc), authService.UserActiveMiddleware(userRepo), handler.Get)
+	}
+
+	// Routes for exports
+	{
+		repo := repos.appointments
+		providersRepo := repos.providers
+
+		handler := exports.NewExportsHandler(repo, providersRepo)
+
+		group := r.Group("/api/exports")
+
+		group.Use(authService.AuthMiddleware(authSvc))
+		group.Use(audit.Middleware())
+		group.Use(authService.UserActiveMiddleware(userRepo))
+
+		group.GET("/appointments", handler.Appointments)
+		group.GET("/customers", handler.Customers)
+	}
+
+	// Routes for imports
+	{
+		repo := repos.appointments
+		servicesRepo := repos.services
+		providersRepo := repos.providers
+
+		handler := imports.NewImportsHandler(importer.NewImporter(servicesRepo, repo), providersRepo)
+
+		group := r.Group("/api/imports")
+
+		group.Use(authService.AuthMiddleware(authSvc))
+		group.Use(audit.Middleware())
+		group.Use(authService.UserActiveMiddleware(userRepo))
+
+		group.POST("/services", handler.Services)
+		group.POST("/appointments", handler.Appointments)
+	}
+
+	// Routes for onboarding
+	{
+		service := onboarding.NewService(repos.providers, repos.schedules, repos.services)
+		handler := onboardingHandler.NewOnboardingHandler(service)
+
+		group := r.Group("/api/onboarding")
+
+		// New users are inactive until their account is activated, and
+		// onboarding is what they do first, so it skips UserActiveMiddleware.
+		group.Use(authService.AuthMiddleware(authSvc))
+		group.Use(audit.Middleware())
+
+		group.GET("", handler.Status)
+		group.GET("/templates", handler.Templates)
+		group.POST("/establishment", handler.Establishment)
+		group.POST("/schedule", handler.Schedule)
+		group.POST("/services", handler.Services)
+	}
+
+	// Routes for the audit log
+	{
+		repo := repos.audit
+		providersRepo := repos.providers
+
+		handler := auditHandler.NewAuditHandler(repo, providersRepo)
+
+		r.GET("/api/audit", authService.AuthMiddleware(authSvc), authService.UserActiveMiddleware(userRepo), handler.List)
+	}
+
+	return r
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/memory"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uidAuthService accepts any token and treats it as the caller's UID.
+type uidAuthService struct{}
+
+func (uidAuthService) VerifyIDToken(ctx context.Context, idToken string) (*auth.Token, error) {
+	return &auth.Token{UID: idToken}, nil
+}
+
+type tenant struct {
+	uid, providerId, serviceId, appointmentId string
+}
+
+func seedTenant(t *testing.T, repos *repositories, uid string) tenant {
+	ctx := context.Background()
+	active := true
+	require.NoError(t, repos.users.Update(ctx, uid, &domain.Users{RoleId: "admin", IsActive: &active}))
+
+	providerId, err := repos.providers.Create(ctx, &domain.Providers{UserId: uid})
+	require.NoError(t, err)
+	serviceId, err := repos.services.Create(ctx, &domain.Services{ProviderId: providerId, Title: "Corte", DurationMinutes: 30})
+	require.NoError(t, err)
+	appointmentId, err := repos.appointments.Create(ctx, &domain.Appointments{
+		ProviderId:      providerId,
+		ServiceId:       serviceId,
+		ScheduledAt:     time.Now().Add(24 * time.Hour).UTC(),
+		DurationMinutes: 30,
+		Status:          "confirmed",
+		CustomerName:    uid + "'s customer",
+		CustomerEmail:   uid + "@example.com",
+	})
+	require.NoError(t, err)
+	_, err = repos.audit.Create(ctx, &domain.AuditEntry{ProviderId: providerId, ActorUID: uid, Action: "update", EntityType: "services", EntityId: serviceId, CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	return tenant{uid: uid, providerId: providerId, serviceId: serviceId, appointmentId: appointmentId}
+}
+
+func newMemoryRepositories() *repositories {
+	store := memory.NewStore()
+	return &repositories{
+		appointments: memory.NewAppointmentsRepository(store),
+		services:     memory.NewServicesRepository(store),
+		providers:    memory.NewProvidersRepository(store),
+		schedules:    memory.NewSchedulesRepository(store),
+		users:        memory.NewUsersRepository(store),
+		audit:        memory.NewAuditRepository(store),
+		holds:        memory.NewHoldsRepository(store),
+		idempotency:  memory.NewIdempotencyStore(store),
+		rateLimits:   memory.NewRateLimitStore(store),
+		close:        func() {},
+	}
+}
+
+// TestCrossTenantAccess signs in as one tenant and hits every route that
+// takes another tenant's IDs; all of them must answer 403. Routes that act
+// on the caller's own provider take no IDs, so for those it checks that a
+// user without a provider is refused and that a tenant only sees its own
+// data.
+func TestCrossTenantAccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repos := newMemoryRepositories()
+	alice := seedTenant(t, repos, "alice")
+	bob := seedTenant(t, repos, "bob")
+
+	r := newRouter(repos, uidAuthService{}, nil)
+
+	tests := []struct {
+		method, path, body string
+	}{
+		{"GET", "/api/services?provider_id=" + bob.providerId, ""},
+		{"GET", "/api/services/" + bob.serviceId, ""},
+		{"PUT", "/api/services/" + bob.serviceId, `{"title":"x"}`},
+		{"PUT", "/api/services/order", `{"service_ids":["` + bob.serviceId + `"]}`},
+		{"DELETE", "/api/services/" + bob.serviceId, ""},
+		{"POST", "/api/services/" + bob.serviceId + "/restore", ""},
+
+		{"GET", "/api/providers/" + bob.providerId, ""},
+		{"PUT", "/api/providers/" + bob.providerId, `{"name":"x"}`},
+		{"DELETE", "/api/providers/" + bob.providerId, ""},
+		{"POST", "/api/providers/" + bob.providerId + "/restore", ""},
+
+		{"GET", "/api/appointments?provider_id=" + bob.providerId, ""},
+		{"GET", "/api/appointments/" + bob.appointmentId, ""},
+		{"POST", "/api/appointments", `{"service_id":"` + bob.serviceId + `","scheduled_at":"2030-01-01T10:00:00Z"}`},
+		{"PUT", "/api/appointments/" + bob.appointmentId, `{"status":"cancelled"}`},
+		{"DELETE", "/api/appointments/" + bob.appointmentId, ""},
+		{"POST", "/api/appointments/" + bob.appointmentId + "/restore", ""},
+		{"GET", "/api/slots?date=2030-01-01&service=" + bob.serviceId, ""},
+
+		{"GET", "/api/schedules?provider_id=" + bob.providerId, ""},
+
+		{"GET", "/api/users/" + bob.uid, ""},
+		{"PUT", "/api/users/" + bob.uid, `{"name":"x"}`},
+		{"DELETE", "/api/users/" + bob.uid, ""},
+
+		{"PROPFIND", "/caldav/providers/" + bob.providerId + "/", ""},
+		{"GET", "/caldav/providers/" + bob.providerId + "/" + bob.appointmentId + ".ics", ""},
+		{"PUT", "/caldav/providers/" + bob.providerId + "/" + bob.appointmentId + ".ics", "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"},
+	}
+
+	serve := func(uid, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+uid)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := serve(alice.uid, tt.method, tt.path, tt.body)
+			assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+		})
+	}
+
+	// The owner still gets through on the same routes.
+	w := serve(bob.uid, "GET", "/api/appointments/"+bob.appointmentId, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// A signed-in user without a provider gets nothing from the routes
+	// scoped to the caller's provider.
+	active := true
+	require.NoError(t, repos.users.Update(context.Background(), "carol", &domain.Users{RoleId: "admin", IsActive: &active}))
+	from, to := time.Now().AddDate(0, 0, -1).Format("2006-01-02"), time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	scoped := []struct {
+		method, path, body string
+	}{
+		{"PUT", "/api/schedules", `{"type":"global","days":{}}`},
+		{"GET", "/api/stats", ""},
+		{"GET", "/api/exports/appointments?from=" + from + "&to=" + to, ""},
+		{"GET", "/api/exports/customers?from=" + from + "&to=" + to, ""},
+		{"POST", "/api/imports/services", `[]`},
+		{"POST", "/api/imports/appointments", `[]`},
+		{"GET", "/api/audit", ""},
+	}
+	for _, tt := range scoped {
+		t.Run("no provider "+tt.method+" "+tt.path, func(t *testing.T) {
+			w := serve("carol", tt.method, tt.path, tt.body)
+			assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+		})
+	}
+
+	// A tenant only sees its own rows there.
+	for _, path := range []string{
+		"/api/exports/appointments?from=" + from + "&to=" + to,
+		"/api/exports/customers?from=" + from + "&to=" + to,
+		"/api/audit",
+		"/api/users",
+	} {
+		t.Run("own data "+path, func(t *testing.T) {
+			w := serve(alice.uid, "GET", path, "")
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.Contains(t, w.Body.String(), "alice")
+			assert.NotContains(t, w.Body.String(), "bob")
+		})
+	}
+}
+
+// TestForwardedForIsNotTrusted checks that a client cannot dodge the per-IP
+// booking limit by sending a different X-Forwarded-For on every request,
+// unless the request really comes through a trusted proxy.
+func TestForwardedForIsNotTrusted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("BOOKING_RATE_LIMIT_PER_IP", "1")
+
+	hold := func(r *gin.Engine, providerId, forwardedFor string) int {
+		req := httptest.NewRequest("POST", "/public/providers/"+providerId+"/holds", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("no trusted proxies", func(t *testing.T) {
+		repos := newMemoryRepositories()
+		alice := seedTenant(t, repos, "alice")
+		r := newRouter(repos, uidAuthService{}, nil)
+
+		assert.NotEqual(t, http.StatusTooManyRequests, hold(r, alice.providerId, "203.0.113.1"))
+		assert.Equal(t, http.StatusTooManyRequests, hold(r, alice.providerId, "203.0.113.2"), "a forged address shares the peer's count")
+	})
+
+	t.Run("trusted proxy", func(t *testing.T) {
+		// httptest requests come from 192.0.2.1.
+		t.Setenv("TRUSTED_PROXIES", "192.0.2.0/24")
+		repos := newMemoryRepositories()
+		alice := seedTenant(t, repos, "alice")
+		r := newRouter(repos, uidAuthService{}, nil)
+
+		assert.NotEqual(t, http.StatusTooManyRequests, hold(r, alice.providerId, "203.0.113.1"))
+		assert.NotEqual(t, http.StatusTooManyRequests, hold(r, alice.providerId, "203.0.113.2"))
+		assert.Equal(t, http.StatusTooManyRequests, hold(r, alice.providerId, "203.0.113.1"))
+	})
+}
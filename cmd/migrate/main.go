@@ -0,0 +1,130 @@
+// Command migrate manages the Firestore schema: the composite indexes in
+// firestore.indexes.json and the versioned data migrations.
+//
+//	go run ./cmd/migrate indexes [-out firestore.indexes.json] [-check]
+//	go run ./cmd/migrate status
+//	go run ./cmd/migrate up
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"ServiceBookingApp/internal/infrastructure/db"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "indexes":
+		err = indexes(os.Args[2:])
+	case "status":
+		err = status(context.Background())
+	case "up":
+		err = up(context.Background())
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate indexes [-out file] [-check] | status | up")
+	os.Exit(2)
+}
+
+// indexes writes the composite indexes the repositories need, or with
+// -check fails when the committed file is out of date.
+func indexes(args []string) error {
+	fs := flag.NewFlagSet("indexes", flag.ExitOnError)
+	out := fs.String("out", "firestore.indexes.json", "file to write")
+	check := fs.Bool("check", false, "fail if the file differs instead of writing it")
+	fs.Parse(args)
+
+	want, err := db.MarshalIndexes(db.CompositeIndexes())
+	if err != nil {
+		return err
+	}
+
+	if *check {
+		got, err := os.ReadFile(*out)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(got, want) {
+			return fmt.Errorf("%s is out of date, run: go run ./cmd/migrate indexes", *out)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(*out, want, 0o644); err != nil {
+		return err
+	}
+	log.Printf("wrote %s, deploy it with: firebase deploy --only firestore:indexes", *out)
+	return nil
+}
+
+func newMigrator() (*db.Migrator, func(), error) {
+	baseRepo, err := db.NewFirestoreRepository()
+	if err != nil {
+		return nil, nil, err
+	}
+	return db.NewMigrator(baseRepo.(*db.FirestoreRepository), db.Migrations), baseRepo.Close, nil
+}
+
+func status(ctx context.Context) error {
+	migrator, closeDB, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	applied, err := migrator.Applied(ctx)
+	if err != nil {
+		return err
+	}
+	for _, m := range db.Migrations {
+		state := "pending"
+		if a, ok := applied[m.Version]; ok {
+			state = "applied " + a.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d  %-50s  %s\n", m.Version, m.Name, state)
+	}
+	return nil
+}
+
+func up(ctx context.Context) error {
+	migrator, closeDB, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	done, err := migrator.Up(ctx)
+	for _, m := range done {
+		log.Printf("applied %04d %s", m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(done) == 0 {
+		log.Println("nothing to migrate")
+	}
+	return nil
+}
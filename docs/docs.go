@@ -15,6 +15,256 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/appointments": {
+            "post": {
+                "description": "Book an appointment for one of the provider's services",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Appointments"
+                ],
+                "summary": "Create Appointment",
+                "parameters": [
+                    {
+                        "description": "Appointment",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.CreateAppointment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Appointments"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/appointments/{id}": {
+            "get": {
+                "description": "Get an appointment of the authenticated provider, with its notes and intake answers",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Appointments"
+                ],
+                "summary": "Get Appointment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Appointments"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/services": {
+            "post": {
+                "description": "Create a service; the Markdown description is stored with its sanitized HTML",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Services"
+                ],
+                "summary": "Create Service",
+                "parameters": [
+                    {
+                        "description": "Service",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.CreateService"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Services"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/services/order": {
+            "put": {
+                "description": "Set the order services are listed in: the services in service_ids come first, in that order, followed by the caller's other services in their current order. Returns every service of the caller in the new order.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Services"
+                ],
+                "summary": "Reorder Services",
+                "parameters": [
+                    {
+                        "description": "Service IDs in order",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ReorderServices"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Services"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/services/{id}": {
+            "get": {
+                "description": "Get a service of the authenticated provider",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Services"
+                ],
+                "summary": "Get Service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Services"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a service; omitted fields are left as they are",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Services"
+                ],
+                "summary": "Update Service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.UpdateService"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Services"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Problem"
+                        }
+                    }
+                }
+            }
+        },
         "/auth/login": {
             "post": {
                 "description": "Login with Firebase token and sync user data",
@@ -107,6 +357,696 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/public/providers/{provider_id}/appointments": {
+            "post": {
+                "description": "Book a slot from the public widget, answering the service's intake form",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Book Appointment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID or slug",
+                        "name": "provider_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Booking",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.PublicAppointment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Appointments"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/providers/{provider_id}/services": {
+            "get": {
+                "description": "List the public services of a provider that are active now, grouped by category in sort order; the services without a category come last, in a group with an empty name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "List Public Services",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID or slug",
+                        "name": "provider_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/catalog.Category"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apperrors.Code": {
+            "type": "string",
+            "enum": [
+                "INVALID_BODY",
+                "VALIDATION_FAILED",
+                "INVALID_QUERY",
+                "PAYLOAD_TOO_LARGE",
+                "UNAUTHORIZED",
+                "INVALID_TOKEN",
+                "FORBIDDEN",
+                "NOT_PROVIDER",
+                "ACCOUNT_BLOCKED",
+                "NOT_FOUND",
+                "APPOINTMENT_NOT_FOUND",
+                "SERVICE_NOT_FOUND",
+                "PROVIDER_NOT_FOUND",
+                "USER_NOT_FOUND",
+                "HOLD_NOT_FOUND",
+                "SLOT_TAKEN",
+                "OUTSIDE_BUSINESS_HOURS",
+                "APPOINTMENT_IN_PAST",
+                "SERVICE_NOT_OWNED",
+                "SERVICE_UNAVAILABLE",
+                "CUSTOMER_EMAIL_REQUIRED",
+                "TOO_MANY_BOOKINGS",
+                "HOLD_EXPIRED",
+                "NOT_DELETED",
+                "ALREADY_PROVIDER",
+                "INVALID_CURSOR",
+                "SLUG_TAKEN",
+                "INVALID_SLUG",
+                "UNKNOWN_BUSINESS_TYPE",
+                "NO_ESTABLISHMENT",
+                "RATE_LIMITED",
+                "CAPTCHA_REQUIRED",
+                "CAPTCHA_FAILED",
+                "CAPTCHA_UNAVAILABLE",
+                "IDEMPOTENCY_KEY_REUSED",
+                "IDEMPOTENCY_IN_PROGRESS",
+                "INTERNAL"
+            ],
+            "x-enum-varnames": [
+                "CodeInvalidBody",
+                "CodeValidationFailed",
+                "CodeInvalidQuery",
+                "CodeTooLarge",
+                "CodeUnauthorized",
+                "CodeInvalidToken",
+                "CodeForbidden",
+                "CodeNotProvider",
+                "CodeAccountBlocked",
+                "CodeNotFound",
+                "CodeAppointmentNotFound",
+                "CodeServiceNotFound",
+                "CodeProviderNotFound",
+                "CodeUserNotFound",
+                "CodeHoldNotFound",
+                "CodeSlotTaken",
+                "CodeOutsideBusinessHours",
+                "CodeInPast",
+                "CodeServiceNotOwned",
+                "CodeServiceUnavailable",
+                "CodeCustomerRequired",
+                "CodeTooManyBookings",
+                "CodeHoldExpired",
+                "CodeNotDeleted",
+                "CodeAlreadyProvider",
+                "CodeInvalidCursor",
+                "CodeSlugTaken",
+                "CodeInvalidSlug",
+                "CodeUnknownBusinessType",
+                "CodeNoEstablishment",
+                "CodeRateLimited",
+                "CodeCaptchaRequired",
+                "CodeCaptchaFailed",
+                "CodeCaptchaUnavailable",
+                "CodeIdempotencyKeyReused",
+                "CodeIdempotencyInProgress",
+                "CodeInternal"
+            ]
+        },
+        "apperrors.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "apperrors.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "$ref": "#/definitions/apperrors.Code"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apperrors.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "catalog.Category": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Services"
+                    }
+                }
+            }
+        },
+        "domain.Appointments": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_email": {
+                    "type": "string"
+                },
+                "customer_name": {
+                    "type": "string"
+                },
+                "customer_phone": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "duration_minutes": {
+                    "type": "integer"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "$ref": "#/definitions/domain.Notes"
+                },
+                "price": {
+                    "description": "Price is the service's price when the appointment was booked, so that\nlater price changes do not restate past revenue.",
+                    "type": "number"
+                },
+                "provider_id": {
+                    "type": "string"
+                },
+                "scheduled_at": {
+                    "type": "string"
+                },
+                "service_id": {
+                    "type": "string"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Description": {
+            "type": "object",
+            "properties": {
+                "html": {
+                    "type": "string"
+                },
+                "markdown": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.IntakeAnswer": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string"
+                },
+                "field_id": {
+                    "type": "string"
+                },
+                "question": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.IntakeField": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.IntakeFieldType"
+                }
+            }
+        },
+        "domain.IntakeFieldType": {
+            "type": "string",
+            "enum": [
+                "text",
+                "choice",
+                "checkbox",
+                "date"
+            ],
+            "x-enum-varnames": [
+                "IntakeText",
+                "IntakeChoice",
+                "IntakeCheckbox",
+                "IntakeDate"
+            ]
+        },
+        "domain.Notes": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.IntakeAnswer"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Services": {
+            "type": "object",
+            "properties": {
+                "active_from": {
+                    "description": "ActiveFrom and ActiveTo bound when a seasonal service is offered in the\nwidget, as [ActiveFrom, ActiveTo). Nil means no bound.",
+                    "type": "string"
+                },
+                "active_to": {
+                    "type": "string"
+                },
+                "category": {
+                    "description": "Category groups the service in the booking widget; empty is none.",
+                    "type": "string"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "$ref": "#/definitions/domain.Description"
+                },
+                "duration_minutes": {
+                    "type": "integer"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "icon_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "intake_form": {
+                    "description": "IntakeForm is asked of customers who book the service publicly.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.IntakeField"
+                    }
+                },
+                "price": {
+                    "type": "number"
+                },
+                "private": {
+                    "description": "Private services are hidden from the booking widget and can only be\nbooked from the dashboard.",
+                    "type": "boolean"
+                },
+                "provider_id": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "description": "SortOrder orders listings, lowest first, then by ID.",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.CreateAppointment": {
+            "type": "object",
+            "required": [
+                "scheduled_at",
+                "service_id"
+            ],
+            "properties": {
+                "customer_email": {
+                    "type": "string",
+                    "maxLength": 254
+                },
+                "customer_name": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "customer_phone": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "notes": {
+                    "$ref": "#/definitions/requests.Notes"
+                },
+                "scheduled_at": {
+                    "type": "string"
+                },
+                "service_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "confirmed",
+                        "completed",
+                        "cancelled",
+                        "no_show"
+                    ]
+                }
+            }
+        },
+        "requests.CreateService": {
+            "type": "object",
+            "required": [
+                "duration_minutes",
+                "title"
+            ],
+            "properties": {
+                "active_from": {
+                    "type": "string"
+                },
+                "active_to": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "color": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "duration_minutes": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 5
+                },
+                "icon_url": {
+                    "type": "string"
+                },
+                "intake_form": {
+                    "type": "array",
+                    "maxItems": 30,
+                    "items": {
+                        "$ref": "#/definitions/requests.IntakeField"
+                    }
+                },
+                "price": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "private": {
+                    "type": "boolean"
+                },
+                "sort_order": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "requests.IntakeAnswer": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "field_id": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "question": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "requests.IntakeField": {
+            "type": "object",
+            "required": [
+                "id",
+                "label",
+                "options",
+                "type"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "label": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "options": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "enum": [
+                        "text",
+                        "choice",
+                        "checkbox",
+                        "date"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.IntakeFieldType"
+                        }
+                    ]
+                }
+            }
+        },
+        "requests.Notes": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "$ref": "#/definitions/requests.IntakeAnswer"
+                    }
+                },
+                "text": {
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "requests.PublicAppointment": {
+            "type": "object",
+            "properties": {
+                "customer_email": {
+                    "type": "string",
+                    "maxLength": 254
+                },
+                "customer_name": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "customer_phone": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "hold_id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "$ref": "#/definitions/requests.Notes"
+                },
+                "scheduled_at": {
+                    "type": "string"
+                },
+                "service_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.ReorderServices": {
+            "type": "object",
+            "required": [
+                "service_ids"
+            ],
+            "properties": {
+                "service_ids": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "requests.UpdateService": {
+            "type": "object",
+            "properties": {
+                "active_from": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "active_to": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "category": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "color": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "duration_minutes": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 5
+                },
+                "icon_url": {
+                    "type": "string"
+                },
+                "intake_form": {
+                    "type": "array",
+                    "maxItems": 30,
+                    "items": {
+                        "$ref": "#/definitions/requests.IntakeField"
+                    }
+                },
+                "price": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "private": {
+                    "type": "boolean"
+                },
+                "sort_order": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
         }
     }
 }`
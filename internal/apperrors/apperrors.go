@@ -0,0 +1,149 @@
+// Package apperrors is the error model of the API. Handlers and middleware
+// attach errors to the request with Abort, and Middleware renders them as
+// RFC 7807 problem details carrying a stable Code, so clients branch on the
+// code rather than on the message.
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ServiceBookingApp/internal/domain"
+)
+
+// Code identifies an error for clients. Codes are part of the API: add new
+// ones, but never rename or reuse one.
+type Code string
+
+const (
+	CodeInvalidBody      Code = "INVALID_BODY"
+	CodeValidationFailed Code = "VALIDATION_FAILED"
+	CodeInvalidQuery     Code = "INVALID_QUERY"
+	CodeTooLarge         Code = "PAYLOAD_TOO_LARGE"
+
+	CodeUnauthorized   Code = "UNAUTHORIZED"
+	CodeInvalidToken   Code = "INVALID_TOKEN"
+	CodeForbidden      Code = "FORBIDDEN"
+	CodeNotProvider    Code = "NOT_PROVIDER"
+	CodeAccountBlocked Code = "ACCOUNT_BLOCKED"
+
+	CodeNotFound            Code = "NOT_FOUND"
+	CodeAppointmentNotFound Code = "APPOINTMENT_NOT_FOUND"
+	CodeServiceNotFound     Code = "SERVICE_NOT_FOUND"
+	CodeProviderNotFound    Code = "PROVIDER_NOT_FOUND"
+	CodeUserNotFound        Code = "USER_NOT_FOUND"
+	CodeHoldNotFound        Code = "HOLD_NOT_FOUND"
+
+	CodeSlotTaken            Code = "SLOT_TAKEN"
+	CodeOutsideBusinessHours Code = "OUTSIDE_BUSINESS_HOURS"
+	CodeInPast               Code = "APPOINTMENT_IN_PAST"
+	CodeServiceNotOwned      Code = "SERVICE_NOT_OWNED"
+	CodeServiceUnavailable   Code = "SERVICE_UNAVAILABLE"
+	CodeCustomerRequired     Code = "CUSTOMER_EMAIL_REQUIRED"
+	CodeTooManyBookings      Code = "TOO_MANY_BOOKINGS"
+	CodeHoldExpired          Code = "HOLD_EXPIRED"
+	CodeNotDeleted           Code = "NOT_DELETED"
+	CodeAlreadyProvider      Code = "ALREADY_PROVIDER"
+	CodeInvalidCursor        Code = "INVALID_CURSOR"
+	CodeSlugTaken            Code = "SLUG_TAKEN"
+	CodeInvalidSlug          Code = "INVALID_SLUG"
+	CodeUnknownBusinessType  Code = "UNKNOWN_BUSINESS_TYPE"
+	CodeNoEstablishment      Code = "NO_ESTABLISHMENT"
+
+	CodeRateLimited           Code = "RATE_LIMITED"
+	CodeCaptchaRequired       Code = "CAPTCHA_REQUIRED"
+	CodeCaptchaFailed         Code = "CAPTCHA_FAILED"
+	CodeCaptchaUnavailable    Code = "CAPTCHA_UNAVAILABLE"
+	CodeIdempotencyKeyReused  Code = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyInProgress Code = "IDEMPOTENCY_IN_PROGRESS"
+
+	CodeInternal Code = "INTERNAL"
+)
+
+// Error is an error the API reports to clients. Message is shown to them;
+// the wrapped cause is only logged.
+type Error struct {
+	Status  int
+	Code    Code
+	Message string
+	// Fields lists the invalid fields of a request that failed validation.
+	Fields []FieldError
+	cause  error
+}
+
+// FieldError is one invalid field of a request. Field is its JSON path,
+// e.g. "days[mon].ranges[1].start".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func New(status int, code Code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// Wrap returns an Error reporting message for cause.
+func Wrap(cause error, status int, code Code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message, cause: cause}
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.cause)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// InvalidBody reports a request body that could not be decoded.
+func InvalidBody(err error) *Error {
+	return Wrap(err, http.StatusBadRequest, CodeInvalidBody, err.Error())
+}
+
+// Invalid reports a request body that was decoded but broke the rules of
+// its fields.
+func Invalid(fields []FieldError) *Error {
+	return &Error{Status: http.StatusBadRequest, Code: CodeValidationFailed, Message: "request is invalid", Fields: fields}
+}
+
+// InvalidQuery reports a missing or malformed query parameter.
+func InvalidQuery(message string) *Error {
+	return New(http.StatusBadRequest, CodeInvalidQuery, message)
+}
+
+// NotFound turns a lookup error into a 404 with code when the document does
+// not exist. Any other error is returned as is and reported as internal.
+func NotFound(err error, code Code, message string) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return Wrap(err, http.StatusNotFound, code, message)
+	}
+	return err
+}
+
+var (
+	ErrUnauthorized = New(http.StatusUnauthorized, CodeUnauthorized, "unauthorized")
+	ErrForbidden    = New(http.StatusForbidden, CodeForbidden, "forbidden")
+)
+
+// From returns the Error to report for err. The sentinels of the domain
+// package get their own codes; anything else is internal, and its message,
+// which may come from the database driver, is not shown.
+func From(err error) *Error {
+	var e *Error
+	switch {
+	case errors.As(err, &e):
+		return e
+	case errors.Is(err, domain.ErrNotFound):
+		return Wrap(err, http.StatusNotFound, CodeNotFound, "not found")
+	case errors.Is(err, domain.ErrSlotTaken):
+		return Wrap(err, http.StatusConflict, CodeSlotTaken, domain.ErrSlotTaken.Error())
+	case errors.Is(err, domain.ErrInvalidCursor):
+		return Wrap(err, http.StatusBadRequest, CodeInvalidCursor, domain.ErrInvalidCursor.Error())
+	default:
+		return Wrap(err, http.StatusInternalServerError, CodeInternal, "internal server error")
+	}
+}
@@ -0,0 +1,92 @@
+package apperrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/:case", func(c *gin.Context) {
+		switch c.Param("case") {
+		case "typed":
+			Abort(c, fmt.Errorf("booking: %w", New(http.StatusUnprocessableEntity, CodeOutsideBusinessHours, "time is outside business hours")))
+		case "not-found":
+			Abort(c, NotFound(fmt.Errorf("get: %w", domain.ErrNotFound), CodeServiceNotFound, "service not found"))
+		case "slot":
+			Abort(c, domain.ErrSlotTaken)
+		case "internal":
+			Abort(c, errors.New("rpc error: code = Unavailable desc = firestore is down"))
+		case "written":
+			c.String(http.StatusTeapot, "short and stout")
+			Abort(c, errors.New("ignored"))
+		default:
+			c.Status(http.StatusNoContent)
+		}
+	})
+
+	get := func(path string) (*httptest.ResponseRecorder, Problem) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(w, req)
+		var p Problem
+		if w.Header().Get("Content-Type") == ContentType {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		}
+		return w, p
+	}
+
+	w, p := get("/typed")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, Problem{
+		Type:     "about:blank",
+		Title:    "Unprocessable Entity",
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   "time is outside business hours",
+		Instance: "/typed",
+		Code:     CodeOutsideBusinessHours,
+	}, p)
+
+	w, p = get("/not-found")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, CodeServiceNotFound, p.Code)
+
+	w, p = get("/slot")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, CodeSlotTaken, p.Code)
+
+	w, p = get("/internal")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, CodeInternal, p.Code)
+	assert.NotContains(t, w.Body.String(), "firestore", "causes are logged, not sent")
+
+	w, _ = get("/written")
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.Equal(t, "short and stout", w.Body.String())
+
+	w, _ = get("/ok")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestNotFound(t *testing.T) {
+	internal := errors.New("deadline exceeded")
+	assert.Same(t, internal, NotFound(internal, CodeUserNotFound, "user not found"), "only missing documents are 404s")
+
+	var e *Error
+	require.ErrorAs(t, NotFound(domain.ErrNotFound, CodeUserNotFound, "user not found"), &e)
+	assert.Equal(t, http.StatusNotFound, e.Status)
+	assert.ErrorIs(t, e, domain.ErrNotFound)
+}
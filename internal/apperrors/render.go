@@ -0,0 +1,62 @@
+package apperrors
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+// ContentType is the media type of problem details (RFC 7807).
+const ContentType = "application/problem+json"
+
+// Problem is the response body of every error.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     Code         `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// Abort attaches err to the request and stops the handler chain. Middleware
+// writes the response.
+func Abort(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
+
+// Middleware renders the last error attached with Abort, unless a response
+// was already written. It goes first on the engine so it sees the errors of
+// every other middleware.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		Render(c, c.Errors.Last().Err)
+	}
+}
+
+// Render writes err as problem details. Internal errors are logged with
+// their cause.
+func Render(c *gin.Context, err error) {
+	e := From(err)
+	if e.Status >= http.StatusInternalServerError {
+		log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
+	c.Header("Content-Type", ContentType)
+	c.Render(e.Status, render.JSON{Data: Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(e.Status),
+		Status:   e.Status,
+		Detail:   e.Message,
+		Instance: c.Request.URL.Path,
+		Code:     e.Code,
+		Errors:   e.Fields,
+	}})
+}
@@ -0,0 +1,123 @@
+// Package audit records who changed what. Repositories wrapped with the
+// decorators of this package write an AuditEntry after every successful
+// create, update and delete, whichever handler made it, using the actor that
+// Middleware puts in the request context.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+)
+
+// Actor is who made a request and from where. UID is empty for anonymous
+// requests such as public bookings.
+type Actor struct {
+	UID       string
+	IP        string
+	UserAgent string
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor of ctx, or the zero Actor for background work.
+func ActorFrom(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
+
+// ignoredFields change on every write and would only add noise to a diff.
+var ignoredFields = map[string]bool{"id": true, "updated_at": true}
+
+// Recorder writes audit entries. Failing to record never fails the mutation
+// that was already made; the error is logged instead.
+type Recorder struct {
+	repo domain.AuditRepository
+}
+
+func NewRecorder(repo domain.AuditRepository) *Recorder {
+	return &Recorder{repo: repo}
+}
+
+// Record stores an entry for a change of entityType from before to after,
+// either of which may be nil. Updates that change nothing are skipped.
+func (r *Recorder) Record(ctx context.Context, action, entityType, entityId, providerId string, before, after interface{}) {
+	d := Diff(before, after)
+	if len(d) == 0 && action == domain.AuditUpdate {
+		return
+	}
+	r.write(ctx, &domain.AuditEntry{
+		ProviderId: providerId,
+		Action:     action,
+		EntityType: entityType,
+		EntityId:   entityId,
+		Diff:       d,
+	})
+}
+
+func (r *Recorder) write(ctx context.Context, entry *domain.AuditEntry) {
+	actor := ActorFrom(ctx)
+	entry.ActorUID = actor.UID
+	entry.IP = actor.IP
+	entry.UserAgent = actor.UserAgent
+	// The entry outlives a cancelled request.
+	if _, err := r.repo.Create(context.WithoutCancel(ctx), entry); err != nil {
+		log.Printf("audit: recording %s %s %s: %v", entry.Action, entry.EntityType, entry.EntityId, err)
+	}
+}
+
+// Diff compares the JSON representations of before and after, as the API
+// shows them, and returns the fields that differ.
+func Diff(before, after interface{}) map[string]domain.AuditChange {
+	b, a := toMap(before), toMap(after)
+	diff := map[string]domain.AuditChange{}
+	for k, v := range b {
+		if ignoredFields[k] {
+			continue
+		}
+		if w, ok := a[k]; !ok || !reflect.DeepEqual(v, w) {
+			diff[k] = domain.AuditChange{Before: v, After: a[k]}
+		}
+	}
+	for k, w := range a {
+		if _, ok := b[k]; ok || ignoredFields[k] {
+			continue
+		}
+		diff[k] = domain.AuditChange{After: w}
+	}
+	return diff
+}
+
+func toMap(v interface{}) map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	json.Unmarshal(data, &m)
+	return m
+}
+
+// updateAction names an update of an existing document by what it did to
+// the soft-delete marker.
+func updateAction(deletedBefore, deletedAfter *time.Time) string {
+	switch {
+	case deletedBefore == nil && deletedAfter != nil:
+		return domain.AuditDelete
+	case deletedBefore != nil && deletedAfter == nil:
+		return domain.AuditRestore
+	default:
+		return domain.AuditUpdate
+	}
+}
@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/memory"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	before := &domain.Services{ProviderId: "p", Title: "Corte", Price: 100}
+	after := &domain.Services{ProviderId: "p", Title: "Corte largo", Price: 100}
+
+	assert.Equal(t, map[string]domain.AuditChange{
+		"title": {Before: "Corte", After: "Corte largo"},
+	}, Diff(before, after))
+
+	created := Diff(nil, after)
+	assert.Equal(t, domain.AuditChange{After: "Corte largo"}, created["title"])
+	assert.NotContains(t, created, "id")
+	assert.NotContains(t, created, "updated_at")
+}
+
+func TestRepositoriesRecordMutations(t *testing.T) {
+	store := memory.NewStore()
+	log := memory.NewAuditRepository(store)
+	recorder := NewRecorder(log)
+	services := NewServicesRepository(memory.NewServicesRepository(store), recorder)
+
+	ctx := WithActor(context.Background(), Actor{UID: "user-1", IP: "203.0.113.7", UserAgent: "test"})
+
+	id, err := services.Create(ctx, &domain.Services{ProviderId: "prov-1", Title: "Corte"})
+	require.NoError(t, err)
+
+	m, err := services.Get(ctx, id)
+	require.NoError(t, err)
+	require.NoError(t, services.Update(ctx, id, m), "a no-op update is not recorded")
+
+	m.Title = "Corte largo"
+	require.NoError(t, services.Update(ctx, id, m))
+
+	now := utils.Now()
+	m.DeletedAt = &now
+	require.NoError(t, services.Update(ctx, id, m))
+
+	m.DeletedAt = nil
+	require.NoError(t, services.Update(ctx, id, m))
+
+	entries, _, err := log.List(context.Background(), domain.AuditFilter{ProviderId: "prov-1", EntityId: id})
+	require.NoError(t, err)
+
+	var actions []string
+	for _, e := range entries {
+		actions = append([]string{e.Action}, actions...)
+		assert.Equal(t, "user-1", e.ActorUID)
+		assert.Equal(t, "203.0.113.7", e.IP)
+		assert.Equal(t, "test", e.UserAgent)
+		assert.Equal(t, EntityServices, e.EntityType)
+	}
+	assert.Equal(t, []string{domain.AuditCreate, domain.AuditUpdate, domain.AuditDelete, domain.AuditRestore}, actions)
+}
+
+func TestReorderRecordsMovedServices(t *testing.T) {
+	store := memory.NewStore()
+	log := memory.NewAuditRepository(store)
+	services := NewServicesRepository(memory.NewServicesRepository(store), NewRecorder(log))
+	ctx := context.Background()
+
+	first, err := services.Create(ctx, &domain.Services{ProviderId: "prov-1", Title: "Corte", SortOrder: 0})
+	require.NoError(t, err)
+	second, err := services.Create(ctx, &domain.Services{ProviderId: "prov-1", Title: "Barba", SortOrder: 1})
+	require.NoError(t, err)
+	third, err := services.Create(ctx, &domain.Services{ProviderId: "prov-1", Title: "Color", SortOrder: 2})
+	require.NoError(t, err)
+
+	_, err = services.Reorder(ctx, "prov-1", []string{second, first})
+	require.NoError(t, err)
+
+	entries, _, err := log.List(ctx, domain.AuditFilter{ProviderId: "prov-1", EntityType: EntityServices})
+	require.NoError(t, err)
+	moved := map[string]domain.AuditChange{}
+	for _, e := range entries {
+		if e.Action == domain.AuditUpdate {
+			moved[e.EntityId] = e.Diff["sort_order"]
+		}
+	}
+	assert.Len(t, moved, 2, "the service that kept its place is not recorded")
+	assert.NotContains(t, moved, third)
+	assert.EqualValues(t, 1, moved[first].After)
+	assert.EqualValues(t, 0, moved[second].After)
+}
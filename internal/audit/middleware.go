@@ -0,0 +1,22 @@
+package audit
+
+import (
+	"firebase.google.com/go/v4/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware puts the Actor of the request in its context. It goes after the
+// authentication middleware of a group, so the verified UID is known; on
+// public routes the actor is anonymous.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := Actor{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+		if u, ok := c.Get("user"); ok {
+			if token, ok := u.(*auth.Token); ok {
+				actor.UID = token.UID
+			}
+		}
+		c.Request = c.Request.WithContext(WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
@@ -0,0 +1,319 @@
+package audit
+
+import (
+	"context"
+	"errors"
+
+	"ServiceBookingApp/internal/domain"
+)
+
+// Entity types, named after their collections.
+const (
+	EntityAppointments = "appointments"
+	EntityServices     = "services"
+	EntityProviders    = "providers"
+	EntitySchedules    = "schedules"
+	EntityUsers        = "users"
+)
+
+// importEntry records a bulk import as a single entry instead of one per
+// document.
+func (r *Recorder) importEntry(ctx context.Context, entityType, providerId string, count int) {
+	r.write(ctx, &domain.AuditEntry{
+		ProviderId: providerId,
+		Action:     domain.AuditImport,
+		EntityType: entityType,
+		Diff:       map[string]domain.AuditChange{"count": {After: count}},
+	})
+}
+
+// lookup returns nil instead of domain.ErrNotFound, so the decorators can
+// treat a missing document as "no before state".
+func lookup[T any](m *T, err error) (*T, error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	return m, err
+}
+
+type AppointmentsRepository struct {
+	domain.AppointmentsRepository
+	recorder *Recorder
+}
+
+func NewAppointmentsRepository(repo domain.AppointmentsRepository, recorder *Recorder) *AppointmentsRepository {
+	return &AppointmentsRepository{AppointmentsRepository: repo, recorder: recorder}
+}
+
+func (r *AppointmentsRepository) Create(ctx context.Context, m *domain.Appointments) (string, error) {
+	id, err := r.AppointmentsRepository.Create(ctx, m)
+	if err != nil {
+		return "", err
+	}
+	r.recorder.Record(ctx, domain.AuditCreate, EntityAppointments, id, m.ProviderId, nil, m)
+	return id, nil
+}
+
+func (r *AppointmentsRepository) Update(ctx context.Context, id string, m *domain.Appointments) error {
+	before, err := lookup(r.AppointmentsRepository.GetIncludingDeleted(ctx, id))
+	if err != nil {
+		return err
+	}
+	if err := r.AppointmentsRepository.Update(ctx, id, m); err != nil {
+		return err
+	}
+	action := domain.AuditCreate
+	if before != nil {
+		action = updateAction(before.DeletedAt, m.DeletedAt)
+	}
+	r.recorder.Record(ctx, action, EntityAppointments, id, m.ProviderId, before, m)
+	return nil
+}
+
+func (r *AppointmentsRepository) Delete(ctx context.Context, id string) error {
+	before, err := lookup(r.AppointmentsRepository.GetIncludingDeleted(ctx, id))
+	if err != nil {
+		return err
+	}
+	if err := r.AppointmentsRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	if before != nil {
+		r.recorder.Record(ctx, domain.AuditDelete, EntityAppointments, id, before.ProviderId, before, nil)
+	}
+	return nil
+}
+
+func (r *AppointmentsRepository) Import(ctx context.Context, models []*domain.Appointments) error {
+	if err := r.AppointmentsRepository.Import(ctx, models); err != nil {
+		return err
+	}
+	if len(models) > 0 {
+		r.recorder.importEntry(ctx, EntityAppointments, models[0].ProviderId, len(models))
+	}
+	return nil
+}
+
+type ServicesRepository struct {
+	domain.ServicesRepository
+	recorder *Recorder
+}
+
+func NewServicesRepository(repo domain.ServicesRepository, recorder *Recorder) *ServicesRepository {
+	return &ServicesRepository{ServicesRepository: repo, recorder: recorder}
+}
+
+func (r *ServicesRepository) Create(ctx context.Context, m *domain.Services) (string, error) {
+	id, err := r.ServicesRepository.Create(ctx, m)
+	if err != nil {
+		return "", err
+	}
+	r.recorder.Record(ctx, domain.AuditCreate, EntityServices, id, m.ProviderId, nil, m)
+	return id, nil
+}
+
+func (r *ServicesRepository) Update(ctx context.Context, id string, m *domain.Services) error {
+	before, err := lookup(r.ServicesRepository.GetIncludingDeleted(ctx, id))
+	if err != nil {
+		return err
+	}
+	if err := r.ServicesRepository.Update(ctx, id, m); err != nil {
+		return err
+	}
+	action := domain.AuditCreate
+	if before != nil {
+		action = updateAction(before.DeletedAt, m.DeletedAt)
+	}
+	r.recorder.Record(ctx, action, EntityServices, id, m.ProviderId, before, m)
+	return nil
+}
+
+func (r *ServicesRepository) Delete(ctx context.Context, id string) error {
+	before, err := lookup(r.ServicesRepository.GetIncludingDeleted(ctx, id))
+	if err != nil {
+		return err
+	}
+	if err := r.ServicesRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	if before != nil {
+		r.recorder.Record(ctx, domain.AuditDelete, EntityServices, id, before.ProviderId, before, nil)
+	}
+	return nil
+}
+
+// Reorder records an update of every service whose position changed.
+func (r *ServicesRepository) Reorder(ctx context.Context, providerId string, ids []string) ([]*domain.Services, error) {
+	before := map[string]domain.Services{}
+	opts := domain.ListOptions{Limit: domain.MaxPageSize}
+	for {
+		page, next, err := r.ServicesRepository.List(ctx, opts, providerId)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range page {
+			before[m.ID] = *m
+		}
+		if next == "" {
+			break
+		}
+		opts.Cursor = next
+	}
+
+	ordered, err := r.ServicesRepository.Reorder(ctx, providerId, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range ordered {
+		old, ok := before[m.ID]
+		if !ok || old.SortOrder == m.SortOrder {
+			continue
+		}
+		r.recorder.Record(ctx, domain.AuditUpdate, EntityServices, m.ID, providerId, &old, m)
+	}
+	return ordered, nil
+}
+
+func (r *ServicesRepository) Import(ctx context.Context, models []*domain.Services) error {
+	if err := r.ServicesRepository.Import(ctx, models); err != nil {
+		return err
+	}
+	if len(models) > 0 {
+		r.recorder.importEntry(ctx, EntityServices, models[0].ProviderId, len(models))
+	}
+	return nil
+}
+
+// ProvidersRepository scopes the entries of a provider to the provider
+// itself.
+type ProvidersRepository struct {
+	domain.ProvidersRepository
+	recorder *Recorder
+}
+
+func NewProvidersRepository(repo domain.ProvidersRepository, recorder *Recorder) *ProvidersRepository {
+	return &ProvidersRepository{ProvidersRepository: repo, recorder: recorder}
+}
+
+func (r *ProvidersRepository) Create(ctx context.Context, m *domain.Providers) (string, error) {
+	id, err := r.ProvidersRepository.Create(ctx, m)
+	if err != nil {
+		return "", err
+	}
+	r.recorder.Record(ctx, domain.AuditCreate, EntityProviders, id, id, nil, m)
+	return id, nil
+}
+
+func (r *ProvidersRepository) Update(ctx context.Context, id string, m *domain.Providers) error {
+	before, err := lookup(r.ProvidersRepository.GetIncludingDeleted(ctx, id))
+	if err != nil {
+		return err
+	}
+	if err := r.ProvidersRepository.Update(ctx, id, m); err != nil {
+		return err
+	}
+	action := domain.AuditCreate
+	if before != nil {
+		action = updateAction(before.DeletedAt, m.DeletedAt)
+	}
+	r.recorder.Record(ctx, action, EntityProviders, id, id, before, m)
+	return nil
+}
+
+func (r *ProvidersRepository) Delete(ctx context.Context, id string) error {
+	before, err := lookup(r.ProvidersRepository.GetIncludingDeleted(ctx, id))
+	if err != nil {
+		return err
+	}
+	if err := r.ProvidersRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	if before != nil {
+		r.recorder.Record(ctx, domain.AuditDelete, EntityProviders, id, id, before, nil)
+	}
+	return nil
+}
+
+type SchedulesRepository struct {
+	domain.SchedulesRepository
+	recorder *Recorder
+}
+
+func NewSchedulesRepository(repo domain.SchedulesRepository, recorder *Recorder) *SchedulesRepository {
+	return &SchedulesRepository{SchedulesRepository: repo, recorder: recorder}
+}
+
+func (r *SchedulesRepository) Upsert(ctx context.Context, s *domain.Schedule) error {
+	before, err := r.SchedulesRepository.GetByProvider(ctx, s.ProviderId, s.Type)
+	if err != nil {
+		return err
+	}
+	if err := r.SchedulesRepository.Upsert(ctx, s); err != nil {
+		return err
+	}
+	action := domain.AuditCreate
+	if before != nil {
+		action = domain.AuditUpdate
+	}
+	r.recorder.Record(ctx, action, EntitySchedules, s.ID, s.ProviderId, before, s)
+	return nil
+}
+
+// UsersRepository scopes the entries of a user to the provider the user
+// owns, if any.
+type UsersRepository struct {
+	domain.UsersRepository
+	providers domain.ProvidersRepository
+	recorder  *Recorder
+}
+
+func NewUsersRepository(repo domain.UsersRepository, providers domain.ProvidersRepository, recorder *Recorder) *UsersRepository {
+	return &UsersRepository{UsersRepository: repo, providers: providers, recorder: recorder}
+}
+
+func (r *UsersRepository) providerOf(ctx context.Context, userId string) string {
+	provider, err := r.providers.GetByUserId(ctx, userId)
+	if err != nil || provider == nil {
+		return ""
+	}
+	return provider.ID
+}
+
+func (r *UsersRepository) Create(ctx context.Context, m *domain.Users) (string, error) {
+	id, err := r.UsersRepository.Create(ctx, m)
+	if err != nil {
+		return "", err
+	}
+	r.recorder.Record(ctx, domain.AuditCreate, EntityUsers, id, r.providerOf(ctx, id), nil, m)
+	return id, nil
+}
+
+func (r *UsersRepository) Update(ctx context.Context, id string, m *domain.Users) error {
+	before, err := lookup(r.UsersRepository.Get(ctx, id))
+	if err != nil {
+		return err
+	}
+	if err := r.UsersRepository.Update(ctx, id, m); err != nil {
+		return err
+	}
+	action := domain.AuditCreate
+	if before != nil {
+		action = updateAction(before.DeletedAt, m.DeletedAt)
+	}
+	r.recorder.Record(ctx, action, EntityUsers, id, r.providerOf(ctx, id), before, m)
+	return nil
+}
+
+func (r *UsersRepository) Delete(ctx context.Context, id string) error {
+	before, err := lookup(r.UsersRepository.Get(ctx, id))
+	if err != nil {
+		return err
+	}
+	if err := r.UsersRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	if before != nil {
+		r.recorder.Record(ctx, domain.AuditDelete, EntityUsers, id, r.providerOf(ctx, id), before, nil)
+	}
+	return nil
+}
@@ -2,11 +2,13 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
 	"strings"
 
+	"ServiceBookingApp/internal/apperrors"
 	"ServiceBookingApp/internal/domain"
 	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
@@ -45,17 +47,13 @@ func AuthMiddleware(service AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Missing Authorization header",
-			})
+			apperrors.Abort(c, apperrors.New(http.StatusUnauthorized, apperrors.CodeUnauthorized, "missing Authorization header"))
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenString == authHeader {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid Authorization header format",
-			})
+			apperrors.Abort(c, apperrors.New(http.StatusUnauthorized, apperrors.CodeUnauthorized, "invalid Authorization header format"))
 			return
 		}
 
@@ -74,11 +72,10 @@ func AuthMiddleware(service AuthService) gin.HandlerFunc {
 
 		token, err := service.VerifyIDToken(context.Background(), tokenString)
 		if err != nil {
+			// Why verification failed is logged, not sent: it can describe
+			// the token or the key set to whoever presented it.
 			log.Printf("Token verification failed: %v", err)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error":   "Invalid token",
-				"details": err.Error(),
-			})
+			apperrors.Abort(c, apperrors.New(http.StatusUnauthorized, apperrors.CodeInvalidToken, "invalid token"))
 			return
 		}
 
@@ -92,25 +89,29 @@ func UserActiveMiddleware(repo domain.UsersRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userTokenInterface, exists := c.Get("user")
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+			apperrors.Abort(c, apperrors.ErrUnauthorized)
 			return
 		}
 
 		userToken, ok := userTokenInterface.(*auth.Token)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Invalid user token type"})
+			apperrors.Abort(c, errors.New("invalid user token type"))
 			return
 		}
 		uid := userToken.UID
 
 		user, err := repo.Get(c.Request.Context(), uid)
+		if errors.Is(err, domain.ErrNotFound) {
+			apperrors.Abort(c, apperrors.New(http.StatusForbidden, apperrors.CodeUserNotFound, "user profile not found"))
+			return
+		}
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "User profile not found or access denied"})
+			apperrors.Abort(c, err)
 			return
 		}
 
 		if user.IsActive != nil && !*user.IsActive {
-			c.AbortWithStatusJSON(http.StatusPaymentRequired, gin.H{"error": "Account is blocked"})
+			apperrors.Abort(c, apperrors.New(http.StatusPaymentRequired, apperrors.CodeAccountBlocked, "account is blocked"))
 			return
 		}
 
@@ -118,3 +119,27 @@ func UserActiveMiddleware(repo domain.UsersRepository) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// IsAdmin reports whether the user loaded by UserActiveMiddleware has the
+// admin role.
+func IsAdmin(c *gin.Context) bool {
+	user, ok := c.Get("user_data")
+	if !ok {
+		return false
+	}
+	u, ok := user.(*domain.Users)
+	return ok && u.RoleId == "admin"
+}
+
+// IncludeDeleted reads the include_deleted query flag. Only admins may set
+// it; for anyone else it aborts with 403 and returns ok=false.
+func IncludeDeleted(c *gin.Context) (include bool, ok bool) {
+	if c.Query("include_deleted") != "true" {
+		return false, true
+	}
+	if !IsAdmin(c) {
+		apperrors.Abort(c, apperrors.New(http.StatusForbidden, apperrors.CodeForbidden, "include_deleted is only available to admins"))
+		return false, false
+	}
+	return true, true
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"net/http"
+
+	"ServiceBookingApp/internal/apperrors"
+	"ServiceBookingApp/internal/domain"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrNotProvider is returned by Policy.CallerProvider when the caller has no
+// live provider.
+var ErrNotProvider = apperrors.New(http.StatusForbidden, apperrors.CodeNotProvider, "must be a provider")
+
+// callerProviderKey caches the caller's provider in the gin context, so a
+// request looks it up at most once however many checks it runs.
+const callerProviderKey = "caller_provider"
+
+// Policy is the single place that decides whether the caller may act on a
+// resource. Tenancy is strict: documents owned by a provider (appointments,
+// services, schedules and the provider itself) are only reachable by the
+// user that owns that provider, and a user record only by that user. Roles
+// do not widen it, since every new user starts as "admin" of their own
+// provider.
+//
+// The Authorize methods abort with a 403 (or 401/500) error the request when
+// access is denied, so handlers just return when they get false.
+type Policy struct {
+	providers domain.ProvidersRepository
+}
+
+func NewPolicy(providers domain.ProvidersRepository) *Policy {
+	return &Policy{providers: providers}
+}
+
+// CallerUID returns the UID verified by AuthMiddleware, or "".
+func CallerUID(c *gin.Context) string {
+	u, ok := c.Get("user")
+	if !ok {
+		return ""
+	}
+	token, ok := u.(*auth.Token)
+	if !ok {
+		return ""
+	}
+	return token.UID
+}
+
+// CallerProvider returns the live provider owned by the caller, or
+// ErrNotProvider.
+func (p *Policy) CallerProvider(c *gin.Context) (*domain.Providers, error) {
+	if cached, ok := c.Get(callerProviderKey); ok {
+		return cached.(*domain.Providers), nil
+	}
+	uid := CallerUID(c)
+	if uid == "" {
+		return nil, ErrNotProvider
+	}
+	provider, err := p.providers.GetByUserId(c.Request.Context(), uid)
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		return nil, ErrNotProvider
+	}
+	c.Set(callerProviderKey, provider)
+	return provider, nil
+}
+
+// RequireProvider returns the caller's provider, or aborts with 403 and
+// returns false when the caller has none.
+func (p *Policy) RequireProvider(c *gin.Context) (*domain.Providers, bool) {
+	provider, err := p.CallerProvider(c)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return nil, false
+	}
+	return provider, true
+}
+
+// AuthorizeProvider allows the request when the caller owns providerId.
+func (p *Policy) AuthorizeProvider(c *gin.Context, providerId string) bool {
+	provider, ok := p.RequireProvider(c)
+	if !ok {
+		return false
+	}
+	if provider.ID != providerId {
+		apperrors.Abort(c, apperrors.ErrForbidden)
+		return false
+	}
+	return true
+}
+
+// AuthorizeUser allows the request when the caller is userId. It needs no
+// lookups, so it is a plain function rather than a Policy method.
+func AuthorizeUser(c *gin.Context, userId string) bool {
+	uid := CallerUID(c)
+	if uid == "" {
+		apperrors.Abort(c, apperrors.ErrUnauthorized)
+		return false
+	}
+	if uid != userId {
+		apperrors.Abort(c, apperrors.ErrForbidden)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,261 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"ServiceBookingApp/internal/apperrors"
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+)
+
+var (
+	ErrServiceRequired      = apperrors.New(http.StatusBadRequest, apperrors.CodeInvalidBody, "service_id is required")
+	ErrInvalidService       = apperrors.New(http.StatusBadRequest, apperrors.CodeServiceNotFound, "invalid service_id")
+	ErrServiceNotOwned      = apperrors.New(http.StatusBadRequest, apperrors.CodeServiceNotOwned, "service does not belong to provider")
+	ErrServiceUnavailable   = apperrors.New(http.StatusUnprocessableEntity, apperrors.CodeServiceUnavailable, "service is not offered at that time")
+	ErrInPast               = apperrors.New(http.StatusBadRequest, apperrors.CodeInPast, "cannot create appointment in the past")
+	ErrSlotTaken            = domain.ErrSlotTaken
+	ErrOutsideBusinessHours = apperrors.New(http.StatusUnprocessableEntity, apperrors.CodeOutsideBusinessHours, "time is outside business hours")
+	ErrAvailabilityCheck    = apperrors.New(http.StatusInternalServerError, apperrors.CodeInternal, "failed to check availability")
+	ErrCustomerRequired     = apperrors.New(http.StatusBadRequest, apperrors.CodeCustomerRequired, "customer_email is required")
+	ErrTooManyBookings      = apperrors.New(http.StatusConflict, apperrors.CodeTooManyBookings, "customer already has too many upcoming appointments")
+	ErrHoldNotFound         = apperrors.New(http.StatusNotFound, apperrors.CodeHoldNotFound, "hold not found")
+	ErrHoldExpired          = apperrors.New(http.StatusGone, apperrors.CodeHoldExpired, "hold has expired")
+)
+
+// HoldDuration is how long a hold keeps its slot.
+const HoldDuration = 10 * time.Minute
+
+// Booker holds the validation and conflict rules shared by every entry point
+// that writes appointments (dashboard, public widget, CalDAV).
+type Booker struct {
+	appointmentsRepo domain.AppointmentsRepository
+	servicesRepo     domain.ServicesRepository
+	holdsRepo        domain.HoldsRepository
+	schedulesRepo    domain.SchedulesRepository
+	// publicOnly hides private services and services outside their active
+	// range, as customers see them.
+	publicOnly bool
+}
+
+// NewBooker returns a Booker. With a nil holdsRepo holds do not take
+// slots, and with a nil schedulesRepo bookings are not held to business
+// hours. The provider's own entry points, the dashboard and CalDAV, pass
+// holds but no schedules: they respect customers' holds yet may book
+// outside business hours.
+func NewBooker(appointmentsRepo domain.AppointmentsRepository, servicesRepo domain.ServicesRepository, holdsRepo domain.HoldsRepository, schedulesRepo domain.SchedulesRepository) *Booker {
+	return &Booker{
+		appointmentsRepo: appointmentsRepo,
+		servicesRepo:     servicesRepo,
+		holdsRepo:        holdsRepo,
+		schedulesRepo:    schedulesRepo,
+	}
+}
+
+// PublicOnly returns a copy of b for customers: private services cannot be
+// booked, and the others only while they are active.
+func (b *Booker) PublicOnly() *Booker {
+	public := *b
+	public.publicOnly = true
+	return &public
+}
+
+// Prepare resolves the service of m, copies its denormalized fields and
+// checks that the requested time is not in the past, falls within business
+// hours and does not overlap an existing appointment or hold of the same
+// provider. m.ID is ignored when
+// looking for overlaps, so an appointment can be moved within its own slot.
+func (b *Booker) Prepare(ctx context.Context, providerId string, m *domain.Appointments) error {
+	return b.prepare(ctx, providerId, m, "")
+}
+
+// PrepareHeld is Prepare for the booking that replaces the hold holdId: m
+// takes the service and time of the hold, and the hold's own slot does not
+// count as taken.
+func (b *Booker) PrepareHeld(ctx context.Context, providerId string, m *domain.Appointments, holdId string) error {
+	hold, err := b.holdsRepo.Get(ctx, holdId)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && hold.ProviderId != providerId) {
+		return ErrHoldNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrAvailabilityCheck, err)
+	}
+	if hold.Expired(utils.Now()) {
+		return ErrHoldExpired
+	}
+	m.ServiceId = hold.ServiceId
+	m.ScheduledAt = hold.ScheduledAt
+	return b.prepare(ctx, providerId, m, holdId)
+}
+
+// Hold reserves the slot of m for HoldDuration after the checks of
+// Prepare.
+func (b *Booker) Hold(ctx context.Context, providerId string, m *domain.Appointments) (*domain.Hold, error) {
+	if err := b.Prepare(ctx, providerId, m); err != nil {
+		return nil, err
+	}
+	hold := &domain.Hold{
+		ProviderId:      providerId,
+		ServiceId:       m.ServiceId,
+		ScheduledAt:     m.ScheduledAt,
+		DurationMinutes: m.DurationMinutes,
+		ExpiresAt:       utils.Now().Add(HoldDuration),
+	}
+	id, err := b.holdsRepo.Create(ctx, hold)
+	if err != nil {
+		return nil, err
+	}
+	hold.ID = id
+	return hold, nil
+}
+
+func (b *Booker) prepare(ctx context.Context, providerId string, m *domain.Appointments, holdId string) error {
+	if m.ServiceId == "" {
+		return ErrServiceRequired
+	}
+	service, err := b.servicesRepo.Get(ctx, m.ServiceId)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && service == nil) {
+		return ErrInvalidService
+	}
+	if err != nil {
+		return err
+	}
+	if service.ProviderId != providerId {
+		return ErrServiceNotOwned
+	}
+	if b.publicOnly && service.Private {
+		return ErrInvalidService
+	}
+	if b.publicOnly && !service.OfferedAt(m.ScheduledAt) {
+		return ErrServiceUnavailable
+	}
+
+	m.ProviderId = providerId
+	m.DurationMinutes = service.DurationMinutes
+	if m.DurationMinutes == 0 {
+		m.DurationMinutes = 30
+	}
+	m.ServiceName = service.Title
+	m.Price = service.Price
+
+	now := utils.Now()
+	if m.ScheduledAt.Before(now.Add(-5 * time.Minute)) {
+		return ErrInPast
+	}
+
+	if b.schedulesRepo != nil {
+		schedule, err := b.schedulesRepo.GetByProvider(ctx, providerId, domain.ScheduleTypeGlobal)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrAvailabilityCheck, err)
+		}
+		if err := CheckBusinessHours(schedule, m); err != nil {
+			return err
+		}
+	}
+
+	return b.checkConflicts(ctx, m, holdId)
+}
+
+// CheckConflicts returns ErrSlotTaken when m overlaps another appointment or
+// a hold of its provider on the same day.
+func (b *Booker) CheckConflicts(ctx context.Context, m *domain.Appointments) error {
+	return b.checkConflicts(ctx, m, "")
+}
+
+func (b *Booker) checkConflicts(ctx context.Context, m *domain.Appointments, holdId string) error {
+	busy, err := b.busy(ctx, m.ProviderId, m.ScheduledAt, m.ID, holdId)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrAvailabilityCheck, err)
+	}
+
+	start := m.ScheduledAt
+	end := start.Add(time.Duration(m.DurationMinutes) * time.Minute)
+	for _, r := range busy {
+		if start.Before(r.end) && end.After(r.start) {
+			return ErrSlotTaken
+		}
+	}
+	return nil
+}
+
+type interval struct {
+	start, end time.Time
+}
+
+// busy returns the time taken on the day of date, in its location, by the
+// provider's appointments other than skipAppointment and by its live holds
+// other than skipHold.
+func (b *Booker) busy(ctx context.Context, providerId string, date time.Time, skipAppointment, skipHold string) ([]interval, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+
+	appointments, err := b.appointmentsRepo.ListByDate(ctx, startOfDay, providerId)
+	if err != nil {
+		return nil, err
+	}
+
+	var busy []interval
+	var serviceDurations map[string]int
+
+	for _, existing := range appointments {
+		if skipAppointment != "" && existing.ID == skipAppointment {
+			continue
+		}
+
+		dur := existing.DurationMinutes
+		if dur == 0 {
+			if serviceDurations == nil {
+				allServices, _, _ := b.servicesRepo.List(ctx, domain.ListOptions{Limit: domain.MaxPageSize}, providerId)
+				serviceDurations = make(map[string]int)
+				for _, s := range allServices {
+					serviceDurations[s.ID] = s.DurationMinutes
+				}
+			}
+			dur = serviceDurations[existing.ServiceId]
+			if dur == 0 {
+				dur = 60
+			}
+		}
+		busy = append(busy, interval{existing.ScheduledAt, existing.ScheduledAt.Add(time.Duration(dur) * time.Minute)})
+	}
+
+	if b.holdsRepo == nil {
+		return busy, nil
+	}
+	holds, err := b.holdsRepo.ListByDate(ctx, startOfDay, providerId, utils.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range holds {
+		if h.ID == skipHold {
+			continue
+		}
+		busy = append(busy, interval{h.ScheduledAt, h.ScheduledAt.Add(time.Duration(h.DurationMinutes) * time.Minute)})
+	}
+	return busy, nil
+}
+
+// CheckCustomerLimit returns ErrTooManyBookings when the customer of m, by
+// email, already has max confirmed upcoming appointments with its provider.
+func (b *Booker) CheckCustomerLimit(ctx context.Context, m *domain.Appointments, max int) error {
+	if m.CustomerEmail == "" {
+		return ErrCustomerRequired
+	}
+	upcoming, _, err := b.appointmentsRepo.List(ctx, domain.AppointmentsFilter{
+		ListOptions: domain.ListOptions{Limit: max},
+		ProviderId:  m.ProviderId,
+		Type:        "upcoming",
+		Status:      domain.StatusConfirmed,
+		Customer:    m.CustomerEmail,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrAvailabilityCheck, err)
+	}
+	if len(upcoming) >= max {
+		return ErrTooManyBookings
+	}
+	return nil
+}
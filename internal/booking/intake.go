@@ -0,0 +1,96 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"ServiceBookingApp/internal/apperrors"
+	"ServiceBookingApp/internal/domain"
+)
+
+// CheckIntake checks the answers in the notes of m, once prepared, against
+// the intake form of its service and replaces them with the answers to
+// store: in the order of the form, labelled with each field's current
+// label. Services without a form take any answers. Broken rules are a
+// VALIDATION_FAILED error with the fields keyed by id, e.g.
+// notes.answers[allergies].
+func (b *Booker) CheckIntake(ctx context.Context, m *domain.Appointments) error {
+	service, err := b.servicesRepo.Get(ctx, m.ServiceId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return ErrInvalidService
+	}
+	if err != nil {
+		return err
+	}
+	answers, invalid := validateIntake(service.IntakeForm, m.Notes.Answers)
+	if len(invalid) > 0 {
+		return apperrors.Invalid(invalid)
+	}
+	m.Notes.Answers = answers
+	return nil
+}
+
+// validateIntake returns answers as they are stored for form, and the
+// fields that break it. Answers are matched to fields by FieldId.
+func validateIntake(form []domain.IntakeField, answers []domain.IntakeAnswer) ([]domain.IntakeAnswer, []apperrors.FieldError) {
+	if len(form) == 0 {
+		return answers, nil
+	}
+
+	var invalid []apperrors.FieldError
+	fail := func(id, message string) {
+		invalid = append(invalid, apperrors.FieldError{Field: fmt.Sprintf("notes.answers[%s]", id), Message: message})
+	}
+
+	given := make(map[string]string, len(answers))
+	for i, a := range answers {
+		switch {
+		case a.FieldId == "":
+			invalid = append(invalid, apperrors.FieldError{Field: fmt.Sprintf("notes.answers[%d].field_id", i), Message: "is required"})
+		case !slices.ContainsFunc(form, func(f domain.IntakeField) bool { return f.Id == a.FieldId }):
+			fail(a.FieldId, "is not a field of the intake form")
+		default:
+			if _, dup := given[a.FieldId]; dup {
+				fail(a.FieldId, "is answered more than once")
+			}
+			given[a.FieldId] = strings.TrimSpace(a.Answer)
+		}
+	}
+
+	var stored []domain.IntakeAnswer
+	for _, f := range form {
+		answer := given[f.Id]
+		if answer == "" || (f.Type == domain.IntakeCheckbox && answer == "false") {
+			if f.Required {
+				fail(f.Id, "is required")
+				continue
+			}
+			if answer == "" {
+				continue
+			}
+		}
+		switch f.Type {
+		case domain.IntakeChoice:
+			if !slices.Contains(f.Options, answer) {
+				fail(f.Id, "must be one of: "+strings.Join(f.Options, ", "))
+				continue
+			}
+		case domain.IntakeCheckbox:
+			if answer != "true" && answer != "false" {
+				fail(f.Id, "must be true or false")
+				continue
+			}
+		case domain.IntakeDate:
+			if _, err := time.Parse(time.DateOnly, answer); err != nil {
+				fail(f.Id, "must be a date as YYYY-MM-DD")
+				continue
+			}
+		}
+		stored = append(stored, domain.IntakeAnswer{FieldId: f.Id, Question: f.Label, Answer: answer})
+	}
+	return stored, invalid
+}
@@ -0,0 +1,97 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+)
+
+var weekdayKeys = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// slotStep is how far apart consecutive slot start times are.
+const slotStep = 30 * time.Minute
+
+// CheckBusinessHours returns ErrOutsideBusinessHours unless m fits in one of
+// the open ranges of its day in schedule, reading m.ScheduledAt in its own
+// location as AvailableSlots reads its date. A provider without a schedule
+// has not set business hours, so nothing is checked.
+func CheckBusinessHours(schedule *domain.Schedule, m *domain.Appointments) error {
+	if schedule == nil || len(schedule.Days) == 0 {
+		return nil
+	}
+	start := m.ScheduledAt
+	end := start.Add(time.Duration(m.DurationMinutes) * time.Minute)
+	daySchedule, ok := schedule.Days[weekdayKeys[start.Weekday()]]
+	if !ok || !daySchedule.Enabled {
+		return ErrOutsideBusinessHours
+	}
+	for _, workRange := range daySchedule.Ranges {
+		var startH, startM, endH, endM int
+		fmt.Sscanf(workRange.Start, "%d:%d", &startH, &startM)
+		fmt.Sscanf(workRange.End, "%d:%d", &endH, &endM)
+		open := time.Date(start.Year(), start.Month(), start.Day(), startH, startM, 0, 0, start.Location())
+		closing := time.Date(start.Year(), start.Month(), start.Day(), endH, endM, 0, 0, start.Location())
+		if !start.Before(open) && !end.After(closing) {
+			return nil
+		}
+	}
+	return ErrOutsideBusinessHours
+}
+
+// AvailableSlots returns the start times ("15:04") on date at which service
+// fits in the provider's schedule without overlapping an appointment or a
+// hold. date is read in its own location. A nil schedule has no slots, and
+// a PublicOnly Booker leaves out the slots outside the service's active
+// range.
+func (b *Booker) AvailableSlots(ctx context.Context, service *domain.Services, schedule *domain.Schedule, date time.Time) ([]string, error) {
+	slots := []string{}
+	if schedule == nil || len(schedule.Days) == 0 {
+		return slots, nil
+	}
+	daySchedule, ok := schedule.Days[weekdayKeys[date.Weekday()]]
+	if !ok || !daySchedule.Enabled {
+		return slots, nil
+	}
+
+	busySlots, err := b.busy(ctx, service.ProviderId, date, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	dur := service.DurationMinutes
+	if dur <= 0 {
+		dur = 30
+	}
+
+	for _, workRange := range daySchedule.Ranges {
+		var startH, startM, endH, endM int
+		fmt.Sscanf(workRange.Start, "%d:%d", &startH, &startM)
+		fmt.Sscanf(workRange.End, "%d:%d", &endH, &endM)
+
+		currentSlot := time.Date(date.Year(), date.Month(), date.Day(), startH, startM, 0, 0, date.Location())
+		rangeEnd := time.Date(date.Year(), date.Month(), date.Day(), endH, endM, 0, 0, date.Location())
+
+		for currentSlot.Before(rangeEnd) {
+			slotEnd := currentSlot.Add(time.Duration(dur) * time.Minute)
+			if slotEnd.After(rangeEnd) {
+				break
+			}
+
+			isBusy := false
+			for _, busy := range busySlots {
+				if currentSlot.Before(busy.end) && slotEnd.After(busy.start) {
+					isBusy = true
+					break
+				}
+			}
+			if !isBusy && (!b.publicOnly || service.OfferedAt(currentSlot)) {
+				slots = append(slots, currentSlot.Format("15:04"))
+			}
+
+			currentSlot = currentSlot.Add(slotStep)
+		}
+	}
+	return slots, nil
+}
@@ -0,0 +1,48 @@
+package captcha
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ServiceBookingApp/internal/apperrors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiteVerifierMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "s3cret", r.PostForm.Get("secret"))
+		assert.Equal(t, "10.0.0.1", r.PostForm.Get("remoteip"))
+		if r.PostForm.Get("response") == "good" {
+			w.Write([]byte(`{"success": true}`))
+			return
+		}
+		w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-response"]}`))
+	}))
+	defer provider.Close()
+
+	r := gin.New()
+	r.Use(apperrors.Middleware())
+	r.POST("/book", Middleware(NewSiteVerifier(provider.URL, "s3cret")), func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	post := func(token string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/book", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if token != "" {
+			req.Header.Set(TokenHeader, token)
+		}
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, post("good"))
+	assert.Equal(t, http.StatusForbidden, post("bad"))
+	assert.Equal(t, http.StatusBadRequest, post(""))
+}
@@ -0,0 +1,66 @@
+// Package catalog builds the service catalog customers see in the booking
+// widget: the provider's listed services, grouped by category.
+package catalog
+
+import (
+	"context"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+)
+
+// Category is a group of services in the widget. Name is empty for the
+// services without a category.
+type Category struct {
+	Name     string             `json:"name"`
+	Services []*domain.Services `json:"services"`
+}
+
+// Listed returns every service of the provider the widget shows at now, in
+// sort order. It pages through the repository, so it is not capped at one
+// page.
+func Listed(ctx context.Context, repo domain.ServicesRepository, providerId string, now time.Time) ([]*domain.Services, error) {
+	var listed []*domain.Services
+	opts := domain.ListOptions{Limit: domain.MaxPageSize}
+	for {
+		page, next, err := repo.List(ctx, opts, providerId)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range page {
+			if s.Listed(now) {
+				listed = append(listed, s)
+			}
+		}
+		if next == "" {
+			return listed, nil
+		}
+		opts.Cursor = next
+	}
+}
+
+// Group groups services by category. Categories come in the order of
+// their first service and keep the order of services within them; the
+// services without a category come last.
+func Group(services []*domain.Services) []Category {
+	categories := []Category{}
+	index := map[string]int{}
+	var uncategorized []*domain.Services
+	for _, s := range services {
+		if s.Category == "" {
+			uncategorized = append(uncategorized, s)
+			continue
+		}
+		i, ok := index[s.Category]
+		if !ok {
+			i = len(categories)
+			index[s.Category] = i
+			categories = append(categories, Category{Name: s.Category})
+		}
+		categories[i].Services = append(categories[i].Services, s)
+	}
+	if len(uncategorized) > 0 {
+		categories = append(categories, Category{Services: uncategorized})
+	}
+	return categories
+}
@@ -0,0 +1,62 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListed(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewServicesRepository(memory.NewStore())
+	now := time.Date(2030, 6, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.AddDate(0, -1, 0), now.AddDate(0, 1, 0)
+
+	// More than a page, so Listed has to follow the cursor.
+	for i := 0; i < domain.MaxPageSize+5; i++ {
+		_, err := repo.Create(ctx, &domain.Services{ProviderId: "p1", Title: fmt.Sprintf("s%d", i), SortOrder: i})
+		require.NoError(t, err)
+	}
+	for _, s := range []*domain.Services{
+		{ProviderId: "p1", Title: "private", Private: true},
+		{ProviderId: "p1", Title: "ended", ActiveTo: &past},
+		{ProviderId: "p1", Title: "upcoming", ActiveFrom: &future},
+		{ProviderId: "p1", Title: "seasonal", ActiveFrom: &past, ActiveTo: &future, SortOrder: 1000},
+		{ProviderId: "p2", Title: "other provider"},
+	} {
+		_, err := repo.Create(ctx, s)
+		require.NoError(t, err)
+	}
+
+	listed, err := Listed(ctx, repo, "p1", now)
+	require.NoError(t, err)
+	require.Len(t, listed, domain.MaxPageSize+6)
+	assert.Equal(t, "s0", listed[0].Title)
+	assert.Equal(t, "seasonal", listed[len(listed)-1].Title)
+}
+
+func TestGroup(t *testing.T) {
+	services := []*domain.Services{
+		{Title: "Corte", Category: "Cabello"},
+		{Title: "Consulta"},
+		{Title: "Manicura", Category: "Uñas"},
+		{Title: "Tinte", Category: "Cabello"},
+	}
+
+	groups := Group(services)
+	require.Len(t, groups, 3)
+	assert.Equal(t, "Cabello", groups[0].Name)
+	assert.Equal(t, []*domain.Services{services[0], services[3]}, groups[0].Services)
+	assert.Equal(t, "Uñas", groups[1].Name)
+	assert.Equal(t, "", groups[2].Name)
+	assert.Equal(t, []*domain.Services{services[1]}, groups[2].Services)
+
+	assert.Equal(t, []Category{}, Group(nil))
+}
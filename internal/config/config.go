@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 )
 
 func GetFirebaseProjectID() string {
@@ -61,3 +63,90 @@ func GetJWTSecret() string {
 	}
 	return token
 }
+
+// GetSoftDeleteRetentionDays returns how many days soft-deleted documents are
+// kept before being purged. 0 keeps them forever.
+func GetSoftDeleteRetentionDays() int {
+	days, err := strconv.Atoi(os.Getenv("SOFT_DELETE_RETENTION_DAYS"))
+	if err != nil || days < 0 {
+		return 90
+	}
+	return days
+}
+
+// GetAuditRetentionDays returns how many days audit entries are kept. 0
+// keeps them forever.
+func GetAuditRetentionDays() int {
+	days, err := strconv.Atoi(os.Getenv("AUDIT_RETENTION_DAYS"))
+	if err != nil || days < 0 {
+		return 365
+	}
+	return days
+}
+
+// GetGeocoder names the geocoder used to locate provider addresses:
+// "nominatim", or "" to leave providers without coordinates.
+func GetGeocoder() string {
+	return os.Getenv("GEOCODER")
+}
+
+// GetGeocoderURL overrides the geocoder's base URL, e.g. for a self-hosted
+// Nominatim.
+func GetGeocoderURL() string {
+	return os.Getenv("GEOCODER_URL")
+}
+
+// GetTrustedProxies returns the addresses or CIDR ranges, comma-separated
+// in TRUSTED_PROXIES, of the reverse proxies whose X-Forwarded-For header is
+// believed. None are trusted by default, so the client address is the peer
+// of the connection and cannot be forged through the header.
+func GetTrustedProxies() []string {
+	var proxies []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
+
+// GetBookingRateLimitPerIP returns how many public bookings one client
+// address may make per hour. 0 disables the limit.
+func GetBookingRateLimitPerIP() int {
+	n, err := strconv.Atoi(os.Getenv("BOOKING_RATE_LIMIT_PER_IP"))
+	if err != nil || n < 0 {
+		return 10
+	}
+	return n
+}
+
+// GetBookingRateLimitPerProvider returns how many public bookings one
+// provider may receive per hour. 0 disables the limit.
+func GetBookingRateLimitPerProvider() int {
+	n, err := strconv.Atoi(os.Getenv("BOOKING_RATE_LIMIT_PER_PROVIDER"))
+	if err != nil || n < 0 {
+		return 60
+	}
+	return n
+}
+
+// GetMaxUpcomingBookingsPerCustomer returns how many confirmed upcoming
+// appointments a customer may hold with one provider through the public
+// widget. 0 disables the cap.
+func GetMaxUpcomingBookingsPerCustomer() int {
+	n, err := strconv.Atoi(os.Getenv("MAX_UPCOMING_BOOKINGS_PER_CUSTOMER"))
+	if err != nil || n < 0 {
+		return 3
+	}
+	return n
+}
+
+// GetCaptchaProvider names the CAPTCHA checked on public bookings:
+// "recaptcha", "hcaptcha", "turnstile", or "" for none.
+func GetCaptchaProvider() string {
+	return os.Getenv("CAPTCHA_PROVIDER")
+}
+
+func GetCaptchaSecret() string {
+	return os.Getenv("CAPTCHA_SECRET")
+}
@@ -2,35 +2,81 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+const (
+	StatusConfirmed = "confirmed"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+	StatusNoShow    = "no_show"
+)
+
+// ErrSlotTaken is returned by Create and Update when an appointment would
+// overlap another live appointment of the same provider. Every adapter
+// checks this atomically with the write; Import skips it.
+var ErrSlotTaken = errors.New("time slot is already booked")
+
 type Appointments struct {
-	ID string `json:"id" firestore:"-"`
+	ID string `json:"id" bson:"_id,omitempty" firestore:"-"`
+
+	ServiceId string `json:"service_id" bson:"service_id" firestore:"ServiceId"`
+	ProviderId string `json:"provider_id" bson:"provider_id" firestore:"ProviderId"`
 
-	ServiceId string `json:"service_id" firestore:"ServiceId"`
-	ProviderId string `json:"provider_id" firestore:"ProviderId"`
+	Notes Notes `json:"notes" bson:"notes" firestore:"Notes"`
 
-	Notes interface{} `json:"notes" firestore:"Notes"`
+	ScheduledAt time.Time `json:"scheduled_at" bson:"scheduled_at" firestore:"ScheduledAt"`
 
-	ScheduledAt time.Time `json:"scheduled_at" firestore:"ScheduledAt"`
+	DurationMinutes int `json:"duration_minutes" bson:"duration_minutes" firestore:"DurationMinutes"`
+	ServiceName string `json:"service_name" bson:"service_name" firestore:"ServiceName"`
+	// Price is the service's price when the appointment was booked, so that
+	// later price changes do not restate past revenue.
+	Price float64 `json:"price" bson:"price" firestore:"Price"`
 
-	DurationMinutes int `json:"duration_minutes" firestore:"DurationMinutes"`
-	ServiceName string `json:"service_name" firestore:"ServiceName"`
+	Status string `json:"status" bson:"status" firestore:"Status"`
+
+	CustomerName  string `json:"customer_name" bson:"customer_name" firestore:"CustomerName"`
+	CustomerEmail string `json:"customer_email" bson:"customer_email" firestore:"CustomerEmail"`
+	CustomerPhone string `json:"customer_phone" bson:"customer_phone" firestore:"CustomerPhone"`
+
+	ExternalId string `json:"external_id,omitempty" bson:"external_id,omitempty" firestore:"ExternalId,omitempty"`
+
+	CreatedAt time.Time  `json:"created_at" bson:"created_at" firestore:"CreatedAt"`
+	UpdatedAt time.Time  `json:"updated_at" bson:"updated_at" firestore:"UpdatedAt"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty" firestore:"DeletedAt,omitempty"`
+}
 
-	Status string `json:"status" firestore:"Status"`
+// AppointmentsFilter narrows an appointments listing. Zero values mean no
+// filter. Type is "upcoming" or "past" relative to now; From and To bound
+// ScheduledAt as [From, To); Customer matches CustomerEmail.
+type AppointmentsFilter struct {
+	ListOptions
 
-	CreatedAt time.Time  `json:"created_at" firestore:"CreatedAt"`
-	UpdatedAt time.Time  `json:"updated_at" firestore:"UpdatedAt"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty" firestore:"DeletedAt,omitempty"`
+	ProviderId string
+	Type       string
+	From       time.Time
+	To         time.Time
+	Status     string
+	ServiceId  string
+	Customer   string
 }
 
 type AppointmentsRepository interface {
-	List(ctx context.Context, limit, offset int, filterType string, providerId string) ([]*Appointments, error)
+	List(ctx context.Context, filter AppointmentsFilter) ([]*Appointments, string, error)
 	Get(ctx context.Context, id string) (*Appointments, error)
+	GetIncludingDeleted(ctx context.Context, id string) (*Appointments, error)
+	// ListByDate returns the live appointments of the day of date, in the
+	// location of date, ordered by ScheduledAt.
 	ListByDate(ctx context.Context, date time.Time, providerId string) ([]*Appointments, error)
 	Create(ctx context.Context, model *Appointments) (string, error)
 	Update(ctx context.Context, id string, model *Appointments) error
 	Delete(ctx context.Context, id string) error
+	// Import creates or replaces models in batches, keyed by ProviderId and
+	// ExternalId so that running the same import twice does not duplicate.
+	Import(ctx context.Context, models []*Appointments) error
+	// Purge keeps cancelled appointments: their DeletedAt marks the
+	// cancellation, which stats and exports still report.
+	Purger
 }
 
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditImport  = "import"
+)
+
+// AuditEntry records one mutation: who made it, from where, and which fields
+// changed. ProviderId scopes the entry to the provider that owns the entity.
+type AuditEntry struct {
+	ID string `json:"id" bson:"_id,omitempty" firestore:"-"`
+
+	ProviderId string `json:"provider_id" bson:"provider_id" firestore:"ProviderId"`
+	ActorUID   string `json:"actor_uid" bson:"actor_uid" firestore:"ActorUID"`
+	Action     string `json:"action" bson:"action" firestore:"Action"`
+	EntityType string `json:"entity_type" bson:"entity_type" firestore:"EntityType"`
+	EntityId   string `json:"entity_id" bson:"entity_id" firestore:"EntityId"`
+
+	// Diff maps each changed JSON field to its value before and after.
+	Diff map[string]AuditChange `json:"diff" bson:"diff" firestore:"Diff"`
+
+	IP        string `json:"ip" bson:"ip" firestore:"IP"`
+	UserAgent string `json:"user_agent" bson:"user_agent" firestore:"UserAgent"`
+
+	CreatedAt time.Time `json:"created_at" bson:"created_at" firestore:"CreatedAt"`
+}
+
+type AuditChange struct {
+	Before interface{} `json:"before" bson:"before" firestore:"Before"`
+	After  interface{} `json:"after" bson:"after" firestore:"After"`
+}
+
+// AuditFilter narrows AuditRepository.List. Empty fields match everything;
+// From is inclusive and To exclusive. Entries are listed newest first.
+type AuditFilter struct {
+	ListOptions
+	ProviderId string
+	EntityType string
+	EntityId   string
+	ActorUID   string
+	From       time.Time
+	To         time.Time
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry *AuditEntry) (string, error)
+	List(ctx context.Context, filter AuditFilter) ([]*AuditEntry, string, error)
+	// Purge deletes the entries created before the cutoff and returns how
+	// many were removed.
+	Purge(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Hold reserves a slot for a customer filling in the booking form. Until
+// ExpiresAt it counts as taken for everyone else, and the booking that
+// names its ID replaces it.
+type Hold struct {
+	ID string `json:"id" bson:"_id,omitempty" firestore:"-"`
+
+	ProviderId      string    `json:"provider_id" bson:"provider_id" firestore:"ProviderId"`
+	ServiceId       string    `json:"service_id" bson:"service_id" firestore:"ServiceId"`
+	ScheduledAt     time.Time `json:"scheduled_at" bson:"scheduled_at" firestore:"ScheduledAt"`
+	DurationMinutes int       `json:"duration_minutes" bson:"duration_minutes" firestore:"DurationMinutes"`
+
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at" firestore:"ExpiresAt"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at" firestore:"CreatedAt"`
+}
+
+// Expired reports whether h no longer holds its slot at now.
+func (h *Hold) Expired(now time.Time) bool {
+	return !now.Before(h.ExpiresAt)
+}
+
+type HoldsRepository interface {
+	// Create returns ErrSlotTaken when the hold would overlap a hold of the
+	// same provider that has not expired. Every adapter checks this
+	// atomically with the write, so two customers cannot hold one slot.
+	Create(ctx context.Context, hold *Hold) (string, error)
+	// Get returns the hold even once it has expired, until it is purged.
+	Get(ctx context.Context, id string) (*Hold, error)
+	// ListByDate returns the holds of the provider on the day of date, in
+	// the location of date, that have not expired at now.
+	ListByDate(ctx context.Context, date time.Time, providerId string, now time.Time) ([]*Hold, error)
+	Delete(ctx context.Context, id string) error
+	// Purge deletes the holds that expired before the cutoff and returns
+	// how many were removed.
+	Purge(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotencyRecord remembers a request made with an Idempotency-Key and,
+// once it has finished, its response. A record without a StatusCode is a
+// request still in progress.
+type IdempotencyRecord struct {
+	Key         string `json:"key" bson:"_id" firestore:"-"`
+	RequestHash string `json:"request_hash" bson:"request_hash" firestore:"RequestHash"`
+
+	StatusCode  int    `json:"status_code" bson:"status_code" firestore:"StatusCode"`
+	ContentType string `json:"content_type" bson:"content_type" firestore:"ContentType"`
+	Body        []byte `json:"body" bson:"body" firestore:"Body"`
+
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at" firestore:"ExpiresAt"`
+}
+
+// Completed reports whether r holds a response to replay.
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+type IdempotencyStore interface {
+	// Start claims rec.Key for a new request. If the key is already claimed
+	// by a record that has not expired, it returns that record and leaves
+	// it untouched; otherwise it stores rec and returns nil.
+	Start(ctx context.Context, rec *IdempotencyRecord, now time.Time) (*IdempotencyRecord, error)
+	// Complete replaces the record of rec.Key with rec.
+	Complete(ctx context.Context, rec *IdempotencyRecord) error
+	// Delete releases a key so the request can be made again.
+	Delete(ctx context.Context, key string) error
+	// Purge deletes the records that expired before the cutoff and returns
+	// how many were removed.
+	Purge(ctx context.Context, before time.Time) (int, error)
+}
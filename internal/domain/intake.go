@@ -0,0 +1,27 @@
+package domain
+
+// IntakeFieldType is the kind of answer an intake field takes.
+type IntakeFieldType string
+
+const (
+	// IntakeText is free text.
+	IntakeText IntakeFieldType = "text"
+	// IntakeChoice is one of the field's Options.
+	IntakeChoice IntakeFieldType = "choice"
+	// IntakeCheckbox is "true" or "false". A required checkbox, such as a
+	// consent, must be checked.
+	IntakeCheckbox IntakeFieldType = "checkbox"
+	// IntakeDate is a calendar date as YYYY-MM-DD.
+	IntakeDate IntakeFieldType = "date"
+)
+
+// IntakeField is one question of the form a service asks customers to
+// fill in when they book it, e.g. "Do you have allergies?". Id identifies
+// the field in the answers and stays fixed when the label is reworded.
+type IntakeField struct {
+	Id       string          `json:"id" bson:"id" firestore:"Id"`
+	Label    string          `json:"label" bson:"label" firestore:"Label"`
+	Type     IntakeFieldType `json:"type" bson:"type" firestore:"Type"`
+	Required bool            `json:"required" bson:"required" firestore:"Required"`
+	Options  []string        `json:"options,omitempty" bson:"options,omitempty" firestore:"Options,omitempty"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "errors"
+
+// ErrInvalidCursor is returned by repositories when a pagination cursor
+// cannot be decoded or does not belong to the requested listing.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ListOptions controls cursor pagination. Cursor is the opaque next_cursor
+// returned by a previous call; an empty cursor starts from the first page.
+// Soft-deleted documents are skipped unless IncludeDeleted is set.
+type ListOptions struct {
+	Limit          int
+	Cursor         string
+	IncludeDeleted bool
+}
+
+// PageSize clamps Limit to [1, MaxPageSize], defaulting to DefaultPageSize.
+func (o ListOptions) PageSize() int {
+	if o.Limit <= 0 {
+		return DefaultPageSize
+	}
+	if o.Limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return o.Limit
+}
@@ -6,38 +6,71 @@ import (
 )
 
 type Providers struct {
-	ID string `json:"id" firestore:"-"`
+	ID string `json:"id" bson:"_id,omitempty" firestore:"-"`
 
-	UserId string `json:"user_id" firestore:"UserId"`
+	UserId string `json:"user_id" bson:"user_id" firestore:"UserId"`
 
-	Address string `json:"address" firestore:"Address"`
+	// Slug identifies the provider in public URLs. It is unique among live
+	// providers.
+	Slug string `json:"slug" bson:"slug" firestore:"Slug"`
 
-	AvatarUrl string `json:"avatar_url" firestore:"AvatarUrl"`
+	Address string `json:"address" bson:"address" firestore:"Address"`
 
-	EstablishmentName string `json:"establishment_name" firestore:"EstablishmentName"`
+	City string `json:"city" bson:"city" firestore:"City"`
 
-	Phone string `json:"phone" firestore:"Phone"`
+	// Latitude and Longitude locate Address and Geohash encodes them for
+	// radius searches. They are filled by geocoding and left empty when the
+	// address could not be located.
+	Latitude  float64 `json:"latitude" bson:"latitude" firestore:"Latitude"`
+	Longitude float64 `json:"longitude" bson:"longitude" firestore:"Longitude"`
+	Geohash   string  `json:"geohash,omitempty" bson:"geohash,omitempty" firestore:"Geohash,omitempty"`
 
-	CreatedAt time.Time  `json:"created_at" firestore:"CreatedAt"`
-	UpdatedAt time.Time  `json:"updated_at" firestore:"UpdatedAt"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty" firestore:"DeletedAt,omitempty"`
+	AvatarUrl string `json:"avatar_url" bson:"avatar_url" firestore:"AvatarUrl"`
+
+	EstablishmentName string `json:"establishment_name" bson:"establishment_name" firestore:"EstablishmentName"`
+
+	BusinessType string `json:"business_type" bson:"business_type" firestore:"BusinessType"`
+
+	Phone string `json:"phone" bson:"phone" firestore:"Phone"`
+
+	CreatedAt time.Time  `json:"created_at" bson:"created_at" firestore:"CreatedAt"`
+	UpdatedAt time.Time  `json:"updated_at" bson:"updated_at" firestore:"UpdatedAt"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty" firestore:"DeletedAt,omitempty"`
 }
 
 type DaySchedule struct {
-	Ranges  []TimeRange `json:"ranges" firestore:"Ranges"`
-	Enabled bool        `json:"enabled" firestore:"Enabled"`
+	Ranges  []TimeRange `json:"ranges" bson:"ranges" firestore:"Ranges"`
+	Enabled bool        `json:"enabled" bson:"enabled" firestore:"Enabled"`
 }
 
 type TimeRange struct {
-	Start string `json:"start" firestore:"Start"`
-	End   string `json:"end" firestore:"End"`
+	Start string `json:"start" bson:"start" firestore:"Start"`
+	End   string `json:"end" bson:"end" firestore:"End"`
+}
+
+// ProviderFilter narrows ProvidersRepository.Search to live providers
+// matching every non-empty field exactly.
+type ProviderFilter struct {
+	ListOptions
+	City         string
+	BusinessType string
 }
 
 type ProvidersRepository interface {
-	List(ctx context.Context, limit, offset int) ([]*Providers, error)
+	List(ctx context.Context, opts ListOptions) ([]*Providers, string, error)
 	Get(ctx context.Context, id string) (*Providers, error)
+	GetIncludingDeleted(ctx context.Context, id string) (*Providers, error)
+	// GetByUserId returns the live provider of the user, or nil when there
+	// is none.
 	GetByUserId(ctx context.Context, userId string) (*Providers, error)
+	// GetBySlug returns the live provider with the slug, or ErrNotFound.
+	GetBySlug(ctx context.Context, slug string) (*Providers, error)
+	Search(ctx context.Context, filter ProviderFilter) ([]*Providers, string, error)
+	// ListByGeohash returns the live providers whose Geohash starts with
+	// prefix.
+	ListByGeohash(ctx context.Context, prefix string) ([]*Providers, error)
 	Create(ctx context.Context, model *Providers) (string, error)
 	Update(ctx context.Context, id string, model *Providers) error
 	Delete(ctx context.Context, id string) error
+	Purger
 }
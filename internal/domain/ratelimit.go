@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitStore counts hits per key in fixed windows. Hit adds one to the
+// count of key in the window of the given length that contains now, and
+// returns the new count and when that window ends. It is Redis' INCR plus
+// PEXPIRE on first hit, so a Redis adapter is a two-command pipeline.
+// Callers use one window length per key.
+type RateLimitStore interface {
+	Hit(ctx context.Context, key string, window time.Duration) (count int, resetAt time.Time, err error)
+}
+
+// WindowStart truncates now to the start of its window. Windows are fixed
+// multiples of their length, so every instance sharing a store agrees on
+// them.
+func WindowStart(now time.Time, window time.Duration) time.Time {
+	return now.Truncate(window)
+}
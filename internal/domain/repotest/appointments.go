@@ -0,0 +1,336 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// appointment builds a one minute appointment, so the ones a test creates
+// back to back never overlap.
+func appointment(providerId string, at time.Time) *domain.Appointments {
+	return &domain.Appointments{
+		ProviderId:      providerId,
+		ServiceId:       "svc-1",
+		ServiceName:     "Corte",
+		ScheduledAt:     at,
+		DurationMinutes: 1,
+		Status:          domain.StatusConfirmed,
+	}
+}
+
+func createAppointment(t *testing.T, repo domain.AppointmentsRepository, m *domain.Appointments) string {
+	t.Helper()
+	id, err := repo.Create(context.Background(), m)
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	return id
+}
+
+// listAll follows next_cursor until the last page and fails on repeats.
+func listAll(t *testing.T, repo domain.AppointmentsRepository, filter domain.AppointmentsFilter) []*domain.Appointments {
+	t.Helper()
+	var all []*domain.Appointments
+	seen := map[string]bool{}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 100, "pagination does not terminate")
+		page, next, err := repo.List(context.Background(), filter)
+		require.NoError(t, err)
+		for _, m := range page {
+			require.False(t, seen[m.ID], "appointment %s returned twice", m.ID)
+			seen[m.ID] = true
+			all = append(all, m)
+		}
+		if next == "" {
+			return all
+		}
+		filter.Cursor = next
+	}
+}
+
+func ids(appointments []*domain.Appointments) []string {
+	out := make([]string, 0, len(appointments))
+	for _, m := range appointments {
+		out = append(out, m.ID)
+	}
+	return out
+}
+
+// Appointments checks a domain.AppointmentsRepository.
+func Appointments(t *testing.T, repo domain.AppointmentsRepository) {
+	ctx := context.Background()
+
+	t.Run("CRUD", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		m := appointment(providerId, day().Add(10*time.Hour))
+		m.Notes = domain.Notes{Text: "first visit", Answers: []domain.IntakeAnswer{{Question: "Alergias", Answer: "Ninguna"}}}
+		m.CustomerName = "Ana"
+		m.CustomerEmail = "ana@example.com"
+		m.CustomerPhone = "+54 11 5555-1234"
+		m.Price = 1500.5
+		id := createAppointment(t, repo, m)
+
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, got.ID)
+		assert.Equal(t, providerId, got.ProviderId)
+		assert.Equal(t, "svc-1", got.ServiceId)
+		assert.Equal(t, "Corte", got.ServiceName)
+		assert.Equal(t, 1, got.DurationMinutes)
+		assert.Equal(t, 1500.5, got.Price)
+		assert.Equal(t, domain.StatusConfirmed, got.Status)
+		assert.Equal(t, m.Notes, got.Notes)
+		assert.Equal(t, "ana@example.com", got.CustomerEmail)
+		assert.True(t, got.ScheduledAt.Equal(m.ScheduledAt), "scheduled_at %v != %v", got.ScheduledAt, m.ScheduledAt)
+		assert.False(t, got.CreatedAt.IsZero())
+
+		got.Status = domain.StatusCompleted
+		require.NoError(t, repo.Update(ctx, id, got))
+		updated, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusCompleted, updated.Status)
+		assert.Equal(t, "ana@example.com", updated.CustomerEmail)
+
+		require.NoError(t, repo.Delete(ctx, id))
+		_, err = repo.Get(ctx, id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("overlaps are rejected", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		at := day().Add(10 * time.Hour)
+		first := appointment(providerId, at)
+		first.DurationMinutes = 30
+		firstId := createAppointment(t, repo, first)
+
+		overlapping := appointment(providerId, at.Add(15*time.Minute))
+		_, err := repo.Create(ctx, overlapping)
+		assert.ErrorIs(t, err, domain.ErrSlotTaken)
+
+		// Back to back, another provider and cancelled appointments do not
+		// overlap.
+		next := appointment(providerId, at.Add(30*time.Minute))
+		nextId := createAppointment(t, repo, next)
+		createAppointment(t, repo, appointment(uniqueID("prov"), at))
+		cancelled := appointment(providerId, at.Add(10*time.Minute))
+		cancelled.Status = domain.StatusCancelled
+		createAppointment(t, repo, cancelled)
+
+		next.ScheduledAt = at.Add(20 * time.Minute)
+		assert.ErrorIs(t, repo.Update(ctx, nextId, next), domain.ErrSlotTaken)
+
+		// An appointment may move within its own slot.
+		first.ScheduledAt = at.Add(-5 * time.Minute)
+		require.NoError(t, repo.Update(ctx, firstId, first))
+	})
+
+	t.Run("Get missing", func(t *testing.T) {
+		_, err := repo.Get(ctx, uniqueID("missing"))
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("ListByDate day boundaries", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		d := day()
+		before := createAppointment(t, repo, appointment(providerId, d.Add(-time.Minute)))
+		first := createAppointment(t, repo, appointment(providerId, d))
+		last := createAppointment(t, repo, appointment(providerId, d.Add(24*time.Hour-time.Minute)))
+		after := createAppointment(t, repo, appointment(providerId, d.Add(24*time.Hour)))
+		other := createAppointment(t, repo, appointment(uniqueID("prov"), d.Add(12*time.Hour)))
+
+		results, err := repo.ListByDate(ctx, d.Add(15*time.Hour), providerId)
+		require.NoError(t, err)
+		assert.Equal(t, []string{first, last}, ids(results), "start of day included, next midnight excluded, ordered by time")
+
+		all, err := repo.ListByDate(ctx, d, "")
+		require.NoError(t, err)
+		allIds := ids(all)
+		assert.Contains(t, allIds, other, "empty provider lists every provider")
+		assert.NotContains(t, allIds, before)
+		assert.NotContains(t, allIds, after)
+	})
+
+	t.Run("List pagination and ordering", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		d := day()
+		var created []string
+		for i := 0; i < 5; i++ {
+			created = append(created, createAppointment(t, repo, appointment(providerId, d.Add(time.Duration(9+i)*time.Hour))))
+		}
+		// Two cancelled appointments at the same time exercise the ID
+		// tie-break of the cursor.
+		for i := 0; i < 2; i++ {
+			m := appointment(providerId, d.Add(20*time.Hour))
+			m.Status = domain.StatusCancelled
+			created = append(created, createAppointment(t, repo, m))
+		}
+
+		desc := listAll(t, repo, domain.AppointmentsFilter{ListOptions: domain.ListOptions{Limit: 2}, ProviderId: providerId})
+		assert.ElementsMatch(t, created, ids(desc))
+		for i := 1; i < len(desc); i++ {
+			assert.False(t, desc[i].ScheduledAt.After(desc[i-1].ScheduledAt), "default listing is newest first")
+		}
+
+		asc := listAll(t, repo, domain.AppointmentsFilter{ListOptions: domain.ListOptions{Limit: 3}, ProviderId: providerId, Type: "upcoming"})
+		assert.ElementsMatch(t, created, ids(asc))
+		for i := 1; i < len(asc); i++ {
+			assert.False(t, asc[i].ScheduledAt.Before(asc[i-1].ScheduledAt), "upcoming listing is oldest first")
+		}
+
+		past := listAll(t, repo, domain.AppointmentsFilter{ProviderId: providerId, Type: "past"})
+		assert.Empty(t, past)
+	})
+
+	t.Run("List filters", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		d := day()
+		atFrom := createAppointment(t, repo, appointment(providerId, d.Add(10*time.Hour)))
+		atTo := appointment(providerId, d.Add(11*time.Hour))
+		atTo.ServiceId = "svc-2"
+		atTo.CustomerEmail = "juan@example.com"
+		atTo.Status = domain.StatusNoShow
+		atToId := createAppointment(t, repo, atTo)
+		createAppointment(t, repo, appointment(uniqueID("prov"), d.Add(10*time.Hour+30*time.Minute)))
+
+		inRange := listAll(t, repo, domain.AppointmentsFilter{ProviderId: providerId, From: d.Add(10 * time.Hour), To: d.Add(11 * time.Hour)})
+		assert.Equal(t, []string{atFrom}, ids(inRange), "from is inclusive, to is exclusive, other providers excluded")
+
+		for name, filter := range map[string]domain.AppointmentsFilter{
+			"status":   {ProviderId: providerId, Status: domain.StatusNoShow},
+			"service":  {ProviderId: providerId, ServiceId: "svc-2"},
+			"customer": {ProviderId: providerId, Customer: "juan@example.com"},
+		} {
+			assert.Equal(t, []string{atToId}, ids(listAll(t, repo, filter)), name)
+		}
+	})
+
+	t.Run("List invalid cursor", func(t *testing.T) {
+		_, _, err := repo.List(ctx, domain.AppointmentsFilter{ListOptions: domain.ListOptions{Cursor: "not a cursor"}})
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+
+	t.Run("soft delete", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		at := day().Add(10 * time.Hour)
+		id := createAppointment(t, repo, appointment(providerId, at))
+
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		deletedAt := time.Now().UTC().Truncate(time.Second)
+		got.DeletedAt = &deletedAt
+		require.NoError(t, repo.Update(ctx, id, got))
+
+		_, err = repo.Get(ctx, id)
+		assert.ErrorIs(t, err, domain.ErrNotFound, "Get hides soft deleted appointments")
+
+		got, err = repo.GetIncludingDeleted(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, got.DeletedAt)
+		assert.True(t, got.DeletedAt.Equal(deletedAt))
+
+		assert.Empty(t, listAll(t, repo, domain.AppointmentsFilter{ProviderId: providerId}))
+		byDate, err := repo.ListByDate(ctx, at, providerId)
+		require.NoError(t, err)
+		assert.Empty(t, byDate, "deleted appointments do not take the slot")
+
+		withDeleted := listAll(t, repo, domain.AppointmentsFilter{ListOptions: domain.ListOptions{IncludeDeleted: true}, ProviderId: providerId})
+		assert.Equal(t, []string{id}, ids(withDeleted))
+
+		got.DeletedAt = nil
+		require.NoError(t, repo.Update(ctx, id, got))
+		_, err = repo.Get(ctx, id)
+		assert.NoError(t, err, "clearing deleted_at restores")
+	})
+
+	t.Run("List pages past runs of deleted appointments", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		var live []string
+		for i := 0; i < 5; i++ {
+			m := appointment(providerId, day().Add(10*time.Hour+time.Duration(i)*time.Minute))
+			id := createAppointment(t, repo, m)
+			if i == 1 || i == 4 {
+				live = append(live, id)
+				continue
+			}
+			deletedAt := time.Now().UTC().Truncate(time.Second)
+			m.DeletedAt = &deletedAt
+			require.NoError(t, repo.Update(ctx, id, m))
+		}
+
+		listed := listAll(t, repo, domain.AppointmentsFilter{ListOptions: domain.ListOptions{Limit: 1}, ProviderId: providerId})
+		assert.ElementsMatch(t, live, ids(listed))
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		old := time.Now().UTC().AddDate(0, 0, -100).Truncate(time.Second)
+		recent := time.Now().UTC().AddDate(0, 0, -1).Truncate(time.Second)
+
+		oldDeleted := appointment(providerId, day().Add(9*time.Hour))
+		oldDeleted.DeletedAt = &old
+		oldId := createAppointment(t, repo, oldDeleted)
+		recentDeleted := appointment(providerId, day().Add(10*time.Hour))
+		recentDeleted.DeletedAt = &recent
+		recentId := createAppointment(t, repo, recentDeleted)
+		liveId := createAppointment(t, repo, appointment(providerId, day().Add(11*time.Hour)))
+		oldCancelled := appointment(providerId, day().Add(12*time.Hour))
+		oldCancelled.Status = domain.StatusCancelled
+		oldCancelled.DeletedAt = &old
+		cancelledId := createAppointment(t, repo, oldCancelled)
+
+		purged, err := repo.Purge(ctx, time.Now().UTC().AddDate(0, 0, -30))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, purged, 1)
+
+		_, err = repo.GetIncludingDeleted(ctx, oldId)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = repo.GetIncludingDeleted(ctx, recentId)
+		assert.NoError(t, err)
+		_, err = repo.Get(ctx, liveId)
+		assert.NoError(t, err)
+		_, err = repo.GetIncludingDeleted(ctx, cancelledId)
+		assert.NoError(t, err, "cancellations are history, not deletions")
+	})
+
+	t.Run("Import is idempotent", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		models := func() []*domain.Appointments {
+			m := appointment(providerId, day().AddDate(-10, 0, 0))
+			m.ExternalId = "ext-1"
+			m.Status = domain.StatusCompleted
+			return []*domain.Appointments{m}
+		}
+		require.NoError(t, repo.Import(ctx, models()))
+		require.NoError(t, repo.Import(ctx, models()))
+
+		listed := listAll(t, repo, domain.AppointmentsFilter{ProviderId: providerId})
+		require.Len(t, listed, 1)
+		assert.Equal(t, "ext-1", listed[0].ExternalId)
+	})
+
+	t.Run("Import skips the overlap check", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		at := day().AddDate(-1, 0, 0).Add(10 * time.Hour)
+		live := appointment(providerId, at)
+		live.DurationMinutes = 30
+		createAppointment(t, repo, live)
+
+		var models []*domain.Appointments
+		for i, ext := range []string{"ext-1", "ext-2"} {
+			m := appointment(providerId, at.Add(time.Duration(i*10)*time.Minute))
+			m.DurationMinutes = 30
+			m.ExternalId = ext
+			m.Status = domain.StatusCompleted
+			models = append(models, m)
+		}
+		require.NoError(t, repo.Import(ctx, models))
+
+		listed := listAll(t, repo, domain.AppointmentsFilter{ProviderId: providerId})
+		assert.Len(t, listed, 3)
+	})
+}
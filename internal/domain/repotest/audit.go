@@ -0,0 +1,140 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createAudit(t *testing.T, repo domain.AuditRepository, m *domain.AuditEntry) string {
+	t.Helper()
+	id, err := repo.Create(context.Background(), m)
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	return id
+}
+
+func listAllAudit(t *testing.T, repo domain.AuditRepository, filter domain.AuditFilter) []*domain.AuditEntry {
+	t.Helper()
+	var all []*domain.AuditEntry
+	seen := map[string]bool{}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 100, "pagination does not terminate")
+		page, next, err := repo.List(context.Background(), filter)
+		require.NoError(t, err)
+		for _, m := range page {
+			require.False(t, seen[m.ID], "audit entry %s returned twice", m.ID)
+			seen[m.ID] = true
+			all = append(all, m)
+		}
+		if next == "" {
+			return all
+		}
+		filter.Cursor = next
+	}
+}
+
+func auditIds(entries []*domain.AuditEntry) []string {
+	out := make([]string, 0, len(entries))
+	for _, m := range entries {
+		out = append(out, m.ID)
+	}
+	return out
+}
+
+// Audit checks a domain.AuditRepository.
+func Audit(t *testing.T, repo domain.AuditRepository) {
+	ctx := context.Background()
+
+	t.Run("Create and List", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		at := day().Add(9 * time.Hour)
+		id := createAudit(t, repo, &domain.AuditEntry{
+			ProviderId: providerId,
+			ActorUID:   "user-1",
+			Action:     domain.AuditUpdate,
+			EntityType: "appointments",
+			EntityId:   "appt-1",
+			Diff:       map[string]domain.AuditChange{"status": {Before: "confirmed", After: "cancelled"}},
+			IP:         "203.0.113.7",
+			UserAgent:  "test",
+			CreatedAt:  at,
+		})
+
+		got := listAllAudit(t, repo, domain.AuditFilter{ProviderId: providerId})
+		require.Len(t, got, 1)
+		m := got[0]
+		assert.Equal(t, id, m.ID)
+		assert.Equal(t, "user-1", m.ActorUID)
+		assert.Equal(t, domain.AuditUpdate, m.Action)
+		assert.Equal(t, "appointments", m.EntityType)
+		assert.Equal(t, "appt-1", m.EntityId)
+		assert.Equal(t, domain.AuditChange{Before: "confirmed", After: "cancelled"}, m.Diff["status"])
+		assert.Equal(t, "203.0.113.7", m.IP)
+		assert.Equal(t, "test", m.UserAgent)
+		assert.True(t, at.Equal(m.CreatedAt))
+	})
+
+	t.Run("List pagination and ordering", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		d := day()
+		var created []string
+		for i := 0; i < 5; i++ {
+			created = append(created, createAudit(t, repo, &domain.AuditEntry{ProviderId: providerId, Action: domain.AuditCreate, EntityType: "services", CreatedAt: d.Add(time.Duration(i) * time.Hour)}))
+		}
+		// Entries at the same time exercise the ID tie-break of the cursor.
+		for i := 0; i < 2; i++ {
+			created = append(created, createAudit(t, repo, &domain.AuditEntry{ProviderId: providerId, Action: domain.AuditCreate, EntityType: "services", CreatedAt: d.Add(10 * time.Hour)}))
+		}
+
+		all := listAllAudit(t, repo, domain.AuditFilter{ListOptions: domain.ListOptions{Limit: 2}, ProviderId: providerId})
+		assert.ElementsMatch(t, created, auditIds(all))
+		for i := 1; i < len(all); i++ {
+			assert.False(t, all[i].CreatedAt.After(all[i-1].CreatedAt), "newest first")
+		}
+	})
+
+	t.Run("List filters", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		d := day()
+		first := createAudit(t, repo, &domain.AuditEntry{ProviderId: providerId, ActorUID: "user-1", Action: domain.AuditCreate, EntityType: "appointments", EntityId: "a-1", CreatedAt: d.Add(10 * time.Hour)})
+		second := createAudit(t, repo, &domain.AuditEntry{ProviderId: providerId, ActorUID: "user-2", Action: domain.AuditUpdate, EntityType: "services", EntityId: "s-1", CreatedAt: d.Add(11 * time.Hour)})
+		createAudit(t, repo, &domain.AuditEntry{ProviderId: uniqueID("prov"), ActorUID: "user-1", Action: domain.AuditCreate, EntityType: "appointments", EntityId: "a-1", CreatedAt: d.Add(10 * time.Hour)})
+
+		for name, tc := range map[string]struct {
+			filter domain.AuditFilter
+			want   []string
+		}{
+			"entity type": {domain.AuditFilter{ProviderId: providerId, EntityType: "services"}, []string{second}},
+			"entity id":   {domain.AuditFilter{ProviderId: providerId, EntityId: "a-1"}, []string{first}},
+			"actor":       {domain.AuditFilter{ProviderId: providerId, ActorUID: "user-2"}, []string{second}},
+			"range":       {domain.AuditFilter{ProviderId: providerId, From: d.Add(10 * time.Hour), To: d.Add(11 * time.Hour)}, []string{first}},
+		} {
+			assert.Equal(t, tc.want, auditIds(listAllAudit(t, repo, tc.filter)), name)
+		}
+	})
+
+	t.Run("List invalid cursor", func(t *testing.T) {
+		_, _, err := repo.List(ctx, domain.AuditFilter{ListOptions: domain.ListOptions{Cursor: "not a cursor"}})
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		old := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+		createAudit(t, repo, &domain.AuditEntry{ProviderId: providerId, Action: domain.AuditCreate, EntityType: "services", CreatedAt: old})
+		createAudit(t, repo, &domain.AuditEntry{ProviderId: providerId, Action: domain.AuditUpdate, EntityType: "services", CreatedAt: old.Add(time.Hour)})
+		kept := createAudit(t, repo, &domain.AuditEntry{ProviderId: providerId, Action: domain.AuditDelete, EntityType: "services", CreatedAt: day()})
+
+		n, err := repo.Purge(ctx, old.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, n, 2)
+
+		assert.Equal(t, []string{kept}, auditIds(listAllAudit(t, repo, domain.AuditFilter{ProviderId: providerId})))
+	})
+}
@@ -0,0 +1,128 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createHold(t *testing.T, repo domain.HoldsRepository, m *domain.Hold) string {
+	t.Helper()
+	id, err := repo.Create(context.Background(), m)
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	return id
+}
+
+// Holds checks a domain.HoldsRepository.
+func Holds(t *testing.T, repo domain.HoldsRepository) {
+	ctx := context.Background()
+
+	t.Run("Create and Get", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		at := day().Add(10 * time.Hour)
+		expires := time.Now().Add(10 * time.Minute).UTC().Truncate(time.Millisecond)
+		id := createHold(t, repo, &domain.Hold{ProviderId: providerId, ServiceId: "svc-1", ScheduledAt: at, DurationMinutes: 30, ExpiresAt: expires})
+
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, got.ID)
+		assert.Equal(t, providerId, got.ProviderId)
+		assert.Equal(t, "svc-1", got.ServiceId)
+		assert.True(t, at.Equal(got.ScheduledAt))
+		assert.Equal(t, 30, got.DurationMinutes)
+		assert.True(t, expires.Equal(got.ExpiresAt))
+		assert.False(t, got.CreatedAt.IsZero())
+
+		require.NoError(t, repo.Delete(ctx, id))
+		_, err = repo.Get(ctx, id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("overlapping live holds are rejected", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		at := day().Add(10 * time.Hour)
+		live := time.Now().Add(10 * time.Minute).UTC()
+		createHold(t, repo, &domain.Hold{ProviderId: providerId, ScheduledAt: at, DurationMinutes: 30, ExpiresAt: live})
+
+		_, err := repo.Create(ctx, &domain.Hold{ProviderId: providerId, ScheduledAt: at.Add(15 * time.Minute), DurationMinutes: 30, ExpiresAt: live})
+		assert.ErrorIs(t, err, domain.ErrSlotTaken)
+		_, err = repo.Create(ctx, &domain.Hold{ProviderId: providerId, ScheduledAt: at.Add(-15 * time.Minute), DurationMinutes: 30, ExpiresAt: live})
+		assert.ErrorIs(t, err, domain.ErrSlotTaken)
+
+		// Back to back, another provider and expired holds do not overlap.
+		createHold(t, repo, &domain.Hold{ProviderId: providerId, ScheduledAt: at.Add(30 * time.Minute), DurationMinutes: 30, ExpiresAt: live})
+		createHold(t, repo, &domain.Hold{ProviderId: uniqueID("prov"), ScheduledAt: at, DurationMinutes: 30, ExpiresAt: live})
+		later := at.Add(2 * time.Hour)
+		createHold(t, repo, &domain.Hold{ProviderId: providerId, ScheduledAt: later, DurationMinutes: 30, ExpiresAt: time.Now().Add(-time.Minute).UTC()})
+		createHold(t, repo, &domain.Hold{ProviderId: providerId, ScheduledAt: later, DurationMinutes: 30, ExpiresAt: live})
+	})
+
+	t.Run("concurrent holds of one slot", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		at := day().Add(10 * time.Hour)
+		live := time.Now().Add(10 * time.Minute).UTC()
+
+		const n = 5
+		errs := make(chan error, n)
+		for i := 0; i < n; i++ {
+			go func() {
+				_, err := repo.Create(ctx, &domain.Hold{ProviderId: providerId, ScheduledAt: at, DurationMinutes: 30, ExpiresAt: live})
+				errs <- err
+			}()
+		}
+		created := 0
+		for i := 0; i < n; i++ {
+			err := <-errs
+			if err == nil {
+				created++
+				continue
+			}
+			assert.ErrorIs(t, err, domain.ErrSlotTaken)
+		}
+		assert.Equal(t, 1, created)
+	})
+
+	t.Run("ListByDate skips expired holds and other days", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		now := time.Now().UTC()
+		live := now.Add(10 * time.Minute)
+		d := day()
+
+		late := createHold(t, repo, &domain.Hold{ProviderId: providerId, ScheduledAt: d.Add(15 * time.Hour), ExpiresAt: live})
+		early := createHold(t, repo, &domain.Hold{ProviderId: providerId, ScheduledAt: d.Add(9 * time.Hour), ExpiresAt: live})
+		createHold(t, repo, &domain.Hold{ProviderId: providerId, ScheduledAt: d.Add(11 * time.Hour), ExpiresAt: now.Add(-time.Minute)})
+		createHold(t, repo, &domain.Hold{ProviderId: providerId, ScheduledAt: d.Add(24 * time.Hour), ExpiresAt: live})
+		createHold(t, repo, &domain.Hold{ProviderId: uniqueID("prov"), ScheduledAt: d.Add(10 * time.Hour), ExpiresAt: live})
+
+		holds, err := repo.ListByDate(ctx, d, providerId, now)
+		require.NoError(t, err)
+		var ids []string
+		for _, h := range holds {
+			ids = append(ids, h.ID)
+		}
+		assert.Equal(t, []string{early, late}, ids)
+	})
+
+	t.Run("Purge removes holds expired before the cutoff", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		cutoff := time.Now().Add(-time.Hour).UTC()
+		expired := createHold(t, repo, &domain.Hold{ProviderId: providerId, ScheduledAt: day(), ExpiresAt: cutoff.Add(-time.Minute)})
+		// Still live, so a store's own expiry does not remove it either.
+		kept := createHold(t, repo, &domain.Hold{ProviderId: providerId, ScheduledAt: day(), ExpiresAt: time.Now().Add(10 * time.Minute).UTC()})
+
+		n, err := repo.Purge(ctx, cutoff)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, n, 1)
+
+		_, err = repo.Get(ctx, expired)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = repo.Get(ctx, kept)
+		assert.NoError(t, err)
+	})
+}
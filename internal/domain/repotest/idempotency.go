@@ -0,0 +1,81 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Idempotency checks a domain.IdempotencyStore.
+func Idempotency(t *testing.T, store domain.IdempotencyStore) {
+	ctx := context.Background()
+
+	t.Run("Start claims a key once", func(t *testing.T) {
+		now := time.Now().UTC()
+		key := uniqueID("key")
+		rec := &domain.IdempotencyRecord{Key: key, RequestHash: "h1", ExpiresAt: now.Add(time.Minute)}
+
+		existing, err := store.Start(ctx, rec, now)
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+
+		existing, err = store.Start(ctx, &domain.IdempotencyRecord{Key: key, RequestHash: "h2", ExpiresAt: now.Add(time.Minute)}, now)
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, "h1", existing.RequestHash)
+		assert.False(t, existing.Completed())
+
+		rec.StatusCode = 201
+		rec.ContentType = "application/json"
+		rec.Body = []byte(`{"id":"a1"}`)
+		rec.ExpiresAt = now.Add(24 * time.Hour)
+		require.NoError(t, store.Complete(ctx, rec))
+
+		existing, err = store.Start(ctx, &domain.IdempotencyRecord{Key: key, RequestHash: "h1", ExpiresAt: now.Add(time.Minute)}, now)
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.True(t, existing.Completed())
+		assert.Equal(t, 201, existing.StatusCode)
+		assert.Equal(t, "application/json", existing.ContentType)
+		assert.Equal(t, `{"id":"a1"}`, string(existing.Body))
+	})
+
+	t.Run("Start takes over expired and deleted keys", func(t *testing.T) {
+		now := time.Now().UTC()
+		key := uniqueID("key")
+		_, err := store.Start(ctx, &domain.IdempotencyRecord{Key: key, RequestHash: "old", ExpiresAt: now.Add(time.Minute)}, now)
+		require.NoError(t, err)
+
+		later := now.Add(2 * time.Minute)
+		existing, err := store.Start(ctx, &domain.IdempotencyRecord{Key: key, RequestHash: "new", ExpiresAt: later.Add(time.Minute)}, later)
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+
+		require.NoError(t, store.Delete(ctx, key))
+		existing, err = store.Start(ctx, &domain.IdempotencyRecord{Key: key, RequestHash: "again", ExpiresAt: later.Add(time.Minute)}, later)
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+	})
+
+	t.Run("Purge removes expired records", func(t *testing.T) {
+		now := time.Now().UTC()
+		expired, kept := uniqueID("key"), uniqueID("key")
+		_, err := store.Start(ctx, &domain.IdempotencyRecord{Key: expired, RequestHash: "h", ExpiresAt: now.Add(-2 * time.Hour)}, now)
+		require.NoError(t, err)
+		_, err = store.Start(ctx, &domain.IdempotencyRecord{Key: kept, RequestHash: "h", ExpiresAt: now.Add(time.Hour)}, now)
+		require.NoError(t, err)
+
+		n, err := store.Purge(ctx, now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, n, 1)
+
+		existing, err := store.Start(ctx, &domain.IdempotencyRecord{Key: kept, RequestHash: "new", ExpiresAt: now.Add(time.Hour)}, now)
+		require.NoError(t, err)
+		assert.NotNil(t, existing)
+	})
+}
@@ -0,0 +1,196 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Providers checks a domain.ProvidersRepository.
+func Providers(t *testing.T, repo domain.ProvidersRepository) {
+	ctx := context.Background()
+
+	t.Run("CRUD", func(t *testing.T) {
+		userId := uniqueID("user")
+		id, err := repo.Create(ctx, &domain.Providers{UserId: userId, EstablishmentName: "Barbería", BusinessType: "barbershop", Phone: "123"})
+		require.NoError(t, err)
+		require.NotEmpty(t, id)
+
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, got.ID)
+		assert.Equal(t, "Barbería", got.EstablishmentName)
+		assert.Equal(t, "barbershop", got.BusinessType)
+
+		byUser, err := repo.GetByUserId(ctx, userId)
+		require.NoError(t, err)
+		require.NotNil(t, byUser)
+		assert.Equal(t, id, byUser.ID)
+
+		got.Phone = "456"
+		require.NoError(t, repo.Update(ctx, id, got))
+		got, err = repo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "456", got.Phone)
+		assert.Equal(t, userId, got.UserId)
+
+		require.NoError(t, repo.Delete(ctx, id))
+		_, err = repo.Get(ctx, id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("soft delete", func(t *testing.T) {
+		userId := uniqueID("user")
+		id, err := repo.Create(ctx, &domain.Providers{UserId: userId})
+		require.NoError(t, err)
+
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		deletedAt := time.Now().UTC().AddDate(0, 0, -100).Truncate(time.Second)
+		got.DeletedAt = &deletedAt
+		require.NoError(t, repo.Update(ctx, id, got))
+
+		_, err = repo.Get(ctx, id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		byUser, err := repo.GetByUserId(ctx, userId)
+		require.NoError(t, err)
+		assert.Nil(t, byUser, "a deleted provider no longer belongs to the user")
+		_, err = repo.GetIncludingDeleted(ctx, id)
+		assert.NoError(t, err)
+
+		_, err = repo.Purge(ctx, time.Now().UTC().AddDate(0, 0, -30))
+		require.NoError(t, err)
+		_, err = repo.GetIncludingDeleted(ctx, id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("GetByUserId missing", func(t *testing.T) {
+		got, err := repo.GetByUserId(ctx, uniqueID("nobody"))
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("GetBySlug", func(t *testing.T) {
+		slug := uniqueID("barberia")
+		id, err := repo.Create(ctx, &domain.Providers{UserId: uniqueID("user"), Slug: slug})
+		require.NoError(t, err)
+
+		got, err := repo.GetBySlug(ctx, slug)
+		require.NoError(t, err)
+		assert.Equal(t, id, got.ID)
+
+		now := time.Now().UTC()
+		got.DeletedAt = &now
+		require.NoError(t, repo.Update(ctx, id, got))
+		_, err = repo.GetBySlug(ctx, slug)
+		assert.ErrorIs(t, err, domain.ErrNotFound, "deleted providers are not public")
+
+		_, err = repo.GetBySlug(ctx, uniqueID("missing"))
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		city := uniqueID("city")
+		create := func(city, businessType string) string {
+			id, err := repo.Create(ctx, &domain.Providers{UserId: uniqueID("user"), City: city, BusinessType: businessType})
+			require.NoError(t, err)
+			return id
+		}
+		barber := create(city, "barbershop")
+		clinic := create(city, "clinic")
+		create(uniqueID("elsewhere"), "barbershop")
+		deleted := create(city, "barbershop")
+		m, err := repo.Get(ctx, deleted)
+		require.NoError(t, err)
+		now := time.Now().UTC()
+		m.DeletedAt = &now
+		require.NoError(t, repo.Update(ctx, deleted, m))
+
+		ids := func(filter domain.ProviderFilter) []string {
+			var all []string
+			for pages := 0; ; pages++ {
+				require.Less(t, pages, 1000, "pagination does not terminate")
+				page, next, err := repo.Search(ctx, filter)
+				require.NoError(t, err)
+				for _, m := range page {
+					all = append(all, m.ID)
+				}
+				if next == "" {
+					return all
+				}
+				filter.Cursor = next
+			}
+		}
+
+		assert.ElementsMatch(t, []string{barber, clinic}, ids(domain.ProviderFilter{ListOptions: domain.ListOptions{Limit: 1}, City: city}))
+		assert.ElementsMatch(t, []string{barber}, ids(domain.ProviderFilter{City: city, BusinessType: "barbershop"}))
+		assert.Contains(t, ids(domain.ProviderFilter{BusinessType: "clinic", ListOptions: domain.ListOptions{Limit: domain.MaxPageSize}}), clinic)
+	})
+
+	t.Run("ListByGeohash", func(t *testing.T) {
+		cell := uniqueGeohash()
+		sibling := cell[:len(cell)-1] + "0"
+		if sibling == cell {
+			sibling = cell[:len(cell)-1] + "1"
+		}
+		create := func(geohash string) string {
+			id, err := repo.Create(ctx, &domain.Providers{UserId: uniqueID("user"), Geohash: geohash})
+			require.NoError(t, err)
+			return id
+		}
+		inside := create(cell + "x7")
+		exact := create(cell)
+		create(sibling)
+		create("")
+		deleted := create(cell + "k")
+		m, err := repo.Get(ctx, deleted)
+		require.NoError(t, err)
+		now := time.Now().UTC()
+		m.DeletedAt = &now
+		require.NoError(t, repo.Update(ctx, deleted, m))
+
+		found, err := repo.ListByGeohash(ctx, cell)
+		require.NoError(t, err)
+		var ids []string
+		for _, p := range found {
+			ids = append(ids, p.ID)
+		}
+		assert.ElementsMatch(t, []string{inside, exact}, ids)
+	})
+
+	t.Run("Get missing", func(t *testing.T) {
+		_, err := repo.Get(ctx, uniqueID("missing"))
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("List paginates", func(t *testing.T) {
+		var created []string
+		for i := 0; i < 3; i++ {
+			id, err := repo.Create(ctx, &domain.Providers{UserId: uniqueID("user")})
+			require.NoError(t, err)
+			created = append(created, id)
+		}
+
+		opts := domain.ListOptions{Limit: 2}
+		var all []string
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 1000, "pagination does not terminate")
+			page, next, err := repo.List(ctx, opts)
+			require.NoError(t, err)
+			for _, m := range page {
+				require.NotContains(t, all, m.ID)
+				all = append(all, m.ID)
+			}
+			if next == "" {
+				break
+			}
+			opts.Cursor = next
+		}
+		assert.Subset(t, all, created)
+	})
+}
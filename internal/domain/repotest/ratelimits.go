@@ -0,0 +1,36 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RateLimits checks a domain.RateLimitStore.
+func RateLimits(t *testing.T, store domain.RateLimitStore) {
+	ctx := context.Background()
+
+	t.Run("Hit counts per key and window", func(t *testing.T) {
+		key, other := uniqueID("ip"), uniqueID("ip")
+		window := time.Hour
+
+		var resetAt time.Time
+		for want := 1; want <= 3; want++ {
+			count, reset, err := store.Hit(ctx, key, window)
+			require.NoError(t, err)
+			assert.Equal(t, want, count)
+			resetAt = reset
+		}
+		assert.True(t, resetAt.After(time.Now()))
+		assert.False(t, resetAt.After(time.Now().Add(window)))
+
+		count, _, err := store.Hit(ctx, other, window)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
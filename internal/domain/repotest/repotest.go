@@ -0,0 +1,95 @@
+// Package repotest is a conformance suite for the domain repositories. Every
+// storage adapter runs it from its own tests, so behavior the handlers rely
+// on (ordering, date boundaries, provider scoping, cursors, soft deletes,
+// not-found errors)
+// stays the same whichever DB_DRIVER is selected.
+//
+// The suite does not assume an empty store: each test works with its own
+// provider IDs and only asserts on documents it created, so adapters backed
+// by a shared database can call a factory once per suite.
+package repotest
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+)
+
+// Factory builds the repositories under test. Nil fields skip that part of
+// the suite.
+type Factory struct {
+	Appointments func(t *testing.T) domain.AppointmentsRepository
+	Services     func(t *testing.T) domain.ServicesRepository
+	Providers    func(t *testing.T) domain.ProvidersRepository
+	Schedules    func(t *testing.T) domain.SchedulesRepository
+	Users        func(t *testing.T) domain.UsersRepository
+	Audit        func(t *testing.T) domain.AuditRepository
+	Holds        func(t *testing.T) domain.HoldsRepository
+	Idempotency  func(t *testing.T) domain.IdempotencyStore
+	RateLimits   func(t *testing.T) domain.RateLimitStore
+}
+
+// Run runs the suite for every repository the factory provides.
+func Run(t *testing.T, f Factory) {
+	if f.Appointments != nil {
+		t.Run("Appointments", func(t *testing.T) { Appointments(t, f.Appointments(t)) })
+	}
+	if f.Services != nil {
+		t.Run("Services", func(t *testing.T) { Services(t, f.Services(t)) })
+	}
+	if f.Providers != nil {
+		t.Run("Providers", func(t *testing.T) { Providers(t, f.Providers(t)) })
+	}
+	if f.Schedules != nil {
+		t.Run("Schedules", func(t *testing.T) { Schedules(t, f.Schedules(t)) })
+	}
+	if f.Users != nil {
+		t.Run("Users", func(t *testing.T) { Users(t, f.Users(t)) })
+	}
+	if f.Audit != nil {
+		t.Run("Audit", func(t *testing.T) { Audit(t, f.Audit(t)) })
+	}
+	if f.Holds != nil {
+		t.Run("Holds", func(t *testing.T) { Holds(t, f.Holds(t)) })
+	}
+	if f.Idempotency != nil {
+		t.Run("Idempotency", func(t *testing.T) { Idempotency(t, f.Idempotency(t)) })
+	}
+	if f.RateLimits != nil {
+		t.Run("RateLimits", func(t *testing.T) { RateLimits(t, f.RateLimits(t)) })
+	}
+}
+
+var counter atomic.Int64
+
+// uniqueID returns an ID that no other test of this run uses, so tests never
+// see each other's documents.
+func uniqueID(prefix string) string {
+	return fmt.Sprintf("%s-%d-%d", prefix, time.Now().UnixNano(), counter.Add(1))
+}
+
+// uniqueGeohash returns a geohash cell no other run uses, so earlier runs
+// against the same database do not show up in prefix queries.
+func uniqueGeohash() string {
+	const alphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+	n := uint64(time.Now().UnixNano()) + uint64(counter.Add(1))
+	b := make([]byte, 8)
+	for i := range b {
+		b[i] = alphabet[n%32]
+		n /= 32
+	}
+	return string(b)
+}
+
+// day is a fixed future date, far enough that "upcoming" listings include
+// it, in the timezone the app computes days in.
+func day() time.Time {
+	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	if err != nil {
+		loc = time.FixedZone("ART", -3*60*60)
+	}
+	return time.Date(2031, 3, 12, 0, 0, 0, 0, loc)
+}
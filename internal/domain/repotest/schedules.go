@@ -0,0 +1,55 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Schedules checks a domain.SchedulesRepository.
+func Schedules(t *testing.T, repo domain.SchedulesRepository) {
+	ctx := context.Background()
+
+	t.Run("GetByProvider missing", func(t *testing.T) {
+		got, err := repo.GetByProvider(ctx, uniqueID("prov"), domain.ScheduleTypeGlobal)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("Upsert keeps one schedule per provider and type", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		schedule := &domain.Schedule{ProviderId: providerId, Type: domain.ScheduleTypeGlobal, Days: map[string]domain.DaySchedule{
+			"mon": {Enabled: true, Ranges: []domain.TimeRange{{Start: "09:00", End: "13:00"}, {Start: "14:00", End: "18:00"}}},
+		}}
+		require.NoError(t, repo.Upsert(ctx, schedule))
+		require.NotEmpty(t, schedule.ID)
+
+		got, err := repo.GetByProvider(ctx, providerId, domain.ScheduleTypeGlobal)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, schedule.ID, got.ID)
+		assert.Equal(t, schedule.Days, got.Days)
+
+		again := &domain.Schedule{ProviderId: providerId, Type: domain.ScheduleTypeGlobal, Days: map[string]domain.DaySchedule{
+			"tue": {Enabled: true, Ranges: []domain.TimeRange{{Start: "10:00", End: "12:00"}}},
+		}}
+		require.NoError(t, repo.Upsert(ctx, again))
+		assert.Equal(t, schedule.ID, again.ID)
+
+		got, err = repo.GetByProvider(ctx, providerId, domain.ScheduleTypeGlobal)
+		require.NoError(t, err)
+		assert.Equal(t, again.Days, got.Days)
+
+		custom := &domain.Schedule{ProviderId: providerId, Type: domain.ScheduleTypeCustom, Days: map[string]domain.DaySchedule{}}
+		require.NoError(t, repo.Upsert(ctx, custom))
+		assert.NotEqual(t, schedule.ID, custom.ID)
+	})
+
+	t.Run("Upsert requires a provider", func(t *testing.T) {
+		assert.Error(t, repo.Upsert(ctx, &domain.Schedule{Type: domain.ScheduleTypeGlobal}))
+	})
+}
@@ -0,0 +1,214 @@
+package repotest
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Services checks a domain.ServicesRepository.
+func Services(t *testing.T, repo domain.ServicesRepository) {
+	ctx := context.Background()
+
+	listAll := func(t *testing.T, providerId string, limit int, includeDeleted bool) []string {
+		t.Helper()
+		opts := domain.ListOptions{Limit: limit, IncludeDeleted: includeDeleted}
+		var all []string
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 100, "pagination does not terminate")
+			page, next, err := repo.List(ctx, opts, providerId)
+			require.NoError(t, err)
+			for _, m := range page {
+				require.NotContains(t, all, m.ID)
+				all = append(all, m.ID)
+			}
+			if next == "" {
+				return all
+			}
+			opts.Cursor = next
+		}
+	}
+
+	t.Run("CRUD", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		activeFrom := time.Date(2030, 12, 1, 0, 0, 0, 0, time.UTC)
+		id, err := repo.Create(ctx, &domain.Services{
+			ProviderId:      providerId,
+			Title:           "Corte",
+			Description:     domain.Description{Markdown: "Corte *clásico*", HTML: "<p>Corte <em>clásico</em></p>"},
+			DurationMinutes: 30,
+			Price:           1500.5,
+			Color:           "#ff0000",
+			Category:        "Cortes",
+			SortOrder:       2,
+			Private:         true,
+			ActiveFrom:      &activeFrom,
+			IntakeForm: []domain.IntakeField{
+				{Id: "allergies", Label: "Alergias", Type: domain.IntakeText, Required: true},
+				{Id: "length", Label: "Largo", Type: domain.IntakeChoice, Options: []string{"corto", "largo"}},
+			},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, id)
+
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, got.ID)
+		assert.Equal(t, providerId, got.ProviderId)
+		assert.Equal(t, "Corte", got.Title)
+		assert.Equal(t, 30, got.DurationMinutes)
+		assert.Equal(t, 1500.5, got.Price)
+		assert.Equal(t, domain.Description{Markdown: "Corte *clásico*", HTML: "<p>Corte <em>clásico</em></p>"}, got.Description)
+		assert.Equal(t, "Cortes", got.Category)
+		assert.Equal(t, 2, got.SortOrder)
+		assert.True(t, got.Private)
+		require.NotNil(t, got.ActiveFrom)
+		assert.True(t, got.ActiveFrom.Equal(activeFrom))
+		assert.Nil(t, got.ActiveTo)
+		require.Len(t, got.IntakeForm, 2)
+		assert.Equal(t, []string{"corto", "largo"}, got.IntakeForm[1].Options)
+		assert.True(t, got.IntakeForm[0].Required)
+
+		got.Title = "Corte y lavado"
+		require.NoError(t, repo.Update(ctx, id, got))
+		got, err = repo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "Corte y lavado", got.Title)
+
+		require.NoError(t, repo.Delete(ctx, id))
+		_, err = repo.Get(ctx, id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("Get missing", func(t *testing.T) {
+		_, err := repo.Get(ctx, uniqueID("missing"))
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("List is scoped to the provider and paginated", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		var created []string
+		for i := 0; i < 5; i++ {
+			id, err := repo.Create(ctx, &domain.Services{ProviderId: providerId, Title: "S", DurationMinutes: 30})
+			require.NoError(t, err)
+			created = append(created, id)
+		}
+		otherId, err := repo.Create(ctx, &domain.Services{ProviderId: uniqueID("prov"), Title: "Other"})
+		require.NoError(t, err)
+
+		listed := listAll(t, providerId, 2, false)
+		assert.ElementsMatch(t, created, listed)
+		assert.NotContains(t, listed, otherId)
+
+		assert.Contains(t, listAll(t, "", domain.MaxPageSize, false), otherId, "empty provider lists every provider")
+
+		_, _, err = repo.List(ctx, domain.ListOptions{Cursor: "not a cursor"}, providerId)
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+
+	t.Run("List is ordered by sort order, then ID", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		var created []*domain.Services
+		for _, order := range []int{3, 1, 2, 1, 0} {
+			m := &domain.Services{ProviderId: providerId, Title: "S", DurationMinutes: 30, SortOrder: order}
+			id, err := repo.Create(ctx, m)
+			require.NoError(t, err)
+			m.ID = id
+			created = append(created, m)
+		}
+		sort.SliceStable(created, func(i, j int) bool {
+			if created[i].SortOrder != created[j].SortOrder {
+				return created[i].SortOrder < created[j].SortOrder
+			}
+			return created[i].ID < created[j].ID
+		})
+		var want []string
+		for _, m := range created {
+			want = append(want, m.ID)
+		}
+		assert.Equal(t, want, listAll(t, providerId, 2, false))
+	})
+
+	t.Run("Reorder renumbers the services left out", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		var ids []string
+		for _, order := range []int{0, 1, 2, 3} {
+			id, err := repo.Create(ctx, &domain.Services{ProviderId: providerId, Title: "S", SortOrder: order})
+			require.NoError(t, err)
+			ids = append(ids, id)
+		}
+		deleted := ids[3]
+		m, err := repo.Get(ctx, deleted)
+		require.NoError(t, err)
+		now := time.Now().UTC()
+		m.DeletedAt = &now
+		require.NoError(t, repo.Update(ctx, deleted, m))
+		otherId, err := repo.Create(ctx, &domain.Services{ProviderId: uniqueID("prov"), Title: "S"})
+		require.NoError(t, err)
+
+		_, err = repo.Reorder(ctx, providerId, []string{ids[2], otherId})
+		assert.ErrorIs(t, err, domain.ErrNotFound, "another provider's service")
+		_, err = repo.Reorder(ctx, providerId, []string{ids[2], deleted})
+		assert.ErrorIs(t, err, domain.ErrNotFound, "a deleted service")
+		assert.Equal(t, ids[:3], listAll(t, providerId, 10, false), "a rejected reorder writes nothing")
+
+		ordered, err := repo.Reorder(ctx, providerId, []string{ids[2]})
+		require.NoError(t, err)
+		want := []string{ids[2], ids[0], ids[1]}
+		var got []string
+		for i, m := range ordered {
+			assert.Equal(t, i, m.SortOrder)
+			got = append(got, m.ID)
+		}
+		assert.Equal(t, want, got)
+		assert.Equal(t, want, listAll(t, providerId, 10, false))
+
+		m, err = repo.GetIncludingDeleted(ctx, deleted)
+		require.NoError(t, err)
+		assert.Equal(t, 3, m.SortOrder, "deleted services are left alone")
+	})
+
+	t.Run("soft delete", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		id, err := repo.Create(ctx, &domain.Services{ProviderId: providerId, Title: "S"})
+		require.NoError(t, err)
+
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		deletedAt := time.Now().UTC().Truncate(time.Second)
+		got.DeletedAt = &deletedAt
+		require.NoError(t, repo.Update(ctx, id, got))
+
+		_, err = repo.Get(ctx, id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		got, err = repo.GetIncludingDeleted(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, got.DeletedAt)
+		assert.Empty(t, listAll(t, providerId, 0, false))
+		assert.Equal(t, []string{id}, listAll(t, providerId, 0, true))
+
+		old := time.Now().UTC().AddDate(0, 0, -100)
+		got.DeletedAt = &old
+		require.NoError(t, repo.Update(ctx, id, got))
+		purged, err := repo.Purge(ctx, time.Now().UTC().AddDate(0, 0, -30))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, purged, 1)
+		assert.Empty(t, listAll(t, providerId, 0, true))
+	})
+
+	t.Run("Import is idempotent", func(t *testing.T) {
+		providerId := uniqueID("prov")
+		models := func() []*domain.Services {
+			return []*domain.Services{{ProviderId: providerId, ExternalId: "ext-1", Title: "Imported", DurationMinutes: 45}}
+		}
+		require.NoError(t, repo.Import(ctx, models()))
+		require.NoError(t, repo.Import(ctx, models()))
+		assert.Len(t, listAll(t, providerId, 0, false), 1)
+	})
+}
@@ -0,0 +1,60 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Users checks a domain.UsersRepository.
+func Users(t *testing.T, repo domain.UsersRepository) {
+	ctx := context.Background()
+
+	t.Run("Update creates missing users", func(t *testing.T) {
+		uid := uniqueID("uid")
+		active := false
+		require.NoError(t, repo.Update(ctx, uid, &domain.Users{Email: "a@example.com", Name: "Ana", IsActive: &active}))
+
+		got, err := repo.Get(ctx, uid)
+		require.NoError(t, err)
+		assert.Equal(t, uid, got.ID)
+		assert.Equal(t, "a@example.com", got.Email)
+		require.NotNil(t, got.IsActive)
+		assert.False(t, *got.IsActive)
+
+		require.NoError(t, repo.Delete(ctx, uid))
+		_, err = repo.Get(ctx, uid)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("Create and List", func(t *testing.T) {
+		var created []string
+		for i := 0; i < 3; i++ {
+			id, err := repo.Create(ctx, &domain.Users{Email: "u@example.com"})
+			require.NoError(t, err)
+			require.NotEmpty(t, id)
+			created = append(created, id)
+		}
+
+		opts := domain.ListOptions{Limit: 2}
+		var all []string
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 1000, "pagination does not terminate")
+			page, next, err := repo.List(ctx, opts)
+			require.NoError(t, err)
+			for _, m := range page {
+				require.NotContains(t, all, m.ID)
+				all = append(all, m.ID)
+			}
+			if next == "" {
+				break
+			}
+			opts.Cursor = next
+		}
+		assert.Subset(t, all, created)
+	})
+}
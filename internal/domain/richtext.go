@@ -0,0 +1,25 @@
+package domain
+
+// Notes are the customer's or provider's notes on an appointment: free
+// text plus, for bookings made through an intake form, the answers given.
+type Notes struct {
+	Text    string         `json:"text" bson:"text" firestore:"Text"`
+	Answers []IntakeAnswer `json:"answers,omitempty" bson:"answers,omitempty" firestore:"Answers,omitempty"`
+}
+
+// IntakeAnswer is the answer to one question asked at booking time. When
+// the question is a field of the service's intake form, FieldId is its Id
+// and Question its label at the time of booking.
+type IntakeAnswer struct {
+	FieldId  string `json:"field_id,omitempty" bson:"field_id,omitempty" firestore:"FieldId,omitempty"`
+	Question string `json:"question" bson:"question" firestore:"Question"`
+	Answer   string `json:"answer" bson:"answer" firestore:"Answer"`
+}
+
+// Description is a service description written in Markdown. HTML is its
+// sanitized rendering, kept next to the source so readers never render
+// untrusted Markdown themselves; it is set by richtext.NewDescription.
+type Description struct {
+	Markdown string `json:"markdown" bson:"markdown" firestore:"Markdown"`
+	HTML     string `json:"html" bson:"html" firestore:"HTML"`
+}
@@ -6,31 +6,103 @@ import (
 )
 
 type Services struct {
-	ID string `json:"id" firestore:"-"`
+	ID string `json:"id" bson:"_id,omitempty" firestore:"-"`
 
-	ProviderId string `json:"provider_id" firestore:"ProviderId"`
+	ProviderId string `json:"provider_id" bson:"provider_id" firestore:"ProviderId"`
 
-	Description interface{} `json:"description" firestore:"Description"`
+	Description Description `json:"description" bson:"description" firestore:"Description"`
 
-	DurationMinutes int `json:"duration_minutes" firestore:"DurationMinutes"`
+	DurationMinutes int `json:"duration_minutes" bson:"duration_minutes" firestore:"DurationMinutes"`
 
-	IconUrl string `json:"icon_url" firestore:"IconUrl"`
+	IconUrl string `json:"icon_url" bson:"icon_url" firestore:"IconUrl"`
 
-	Price float64 `json:"price" firestore:"Price"`
+	Price float64 `json:"price" bson:"price" firestore:"Price"`
 
-	Color string `json:"color" firestore:"Color"`
+	Color string `json:"color" bson:"color" firestore:"Color"`
 
-	Title string `json:"title" firestore:"Title"`
+	Title string `json:"title" bson:"title" firestore:"Title"`
 
-	CreatedAt time.Time  `json:"created_at" firestore:"CreatedAt"`
-	UpdatedAt time.Time  `json:"updated_at" firestore:"UpdatedAt"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty" firestore:"DeletedAt,omitempty"`
+	// IntakeForm is asked of customers who book the service publicly.
+	IntakeForm []IntakeField `json:"intake_form" bson:"intake_form,omitempty" firestore:"IntakeForm,omitempty"`
+
+	// Category groups the service in the booking widget; empty is none.
+	Category string `json:"category" bson:"category" firestore:"Category"`
+	// SortOrder orders listings, lowest first, then by ID.
+	SortOrder int `json:"sort_order" bson:"sort_order" firestore:"SortOrder"`
+	// Private services are hidden from the booking widget and can only be
+	// booked from the dashboard.
+	Private bool `json:"private" bson:"private" firestore:"Private"`
+	// ActiveFrom and ActiveTo bound when a seasonal service is offered in the
+	// widget, as [ActiveFrom, ActiveTo). Nil means no bound.
+	ActiveFrom *time.Time `json:"active_from,omitempty" bson:"active_from,omitempty" firestore:"ActiveFrom,omitempty"`
+	ActiveTo   *time.Time `json:"active_to,omitempty" bson:"active_to,omitempty" firestore:"ActiveTo,omitempty"`
+
+	ExternalId string `json:"external_id,omitempty" bson:"external_id,omitempty" firestore:"ExternalId,omitempty"`
+
+	CreatedAt time.Time  `json:"created_at" bson:"created_at" firestore:"CreatedAt"`
+	UpdatedAt time.Time  `json:"updated_at" bson:"updated_at" firestore:"UpdatedAt"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty" firestore:"DeletedAt,omitempty"`
+}
+
+// OfferedAt reports whether t falls within the active range of s.
+func (s *Services) OfferedAt(t time.Time) bool {
+	return (s.ActiveFrom == nil || !t.Before(*s.ActiveFrom)) && (s.ActiveTo == nil || t.Before(*s.ActiveTo))
+}
+
+// Listed reports whether the booking widget shows s at t.
+func (s *Services) Listed(t time.Time) bool {
+	return !s.Private && s.OfferedAt(t)
+}
+
+// ReorderServices puts the services named by ids first, in that order, and
+// the others after them in their current order, renumbering SortOrder from
+// 0. services must be in listing order. It returns the services in their new
+// order and those whose SortOrder changed, or ErrNotFound when an ID is not
+// among services.
+func ReorderServices(services []*Services, ids []string) (ordered, changed []*Services, err error) {
+	byId := make(map[string]*Services, len(services))
+	for _, s := range services {
+		byId[s.ID] = s
+	}
+	placed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		s, ok := byId[id]
+		if !ok {
+			return nil, nil, ErrNotFound
+		}
+		if !placed[id] {
+			placed[id] = true
+			ordered = append(ordered, s)
+		}
+	}
+	for _, s := range services {
+		if !placed[s.ID] {
+			ordered = append(ordered, s)
+		}
+	}
+	for i, s := range ordered {
+		if s.SortOrder != i {
+			s.SortOrder = i
+			changed = append(changed, s)
+		}
+	}
+	return ordered, changed, nil
 }
 
 type ServicesRepository interface {
-	List(ctx context.Context, limit, offset int, providerId string) ([]*Services, error)
+	// List returns services ordered by SortOrder and then by ID.
+	List(ctx context.Context, opts ListOptions, providerId string) ([]*Services, string, error)
 	Get(ctx context.Context, id string) (*Services, error)
+	GetIncludingDeleted(ctx context.Context, id string) (*Services, error)
 	Create(ctx context.Context, model *Services) (string, error)
 	Update(ctx context.Context, id string, model *Services) error
 	Delete(ctx context.Context, id string) error
+	// Reorder renumbers the live services of the provider as
+	// ReorderServices does, writing them all or none, and returns them in
+	// their new order.
+	Reorder(ctx context.Context, providerId string, ids []string) ([]*Services, error)
+	// Import creates or replaces models in batches, keyed by ProviderId and
+	// ExternalId so that running the same import twice does not duplicate.
+	Import(ctx context.Context, models []*Services) error
+	Purger
 }
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Get when the document does not exist or is
+// soft-deleted.
+var ErrNotFound = errors.New("not found")
+
+// Soft deletes: setting DeletedAt through Update hides a document from
+// Get, List and ListByDate. Listings return it again with
+// ListOptions.IncludeDeleted and GetIncludingDeleted reads it by ID, which is
+// how it gets restored (by clearing DeletedAt through Update). Purge removes
+// documents deleted before a cutoff for good.
+
+// Purger is implemented by repositories whose documents are soft-deleted.
+type Purger interface {
+	// Purge hard-deletes documents soft-deleted before deletedBefore and
+	// returns how many were removed.
+	Purge(ctx context.Context, deletedBefore time.Time) (int, error)
+}
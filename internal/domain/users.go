@@ -25,7 +25,7 @@ type Users struct {
 }
 
 type UsersRepository interface {
-	List(ctx context.Context, limit, offset int) ([]*Users, error)
+	List(ctx context.Context, opts ListOptions) ([]*Users, string, error)
 	Get(ctx context.Context, id string) (*Users, error)
 	Create(ctx context.Context, model *Users) (string, error)
 	Update(ctx context.Context, id string, model *Users) error
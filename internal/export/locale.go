@@ -0,0 +1,103 @@
+package export
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"ServiceBookingApp/internal/utils"
+)
+
+// Locale controls how dates and numbers are rendered in text outputs and
+// which column titles are used.
+type Locale struct {
+	Name           string
+	DateTimeLayout string
+	DecimalSep     string
+	ThousandsSep   string
+	// CSVComma is the field separator. Spreadsheet apps configured for
+	// locales that use a decimal comma expect ';'.
+	CSVComma rune
+	// XLSXDateFormat is the number format applied to date cells.
+	XLSXDateFormat string
+	Headers        map[string]string
+}
+
+var (
+	LocaleDefault = Locale{
+		Name:           "en",
+		DateTimeLayout: "2006-01-02 15:04",
+		DecimalSep:     ".",
+		ThousandsSep:   "",
+		CSVComma:       ',',
+		XLSXDateFormat: "yyyy-mm-dd hh:mm",
+	}
+
+	LocaleESAR = Locale{
+		Name:           "es-AR",
+		DateTimeLayout: "02/01/2006 15:04",
+		DecimalSep:     ",",
+		ThousandsSep:   ".",
+		CSVComma:       ';',
+		XLSXDateFormat: "dd/mm/yyyy hh:mm",
+		Headers: map[string]string{
+			"scheduled_at":     "Fecha",
+			"service":          "Servicio",
+			"duration_minutes": "Duración (min)",
+			"price":            "Precio",
+			"status":           "Estado",
+			"customer_name":    "Cliente",
+			"customer_email":   "Email",
+			"customer_phone":   "Teléfono",
+			"appointments":     "Turnos",
+			"last_appointment": "Último turno",
+			"total_spent":      "Total gastado",
+		},
+	}
+)
+
+// LookupLocale returns the locale for a tag such as "es-AR", falling back to
+// LocaleDefault for unknown or empty tags.
+func LookupLocale(tag string) Locale {
+	if strings.EqualFold(tag, LocaleESAR.Name) {
+		return LocaleESAR
+	}
+	return LocaleDefault
+}
+
+func (l Locale) Header(key string) string {
+	if h, ok := l.Headers[key]; ok {
+		return h
+	}
+	return key
+}
+
+func (l Locale) FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(utils.ArgentinaLocation).Format(l.DateTimeLayout)
+}
+
+// FormatNumber renders v with two decimals using the locale separators.
+func (l Locale) FormatNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	intPart, frac, _ := strings.Cut(s, ".")
+
+	neg := strings.HasPrefix(intPart, "-")
+	intPart = strings.TrimPrefix(intPart, "-")
+	if l.ThousandsSep != "" {
+		var b strings.Builder
+		for i, d := range intPart {
+			if i > 0 && (len(intPart)-i)%3 == 0 {
+				b.WriteString(l.ThousandsSep)
+			}
+			b.WriteRune(d)
+		}
+		intPart = b.String()
+	}
+	if neg {
+		intPart = "-" + intPart
+	}
+	return intPart + l.DecimalSep + frac
+}
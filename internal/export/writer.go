@@ -0,0 +1,186 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Writer streams a table row by row. Cells may be string, int, float64 or
+// time.Time; each writer renders them natively for its format.
+type Writer interface {
+	WriteHeader(keys []string) error
+	WriteRow(cells []interface{}) error
+	// Close flushes any buffered output. It must be called once all rows
+	// have been written.
+	Close() error
+}
+
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+func NewWriter(format string, w io.Writer, locale Locale) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, locale), nil
+	case FormatXLSX:
+		return newXLSXWriter(w, locale)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// escapeFormula prefixes text that a spreadsheet would run as a formula
+// with a quote, so values customers typed into the public widget, such as
+// a name starting with "=", are shown instead of evaluated.
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	locale Locale
+}
+
+func newCSVWriter(w io.Writer, locale Locale) *csvWriter {
+	cw := csv.NewWriter(w)
+	cw.Comma = locale.CSVComma
+	return &csvWriter{w: cw, locale: locale}
+}
+
+func (c *csvWriter) WriteHeader(keys []string) error {
+	headers := make([]string, len(keys))
+	for i, k := range keys {
+		headers[i] = c.locale.Header(k)
+	}
+	return c.w.Write(headers)
+}
+
+func (c *csvWriter) WriteRow(cells []interface{}) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		switch v := cell.(type) {
+		case string:
+			record[i] = escapeFormula(v)
+		case int:
+			record[i] = strconv.Itoa(v)
+		case float64:
+			record[i] = c.locale.FormatNumber(v)
+		case time.Time:
+			record[i] = c.locale.FormatTime(v)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+	// Flush every row so the response is streamed to the client instead of
+	// accumulating in the csv buffer.
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// xlsxWriter uses the excelize stream writer, which spills rows to a
+// temporary file instead of keeping the sheet in memory. The workbook is
+// only written to out on Close because XLSX is a zip archive.
+type xlsxWriter struct {
+	out       io.Writer
+	file      *excelize.File
+	sw        *excelize.StreamWriter
+	locale    Locale
+	row       int
+	dateStyle int
+	numStyle  int
+}
+
+func newXLSXWriter(out io.Writer, locale Locale) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		return nil, err
+	}
+	dateFormat := locale.XLSXDateFormat
+	dateStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		return nil, err
+	}
+	numStyle, err := f.NewStyle(&excelize.Style{NumFmt: 4})
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{out: out, file: f, sw: sw, locale: locale, dateStyle: dateStyle, numStyle: numStyle}, nil
+}
+
+func (x *xlsxWriter) WriteHeader(keys []string) error {
+	headers := make([]interface{}, len(keys))
+	for i, k := range keys {
+		headers[i] = x.locale.Header(k)
+	}
+	return x.writeRow(headers)
+}
+
+func (x *xlsxWriter) WriteRow(cells []interface{}) error {
+	row := make([]interface{}, len(cells))
+	for i, cell := range cells {
+		switch v := cell.(type) {
+		case time.Time:
+			if v.IsZero() {
+				row[i] = ""
+				continue
+			}
+			// Excel has no time zones; store the Argentina wall clock.
+			local := v.In(utils.ArgentinaLocation)
+			wall := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), 0, time.UTC)
+			row[i] = excelize.Cell{StyleID: x.dateStyle, Value: wall}
+		case float64:
+			row[i] = excelize.Cell{StyleID: x.numStyle, Value: v}
+		case string:
+			row[i] = escapeFormula(v)
+		default:
+			row[i] = v
+		}
+	}
+	return x.writeRow(row)
+}
+
+func (x *xlsxWriter) writeRow(values []interface{}) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.sw.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.out)
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "1234567.50", LocaleDefault.FormatNumber(1234567.5))
+	assert.Equal(t, "1.234.567,50", LocaleESAR.FormatNumber(1234567.5))
+	assert.Equal(t, "-950,00", LocaleESAR.FormatNumber(-950))
+	assert.Equal(t, "0,25", LocaleESAR.FormatNumber(0.25))
+}
+
+func TestCSVWriterESAR(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatCSV, &buf, LocaleESAR)
+	assert.NoError(t, err)
+
+	when := time.Date(2024, 3, 5, 17, 30, 0, 0, utils.ArgentinaLocation)
+	assert.NoError(t, w.WriteHeader([]string{"scheduled_at", "service", "price"}))
+	assert.NoError(t, w.WriteRow([]interface{}{when, "Corte; barba", 12500.0}))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, "Fecha;Servicio;Precio\n05/03/2024 17:30;\"Corte; barba\";12.500,00\n", buf.String())
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatXLSX, &buf, LocaleDefault)
+	assert.NoError(t, err)
+
+	assert.NoError(t, w.WriteHeader([]string{"service", "duration_minutes"}))
+	assert.NoError(t, w.WriteRow([]interface{}{"Corte", 30}))
+	assert.NoError(t, w.Close())
+
+	f, err := excelize.OpenReader(&buf)
+	assert.NoError(t, err)
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"service", "duration_minutes"}, {"Corte", "30"}}, rows)
+}
+
+func TestWritersEscapeFormulas(t *testing.T) {
+	row := []interface{}{"=HYPERLINK(\"http://x\")", "+54 341", "-1", "@SUM(A1)", "\tcmd", "Ana", -950.0}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatCSV, &buf, LocaleDefault)
+	assert.NoError(t, err)
+	assert.NoError(t, w.WriteRow(row))
+	assert.NoError(t, w.Close())
+	assert.Equal(t, "\"'=HYPERLINK(\"\"http://x\"\")\",'+54 341,'-1,'@SUM(A1),'\tcmd,Ana,-950.00\n", buf.String())
+
+	buf.Reset()
+	w, err = NewWriter(FormatXLSX, &buf, LocaleDefault)
+	assert.NoError(t, err)
+	assert.NoError(t, w.WriteRow(row))
+	assert.NoError(t, w.Close())
+	f, err := excelize.OpenReader(&buf)
+	assert.NoError(t, err)
+	formula, err := f.GetCellFormula("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Empty(t, formula)
+	value, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "'=HYPERLINK(\"http://x\")", value)
+}
@@ -0,0 +1,110 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrAddressNotFound is returned when a geocoder has no match for an
+// address.
+var ErrAddressNotFound = errors.New("address not found")
+
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Geocoder turns a postal address into coordinates.
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (Point, error)
+}
+
+// NewGeocoder returns the geocoder called name: "nominatim", or nil for ""
+// when addresses are not geocoded at all.
+func NewGeocoder(name, baseURL string) (Geocoder, error) {
+	switch name {
+	case "nominatim":
+		return NewNominatimGeocoder(baseURL), nil
+	case "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown geocoder %q", name)
+	}
+}
+
+// StaticGeocoder answers from a fixed table, keyed by address. It is the
+// fake used by tests and local runs.
+type StaticGeocoder map[string]Point
+
+func (g StaticGeocoder) Geocode(ctx context.Context, address string) (Point, error) {
+	p, ok := g[strings.TrimSpace(address)]
+	if !ok {
+		return Point{}, ErrAddressNotFound
+	}
+	return p, nil
+}
+
+// NominatimGeocoder queries an OpenStreetMap Nominatim server. Its usage
+// policy asks for an identifying User-Agent and at most one request per
+// second, which provider edits stay well below.
+type NominatimGeocoder struct {
+	BaseURL   string
+	UserAgent string
+	Client    *http.Client
+}
+
+func NewNominatimGeocoder(baseURL string) *NominatimGeocoder {
+	if baseURL == "" {
+		baseURL = "https://nominatim.openstreetmap.org"
+	}
+	return &NominatimGeocoder{
+		BaseURL:   strings.TrimRight(baseURL, "/"),
+		UserAgent: "ServiceBookingApp",
+		Client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (g *NominatimGeocoder) Geocode(ctx context.Context, address string) (Point, error) {
+	q := url.Values{"q": {address}, "format": {"json"}, "limit": {"1"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.BaseURL+"/search?"+q.Encode(), nil)
+	if err != nil {
+		return Point{}, err
+	}
+	req.Header.Set("User-Agent", g.UserAgent)
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return Point{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Point{}, fmt.Errorf("nominatim: %s", resp.Status)
+	}
+
+	var results []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return Point{}, fmt.Errorf("nominatim: %w", err)
+	}
+	if len(results) == 0 {
+		return Point{}, ErrAddressNotFound
+	}
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("nominatim: %w", err)
+	}
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("nominatim: %w", err)
+	}
+	return Point{Lat: lat, Lng: lng}, nil
+}
@@ -0,0 +1,130 @@
+package geo
+
+import (
+	"math"
+	"strings"
+)
+
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Precision is the geohash length stored on providers. At 9 characters a
+// cell is about 5 m across, finer than any radius we search by.
+const Precision = 9
+
+// Encode returns the geohash of lat/lng with the given number of
+// characters.
+func Encode(lat, lng float64, precision int) string {
+	latRange := [2]float64{-90, 90}
+	lngRange := [2]float64{-180, 180}
+
+	var b strings.Builder
+	bit, ch := 0, 0
+	even := true
+	for b.Len() < precision {
+		r, v := &latRange, lat
+		if even {
+			r, v = &lngRange, lng
+		}
+		mid := (r[0] + r[1]) / 2
+		if v >= mid {
+			ch |= 1 << (4 - bit)
+			r[0] = mid
+		} else {
+			r[1] = mid
+		}
+		even = !even
+		if bit < 4 {
+			bit++
+			continue
+		}
+		b.WriteByte(base32[ch])
+		bit, ch = 0, 0
+	}
+	return b.String()
+}
+
+// bounds returns the box covered by hash.
+func bounds(hash string) (latMin, latMax, lngMin, lngMax float64) {
+	latMin, latMax, lngMin, lngMax = -90, 90, -180, 180
+	even := true
+	for i := 0; i < len(hash); i++ {
+		idx := strings.IndexByte(base32, hash[i])
+		for bit := 4; bit >= 0; bit-- {
+			on := idx&(1<<bit) != 0
+			if even {
+				mid := (lngMin + lngMax) / 2
+				if on {
+					lngMin = mid
+				} else {
+					lngMax = mid
+				}
+			} else {
+				mid := (latMin + latMax) / 2
+				if on {
+					latMin = mid
+				} else {
+					latMax = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return latMin, latMax, lngMin, lngMax
+}
+
+// Cell sizes at the equator for each geohash length. Cells get narrower
+// towards the poles, by the cosine of the latitude.
+var (
+	cellHeightKm = []float64{0, 5000, 625, 156, 19.5, 4.89, 0.61, 0.153, 0.019, 0.0048}
+	cellWidthKm  = []float64{0, 5000, 1250, 156, 39.1, 4.89, 1.22, 0.153, 0.038, 0.0048}
+)
+
+// CoveringPrefixes returns the geohash cells that together contain every
+// point within radiusKm of lat/lng: the cell of the centre, at the finest
+// length whose cells are at least radiusKm across, and its eight
+// neighbours. Querying each prefix as a range on the stored geohash finds
+// the candidates; callers still filter them by actual distance.
+func CoveringPrefixes(lat, lng, radiusKm float64) []string {
+	shrink := math.Cos(lat * math.Pi / 180)
+	precision := 1
+	for p := len(cellHeightKm) - 1; p >= 1; p-- {
+		if cellHeightKm[p] >= radiusKm && cellWidthKm[p]*shrink >= radiusKm {
+			precision = p
+			break
+		}
+	}
+
+	center := Encode(lat, lng, precision)
+	latMin, latMax, lngMin, lngMax := bounds(center)
+	dLat, dLng := latMax-latMin, lngMax-lngMin
+	cLat, cLng := (latMin+latMax)/2, (lngMin+lngMax)/2
+
+	seen := map[string]bool{}
+	var prefixes []string
+	for i := -1; i <= 1; i++ {
+		nLat := cLat + float64(i)*dLat
+		if nLat < -90 || nLat > 90 {
+			continue
+		}
+		for j := -1; j <= 1; j++ {
+			nLng := math.Mod(cLng+float64(j)*dLng+540, 360) - 180
+			hash := Encode(nLat, nLng, precision)
+			if !seen[hash] {
+				seen[hash] = true
+				prefixes = append(prefixes, hash)
+			}
+		}
+	}
+	return prefixes
+}
+
+const earthRadiusKm = 6371.0
+
+// DistanceKm is the great-circle distance between two points.
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
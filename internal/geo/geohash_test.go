@@ -0,0 +1,124 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	assert.Equal(t, "ezs42", Encode(42.605, -5.603, 5))
+	assert.Equal(t, "69y7pkxff", Encode(-34.6037, -58.3816, 9))
+
+	latMin, latMax, lngMin, lngMax := bounds("ezs42")
+	assert.True(t, latMin <= 42.605 && 42.605 <= latMax)
+	assert.True(t, lngMin <= -5.603 && -5.603 <= lngMax)
+}
+
+func TestDistanceKm(t *testing.T) {
+	// Buenos Aires to Montevideo.
+	assert.InDelta(t, 206, DistanceKm(-34.6037, -58.3816, -34.9011, -56.1645), 2)
+	assert.Zero(t, DistanceKm(10, 10, 10, 10))
+}
+
+// Every point within the radius must fall in one of the covering cells, or
+// radius searches would miss providers near cell edges.
+func TestCoveringPrefixesContainTheCircle(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		lat := rng.Float64()*140 - 70
+		lng := rng.Float64()*360 - 180
+		radius := []float64{0.5, 3, 10, 50}[i%4]
+
+		prefixes := CoveringPrefixes(lat, lng, radius)
+		require.NotEmpty(t, prefixes)
+
+		// A point at the edge of the circle in a random direction.
+		bearing := rng.Float64() * 2 * math.Pi
+		d := radius * 0.999 / earthRadiusKm
+		lat1, lng1 := lat*math.Pi/180, lng*math.Pi/180
+		lat2 := math.Asin(math.Sin(lat1)*math.Cos(d) + math.Cos(lat1)*math.Sin(d)*math.Cos(bearing))
+		lng2 := lng1 + math.Atan2(math.Sin(bearing)*math.Sin(d)*math.Cos(lat1), math.Cos(d)-math.Sin(lat1)*math.Sin(lat2))
+		hash := Encode(lat2*180/math.Pi, math.Mod(lng2*180/math.Pi+540, 360)-180, Precision)
+
+		found := false
+		for _, p := range prefixes {
+			if strings.HasPrefix(hash, p) {
+				found = true
+				break
+			}
+		}
+		require.True(t, found, "lat %f lng %f radius %f", lat, lng, radius)
+	}
+}
+
+func TestProvidersRepositoryGeocodesAddress(t *testing.T) {
+	ctx := context.Background()
+	geocoder := StaticGeocoder{
+		"Corrientes 1234, Buenos Aires": {Lat: -34.6037, Lng: -58.3816},
+		"18 de Julio 1000, Montevideo":  {Lat: -34.9011, Lng: -56.1645},
+	}
+	repo := NewProvidersRepository(memory.NewProvidersRepository(memory.NewStore()), geocoder)
+
+	p := &domain.Providers{Address: "Corrientes 1234", City: "Buenos Aires"}
+	id, err := repo.Create(ctx, p)
+	require.NoError(t, err)
+	got, err := repo.Get(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, -34.6037, got.Latitude)
+	assert.Equal(t, "69y7pkxff", got.Geohash)
+
+	// Saving without an address change keeps the location even if the
+	// caller did not send it back.
+	got.Latitude, got.Longitude, got.Geohash = 0, 0, ""
+	got.Phone = "123"
+	require.NoError(t, repo.Update(ctx, id, got))
+	got, err = repo.Get(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "69y7pkxff", got.Geohash)
+
+	got.Address, got.City = "18 de Julio 1000", "Montevideo"
+	require.NoError(t, repo.Update(ctx, id, got))
+	got, err = repo.Get(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, -56.1645, got.Longitude)
+
+	// A failed lookup neither fails the write nor drops the provider from
+	// radius searches.
+	montevideo := got.Geohash
+	got.Address = "Nowhere 1"
+	require.NoError(t, repo.Update(ctx, id, got), "an unknown address does not fail the write")
+	got, err = repo.Get(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, montevideo, got.Geohash)
+
+	down := NewProvidersRepository(repo.ProvidersRepository, downGeocoder{})
+	got.Address = "Corrientes 1234"
+	require.NoError(t, down.Update(ctx, id, got))
+	got, err = down.Get(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, montevideo, got.Geohash, "a geocoder outage keeps the location")
+
+	got.Address = ""
+	require.NoError(t, repo.Update(ctx, id, got))
+	got, err = repo.Get(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, got.Geohash, "clearing the address clears the location")
+	assert.Zero(t, got.Latitude)
+}
+
+// downGeocoder fails every lookup, like a geocoder outage.
+type downGeocoder struct{}
+
+func (downGeocoder) Geocode(ctx context.Context, address string) (Point, error) {
+	return Point{}, errors.New("unavailable")
+}
@@ -0,0 +1,69 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"ServiceBookingApp/internal/domain"
+)
+
+// ProvidersRepository geocodes the address of every provider it saves, so
+// Latitude, Longitude and Geohash always follow Address whichever handler
+// wrote it.
+type ProvidersRepository struct {
+	domain.ProvidersRepository
+	geocoder Geocoder
+}
+
+func NewProvidersRepository(repo domain.ProvidersRepository, geocoder Geocoder) *ProvidersRepository {
+	return &ProvidersRepository{ProvidersRepository: repo, geocoder: geocoder}
+}
+
+func (r *ProvidersRepository) Create(ctx context.Context, m *domain.Providers) (string, error) {
+	m.Latitude, m.Longitude, m.Geohash = 0, 0, ""
+	Locate(ctx, r.geocoder, m)
+	return r.ProvidersRepository.Create(ctx, m)
+}
+
+// Update keeps the stored location unless the address or city changed and
+// the new address could be geocoded, so a geocoder outage never drops a
+// provider from radius searches. Clearing the address clears the location.
+func (r *ProvidersRepository) Update(ctx context.Context, id string, m *domain.Providers) error {
+	before, err := r.ProvidersRepository.GetIncludingDeleted(ctx, id)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+	if before != nil {
+		m.Latitude, m.Longitude, m.Geohash = before.Latitude, before.Longitude, before.Geohash
+	}
+	switch {
+	case m.Address == "":
+		m.Latitude, m.Longitude, m.Geohash = 0, 0, ""
+	case before == nil || before.Address != m.Address || before.City != m.City:
+		Locate(ctx, r.geocoder, m)
+	}
+	return r.ProvidersRepository.Update(ctx, id, m)
+}
+
+// Locate sets the location of m from its address and reports whether it
+// did. A failed lookup leaves m untouched rather than failing the write.
+func Locate(ctx context.Context, geocoder Geocoder, m *domain.Providers) bool {
+	if m.Address == "" {
+		return false
+	}
+	address := m.Address
+	if m.City != "" {
+		address += ", " + m.City
+	}
+	p, err := geocoder.Geocode(ctx, address)
+	if err != nil {
+		if !errors.Is(err, ErrAddressNotFound) {
+			log.Printf("geocoding %q: %v", address, err)
+		}
+		return false
+	}
+	m.Latitude, m.Longitude = p.Lat, p.Lng
+	m.Geohash = Encode(p.Lat, p.Lng, Precision)
+	return true
+}
@@ -1,15 +1,18 @@
 package appointments
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
+	"ServiceBookingApp/internal/apperrors"
+	authService "ServiceBookingApp/internal/auth"
+	"ServiceBookingApp/internal/booking"
 	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/requests"
 	"ServiceBookingApp/internal/utils"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,127 +21,157 @@ type AppointmentsHandler struct {
 	servicesRepo  domain.ServicesRepository
 	providersRepo domain.ProvidersRepository
 	schedulesRepo domain.SchedulesRepository
+	booker        *booking.Booker
+	policy        *authService.Policy
 }
 
-func NewAppointmentsHandler(repo domain.AppointmentsRepository, servicesRepo domain.ServicesRepository, providersRepo domain.ProvidersRepository, schedulesRepo domain.SchedulesRepository) *AppointmentsHandler {
+// NewAppointmentsHandler returns the dashboard's appointment handlers. Its
+// Booker counts the holds of holdsRepo as taken but, like the provider's
+// other entry points, does not hold bookings to business hours.
+func NewAppointmentsHandler(repo domain.AppointmentsRepository, servicesRepo domain.ServicesRepository, providersRepo domain.ProvidersRepository, schedulesRepo domain.SchedulesRepository, holdsRepo domain.HoldsRepository) *AppointmentsHandler {
 	return &AppointmentsHandler{
 		repo:          repo,
 		servicesRepo:  servicesRepo,
 		providersRepo: providersRepo,
 		schedulesRepo: schedulesRepo,
+		booker:        booking.NewBooker(repo, servicesRepo, holdsRepo, nil),
+		policy:        authService.NewPolicy(providersRepo),
 	}
 }
 
 func (h *AppointmentsHandler) getProviderID(c *gin.Context) (string, error) {
-	u, exists := c.Get("user")
-	if !exists {
-		return "", fmt.Errorf("user not found in context")
-	}
-	token := u.(*auth.Token)
-	
-	provider, err := h.providersRepo.GetByUserId(c.Request.Context(), token.UID)
+	provider, err := h.policy.CallerProvider(c)
 	if err != nil {
 		return "", err
 	}
-	if provider == nil {
-		return "", fmt.Errorf("user is not a provider")
-	}
 	return provider.ID, nil
 }
 
 func (h *AppointmentsHandler) List(c *gin.Context) {
 	reqProviderId := c.Query("provider_id")
 	if reqProviderId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "provider_id is required"})
+		apperrors.Abort(c, apperrors.InvalidQuery("provider_id is required"))
 		return
 	}
 
-	providerId, err := h.getProviderID(c)
-	if err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "must be a provider to list appointments"})
+	if !h.policy.AuthorizeProvider(c, reqProviderId) {
 		return
 	}
+	providerId := reqProviderId
 
-	if reqProviderId != providerId {
-		c.JSON(http.StatusForbidden, gin.H{"error": "cannot view appointments of another provider"})
+	includeDeleted, ok := authService.IncludeDeleted(c)
+	if !ok {
 		return
 	}
 
-	limit := 20
+	filter := domain.AppointmentsFilter{
+		ListOptions: domain.ListOptions{Cursor: c.Query("cursor"), IncludeDeleted: includeDeleted},
+		ProviderId:  providerId,
+		Type:        c.Query("type"),
+		Status:      c.Query("status"),
+		ServiceId:   c.Query("service_id"),
+		Customer:    c.Query("customer"),
+	}
 	if l := c.Query("limit"); l != "" {
 		if val, err := strconv.Atoi(l); err == nil && val > 0 {
-			limit = val
+			filter.Limit = val
 		}
 	}
-
-	offset := 0
-	if o := c.Query("offset"); o != "" {
-		if val, err := strconv.Atoi(o); err == nil && val >= 0 {
-			offset = val
+	if from := c.Query("from"); from != "" {
+		t, err := utils.ParseDateOrTime(from)
+		if err != nil {
+			apperrors.Abort(c, apperrors.InvalidQuery("invalid from date"))
+			return
 		}
-	} else {
-		page := 1
-		if p := c.Query("page"); p != "" {
-			if val, err := strconv.Atoi(p); err == nil && val > 0 {
-				page = val
-			}
+		filter.From = t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := utils.ParseDateOrTime(to)
+		if err != nil {
+			apperrors.Abort(c, apperrors.InvalidQuery("invalid to date"))
+			return
 		}
-		offset = (page - 1) * limit
+		filter.To = t
 	}
 
-	filterType := c.Query("type")
-
-	results, err := h.repo.List(c.Request.Context(), limit, offset, filterType, providerId)
+	results, nextCursor, err := h.repo.List(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, results)
+	c.JSON(http.StatusOK, gin.H{"data": results, "next_cursor": nextCursor})
 }
 
+// Get godoc
+// @Summary Get Appointment
+// @Description Get an appointment of the authenticated provider, with its notes and intake answers
+// @Tags Appointments
+// @Produce  json
+// @Param id path string true "Appointment ID"
+// @Success 200 {object} domain.Appointments
+// @Failure 404 {object} apperrors.Problem
+// @Router /api/appointments/{id} [get]
 func (h *AppointmentsHandler) Get(c *gin.Context) {
 	id := c.Param("id")
-	result, err := h.repo.Get(c.Request.Context(), id)
+	includeDeleted, ok := authService.IncludeDeleted(c)
+	if !ok {
+		return
+	}
+
+	get := h.repo.Get
+	if includeDeleted {
+		get = h.repo.GetIncludingDeleted
+	}
+	result, err := get(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeAppointmentNotFound, "appointment not found"))
+		return
+	}
+	if !h.policy.AuthorizeProvider(c, result.ProviderId) {
 		return
 	}
 	c.JSON(http.StatusOK, result)
 }
 
+// Create godoc
+// @Summary Create Appointment
+// @Description Book an appointment for one of the provider's services
+// @Tags Appointments
+// @Accept  json
+// @Produce  json
+// @Param body body requests.CreateAppointment true "Appointment"
+// @Success 201 {object} domain.Appointments
+// @Failure 400 {object} apperrors.Problem
+// @Failure 409 {object} apperrors.Problem
+// @Router /api/appointments [post]
 func (h *AppointmentsHandler) Create(c *gin.Context) {
-	var m domain.Appointments
-	if err := c.ShouldBindJSON(&m); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req requests.CreateAppointment
+	if err := requests.Bind(c, &req); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
+	m := req.ToDomain()
 
-	if m.ServiceId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "service_id is required"})
+	service, err := h.servicesRepo.Get(c.Request.Context(), m.ServiceId)
+	if errors.Is(err, domain.ErrNotFound) {
+		apperrors.Abort(c, booking.ErrInvalidService)
 		return
 	}
-
-	service, err := h.servicesRepo.Get(c.Request.Context(), m.ServiceId)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service_id"})
+		apperrors.Abort(c, err)
 		return
 	}
-	m.ProviderId = service.ProviderId
-	m.ServiceName = service.Title
-	m.DurationMinutes = service.DurationMinutes
-	if m.DurationMinutes == 0 {
-		m.DurationMinutes = 30
+	if !h.policy.AuthorizeProvider(c, service.ProviderId) {
+		return
 	}
-
-	now := utils.Now()
-	if m.ScheduledAt.Before(now.Add(-5 * time.Minute)) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot create appointment in the past"})
+	if err := h.booker.Prepare(c.Request.Context(), service.ProviderId, m); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
 
-	id, err := h.repo.Create(c.Request.Context(), &m)
+	id, err := h.repo.Create(c.Request.Context(), m)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 	m.ID = id
@@ -150,20 +183,23 @@ func (h *AppointmentsHandler) Update(c *gin.Context) {
 	
 	existing, err := h.repo.Get(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "appointment not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeAppointmentNotFound, "appointment not found"))
+		return
+	}
+	if !h.policy.AuthorizeProvider(c, existing.ProviderId) {
 		return
 	}
 	
-	var updates domain.Appointments
-	if err := c.ShouldBindJSON(&updates); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req requests.UpdateAppointment
+	if err := requests.Bind(c, &req); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
 	
-	if updates.Status != "" {
-		existing.Status = updates.Status
+	if req.Status != "" {
+		existing.Status = req.Status
 		
-		if updates.Status == "cancelled" {
+		if req.Status == "cancelled" {
 			now := utils.Now()
 			existing.DeletedAt = &now
 		}
@@ -172,7 +208,7 @@ func (h *AppointmentsHandler) Update(c *gin.Context) {
 	existing.UpdatedAt = utils.Now()
 	
 	if err := h.repo.Update(c.Request.Context(), id, existing); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 	
@@ -184,7 +220,10 @@ func (h *AppointmentsHandler) Delete(c *gin.Context) {
 	
 	appointment, err := h.repo.Get(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "appointment not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeAppointmentNotFound, "appointment not found"))
+		return
+	}
+	if !h.policy.AuthorizeProvider(c, appointment.ProviderId) {
 		return
 	}
 	
@@ -192,20 +231,54 @@ func (h *AppointmentsHandler) Delete(c *gin.Context) {
 	appointment.DeletedAt = &now
 	
 	if err := h.repo.Update(c.Request.Context(), id, appointment); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 	
 	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
 }
 
+// Restore brings back a deleted or cancelled appointment as confirmed, as
+// long as its slot is still free.
+func (h *AppointmentsHandler) Restore(c *gin.Context) {
+	id := c.Param("id")
+
+	appointment, err := h.repo.GetIncludingDeleted(c.Request.Context(), id)
+	if err != nil {
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeAppointmentNotFound, "appointment not found"))
+		return
+	}
+	if !h.policy.AuthorizeProvider(c, appointment.ProviderId) {
+		return
+	}
+	if appointment.DeletedAt == nil {
+		apperrors.Abort(c, apperrors.New(http.StatusConflict, apperrors.CodeNotDeleted, "appointment is not deleted"))
+		return
+	}
+
+	appointment.DeletedAt = nil
+	if appointment.Status == domain.StatusCancelled {
+		appointment.Status = domain.StatusConfirmed
+	}
+	if err := h.booker.CheckConflicts(c.Request.Context(), appointment); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	if err := h.repo.Update(c.Request.Context(), id, appointment); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, appointment)
+}
+
 func (h *AppointmentsHandler) GetAvailableSlots(c *gin.Context) {
 	dateStr := c.Query("date")
 	serviceID := c.Query("service")
 	tzOffsetStr := c.Query("timezone_offset")
 
 	if dateStr == "" || serviceID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "date and service are required"})
+		apperrors.Abort(c, apperrors.InvalidQuery("date and service are required"))
 		return
 	}
 
@@ -221,113 +294,31 @@ func (h *AppointmentsHandler) GetAvailableSlots(c *gin.Context) {
 	if err != nil {
 		date, err = time.Parse(time.RFC3339, dateStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format"})
+			apperrors.Abort(c, apperrors.InvalidQuery("invalid date format"))
 			return
 		}
-		loc = date.Location()
 	}
 
 	service, err := h.servicesRepo.Get(c.Request.Context(), serviceID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeServiceNotFound, "service not found"))
+		return
+	}
+	if !h.policy.AuthorizeProvider(c, service.ProviderId) {
 		return
 	}
 	providerId := service.ProviderId
 
 	schedule, err := h.schedulesRepo.GetByProvider(c.Request.Context(), providerId, domain.ScheduleTypeGlobal)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch schedule"})
-		return
-	}
-
-	if schedule == nil || len(schedule.Days) == 0 {
-		c.JSON(http.StatusOK, []string{})
-		return
-	}
-
-	daysOfWeek := []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
-	dayOfWeekStr := daysOfWeek[date.Weekday()]
-
-	daySchedule, ok := schedule.Days[dayOfWeekStr]
-
-	if !ok || !daySchedule.Enabled {
-		c.JSON(http.StatusOK, []string{})
+		apperrors.Abort(c, err)
 		return
 	}
 
-	appointments, err := h.repo.ListByDate(c.Request.Context(), date, providerId)
+	slots, err := h.booker.AvailableSlots(c.Request.Context(), service, schedule, date)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
-
-	type timeRange struct {
-		Start time.Time
-		End   time.Time
-	}
-	busySlots := []timeRange{}
-
-	var serviceDestinations map[string]int
-
-	for _, appt := range appointments {
-		dur := appt.DurationMinutes
-		if dur == 0 {
-			if serviceDestinations == nil {
-				allServices, _ := h.servicesRepo.List(c.Request.Context(), 100, 0, providerId)
-				serviceDestinations = make(map[string]int)
-				for _, s := range allServices {
-					serviceDestinations[s.ID] = s.DurationMinutes
-				}
-			}
-			dur = serviceDestinations[appt.ServiceId]
-			if dur == 0 {
-				dur = 60
-			}
-		}
-		end := appt.ScheduledAt.Add(time.Duration(dur) * time.Minute)
-		busySlots = append(busySlots, timeRange{Start: appt.ScheduledAt, End: end})
-	}
-
-	var availableSlots []string
-
-	for _, workRange := range daySchedule.Ranges {
-		var startH, startM, endH, endM int
-		fmt.Sscanf(workRange.Start, "%d:%d", &startH, &startM)
-		fmt.Sscanf(workRange.End, "%d:%d", &endH, &endM)
-
-		currentSlot := time.Date(date.Year(), date.Month(), date.Day(), startH, startM, 0, 0, date.Location())
-		rangeEnd := time.Date(date.Year(), date.Month(), date.Day(), endH, endM, 0, 0, date.Location())
-
-		for currentSlot.Before(rangeEnd) {
-			dur := service.DurationMinutes
-			if dur <= 0 {
-				dur = 30
-			}
-			slotEnd := currentSlot.Add(time.Duration(dur) * time.Minute)
-
-			if slotEnd.After(rangeEnd) {
-				break
-			}
-
-			isBusy := false
-			for _, busy := range busySlots {
-				if currentSlot.Before(busy.End) && slotEnd.After(busy.Start) {
-					isBusy = true
-					break
-				}
-			}
-
-			if !isBusy {
-				availableSlots = append(availableSlots, currentSlot.Format("15:04"))
-			}
-
-			currentSlot = currentSlot.Add(30 * time.Minute)
-		}
-	}
-
-	if availableSlots == nil {
-		availableSlots = []string{}
-	}
-
-	c.JSON(http.StatusOK, availableSlots)
+	c.JSON(http.StatusOK, slots)
 }
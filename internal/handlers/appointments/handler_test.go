@@ -9,84 +9,108 @@ import (
 	"testing"
 	"time"
 
+	"ServiceBookingApp/internal/apperrors"
 	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/memory"
+
+	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-type MockAppointmentsRepository struct {
-	Data map[string]*domain.Appointments
-}
-
-func (m *MockAppointmentsRepository) List(ctx context.Context, limit, offset int) ([]*domain.Appointments, error) {
-	var results []*domain.Appointments
-	for _, v := range m.Data {
-		results = append(results, v)
-	}
-
-	if offset >= len(results) {
-		return []*domain.Appointments{}, nil
-	}
-	end := offset + limit
-	if end > len(results) {
-		end = len(results)
-	}
-	return results[offset:end], nil
+// creatingRepo counts the appointments that reach the repository, to tell
+// conflicts the handler catches from those only the adapter would.
+type creatingRepo struct {
+	domain.AppointmentsRepository
+	creates int
 }
 
-func (m *MockAppointmentsRepository) Get(ctx context.Context, id string) (*domain.Appointments, error) {
-	if val, ok := m.Data[id]; ok {
-		return val, nil
-	}
-	return nil, nil
+func (r *creatingRepo) Create(ctx context.Context, m *domain.Appointments) (string, error) {
+	r.creates++
+	return r.AppointmentsRepository.Create(ctx, m)
 }
 
-func (m *MockAppointmentsRepository) Create(ctx context.Context, model *domain.Appointments) (string, error) {
-	id := "test-id"
-	model.ID = id
-	if m.Data == nil {
-		m.Data = make(map[string]*domain.Appointments)
-	}
-	m.Data[id] = model
-	return id, nil
-}
-
-func (m *MockAppointmentsRepository) Update(ctx context.Context, id string, model *domain.Appointments) error {
-	m.Data[id] = model
-	return nil
-}
+func TestAppointmentsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
 
-func (m *MockAppointmentsRepository) Delete(ctx context.Context, id string) error {
-	delete(m.Data, id)
-	return nil
-}
+	store := memory.NewStore()
+	repo := &creatingRepo{AppointmentsRepository: memory.NewAppointmentsRepository(store)}
+	holdsRepo := memory.NewHoldsRepository(store)
+	servicesRepo := memory.NewServicesRepository(store)
+	providersRepo := memory.NewProvidersRepository(store)
+	schedulesRepo := memory.NewSchedulesRepository(store)
 
-func (m *MockAppointmentsRepository) ListByDate(ctx context.Context, date time.Time) ([]*domain.Appointments, error) {
-	return nil, nil
-}
+	providerId, err := providersRepo.Create(ctx, &domain.Providers{UserId: "user-1"})
+	require.NoError(t, err)
+	serviceId, err := servicesRepo.Create(ctx, &domain.Services{ProviderId: providerId, Title: "Corte", DurationMinutes: 30})
+	require.NoError(t, err)
 
-func TestAppointmentsHandler(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-	repo := &MockAppointmentsRepository{Data: make(map[string]*domain.Appointments)}
-	handler := NewAppointmentsHandler(repo, nil, nil)
+	handler := NewAppointmentsHandler(repo, servicesRepo, providersRepo, schedulesRepo, holdsRepo)
 	r := gin.Default()
+	r.Use(apperrors.Middleware())
+	r.Use(func(c *gin.Context) {
+		c.Set("user", &auth.Token{UID: "user-1"})
+	})
 
 	r.GET("/appointments", handler.List)
 	r.POST("/appointments", handler.Create)
 
+	scheduledAt := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+
 	t.Run("Create", func(t *testing.T) {
 		w := httptest.NewRecorder()
-		body := domain.Appointments{}
+		body := domain.Appointments{ServiceId: serviceId, ScheduledAt: scheduledAt}
 		jsonBody, _ := json.Marshal(body)
 		req, _ := http.NewRequest("POST", "/appointments", bytes.NewBuffer(jsonBody))
 		r.ServeHTTP(w, req)
 		assert.Equal(t, http.StatusCreated, w.Code)
 	})
 
+	t.Run("Create overlapping", func(t *testing.T) {
+		// The Booker rejects overlaps with appointments and holds before
+		// the repository's own check, so a conflict costs no write attempt.
+		creates := repo.creates
+		_, err := holdsRepo.Create(ctx, &domain.Hold{ProviderId: providerId, ServiceId: serviceId, ScheduledAt: scheduledAt.Add(2 * time.Hour), DurationMinutes: 30, ExpiresAt: time.Now().Add(time.Minute)})
+		require.NoError(t, err)
+
+		for _, at := range []time.Time{scheduledAt.Add(15 * time.Minute), scheduledAt.Add(2 * time.Hour)} {
+			w := httptest.NewRecorder()
+			body := domain.Appointments{ServiceId: serviceId, ScheduledAt: at}
+			jsonBody, _ := json.Marshal(body)
+			req, _ := http.NewRequest("POST", "/appointments", bytes.NewBuffer(jsonBody))
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusConflict, w.Code)
+			assert.Contains(t, w.Body.String(), `"code":"SLOT_TAKEN"`)
+		}
+		assert.Equal(t, creates, repo.creates, "conflicts are caught before the repository")
+	})
+
+	t.Run("Create without service", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/appointments", bytes.NewBufferString(`{}`))
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	t.Run("List", func(t *testing.T) {
 		w := httptest.NewRecorder()
-		req, _ := http.NewRequest("GET", "/appointments?page=1&limit=10", nil)
+		req, _ := http.NewRequest("GET", "/appointments?provider_id="+providerId+"&limit=10", nil)
 		r.ServeHTTP(w, req)
 		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Data []domain.Appointments `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Data, 1)
+	})
+
+	t.Run("List another provider", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/appointments?provider_id=someone-else", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
 	})
 }
@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+
+	"ServiceBookingApp/internal/apperrors"
+	authService "ServiceBookingApp/internal/auth"
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler lets a provider see who changed their data.
+type AuditHandler struct {
+	repo   domain.AuditRepository
+	policy *authService.Policy
+}
+
+func NewAuditHandler(repo domain.AuditRepository, providersRepo domain.ProvidersRepository) *AuditHandler {
+	return &AuditHandler{
+		repo:   repo,
+		policy: authService.NewPolicy(providersRepo),
+	}
+}
+
+// List returns the audit entries of the caller's provider, newest first,
+// optionally filtered by entity_type, entity_id, actor and a from/to range.
+func (h *AuditHandler) List(c *gin.Context) {
+	provider, ok := h.policy.RequireProvider(c)
+	if !ok {
+		return
+	}
+	providerId := provider.ID
+
+	filter := domain.AuditFilter{
+		ListOptions: domain.ListOptions{Cursor: c.Query("cursor")},
+		ProviderId:  providerId,
+		EntityType:  c.Query("entity_type"),
+		EntityId:    c.Query("entity_id"),
+		ActorUID:    c.Query("actor"),
+	}
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil && val > 0 {
+			filter.Limit = val
+		}
+	}
+	if from := c.Query("from"); from != "" {
+		t, err := utils.ParseDateOrTime(from)
+		if err != nil {
+			apperrors.Abort(c, apperrors.InvalidQuery("invalid from date"))
+			return
+		}
+		filter.From = t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := utils.ParseDateOrTime(to)
+		if err != nil {
+			apperrors.Abort(c, apperrors.InvalidQuery("invalid to date"))
+			return
+		}
+		filter.To = t
+	}
+
+	results, nextCursor, err := h.repo.List(c.Request.Context(), filter)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": results, "next_cursor": nextCursor})
+}
@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ServiceBookingApp/internal/apperrors"
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/memory"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := memory.NewStore()
+	repo := memory.NewAuditRepository(store)
+	providersRepo := memory.NewProvidersRepository(store)
+	providerId, err := providersRepo.Create(context.Background(), &domain.Providers{UserId: "user-1"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, e := range []*domain.AuditEntry{
+		{ProviderId: providerId, Action: domain.AuditCreate, EntityType: "services", EntityId: "s-1"},
+		{ProviderId: providerId, Action: domain.AuditDelete, EntityType: "appointments", EntityId: "a-1"},
+		{ProviderId: "other-provider", Action: domain.AuditDelete, EntityType: "appointments", EntityId: "a-2"},
+	} {
+		_, err := repo.Create(ctx, e)
+		require.NoError(t, err)
+	}
+
+	handler := NewAuditHandler(repo, providersRepo)
+	r := gin.Default()
+	r.Use(apperrors.Middleware())
+	r.Use(func(c *gin.Context) {
+		c.Set("user", &auth.Token{UID: c.GetHeader("X-Test-UID")})
+	})
+	r.GET("/audit", handler.List)
+
+	get := func(uid, query string) (int, []domain.AuditEntry) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/audit"+query, nil)
+		req.Header.Set("X-Test-UID", uid)
+		r.ServeHTTP(w, req)
+		var resp struct {
+			Data []domain.AuditEntry `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+
+	t.Run("scoped to the caller's provider", func(t *testing.T) {
+		code, entries := get("user-1", "")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Len(t, entries, 2)
+		for _, e := range entries {
+			assert.Equal(t, providerId, e.ProviderId)
+		}
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		code, entries := get("user-1", "?entity_type=appointments")
+		assert.Equal(t, http.StatusOK, code)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "a-1", entries[0].EntityId)
+	})
+
+	t.Run("non providers are rejected", func(t *testing.T) {
+		code, _ := get("someone-else", "")
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		code, _ := get("user-1", "?cursor=bogus")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
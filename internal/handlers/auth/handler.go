@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 
+	"ServiceBookingApp/internal/apperrors"
 	"ServiceBookingApp/internal/auth"
 	"ServiceBookingApp/internal/domain"
 	firebaseAuth "firebase.google.com/go/v4/auth"
@@ -46,7 +47,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 	userTokenInterface, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		apperrors.Abort(c, apperrors.ErrUnauthorized)
 		return
 	}
 	userToken := userTokenInterface.(*firebaseAuth.Token)
@@ -101,7 +102,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 	err = h.Repository.Update(context.Background(), uid, data)
 	if err != nil {
 		log.Printf("Failed to update user: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save user data"})
+		apperrors.Abort(c, err)
 		return
 	}
 
@@ -124,7 +125,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 func (h *UserHandler) GetMe(c *gin.Context) {
 	userTokenInterface, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		apperrors.Abort(c, apperrors.ErrUnauthorized)
 		return
 	}
 	userToken := userTokenInterface.(*firebaseAuth.Token)
@@ -132,7 +133,7 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 
 	userData, err := h.Repository.Get(context.Background(), uid)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeUserNotFound, "user not found"))
 		return
 	}
 
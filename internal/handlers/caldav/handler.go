@@ -0,0 +1,301 @@
+package caldav
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"ServiceBookingApp/internal/apperrors"
+	authService "ServiceBookingApp/internal/auth"
+	"ServiceBookingApp/internal/booking"
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalDAVHandler exposes the appointments of a provider as a single calendar
+// collection. Appointments can be read and moved or cancelled from a
+// calendar client; new bookings still have to go through the widget or the
+// REST API because a plain VEVENT carries no service.
+type CalDAVHandler struct {
+	repo          domain.AppointmentsRepository
+	servicesRepo  domain.ServicesRepository
+	providersRepo domain.ProvidersRepository
+	authSvc       authService.AuthService
+	booker        *booking.Booker
+}
+
+// NewCalDAVHandler returns the CalDAV handlers. Moves are checked by the
+// same Booker rules as the dashboard, so the holds of holdsRepo count as
+// taken.
+func NewCalDAVHandler(repo domain.AppointmentsRepository, servicesRepo domain.ServicesRepository, providersRepo domain.ProvidersRepository, holdsRepo domain.HoldsRepository, authSvc authService.AuthService) *CalDAVHandler {
+	return &CalDAVHandler{
+		repo:          repo,
+		servicesRepo:  servicesRepo,
+		providersRepo: providersRepo,
+		authSvc:       authSvc,
+		booker:        booking.NewBooker(repo, servicesRepo, holdsRepo, nil),
+	}
+}
+
+// Authenticate accepts the Firebase ID token either as a Bearer token or as
+// the password of HTTP Basic auth, which is what most calendar apps support,
+// and only lets the owner of the provider in.
+func (h *CalDAVHandler) Authenticate(c *gin.Context) {
+	tokenString := ""
+	authHeader := c.GetHeader("Authorization")
+	if strings.HasPrefix(authHeader, "Bearer ") {
+		tokenString = strings.TrimPrefix(authHeader, "Bearer ")
+	} else if _, password, ok := c.Request.BasicAuth(); ok {
+		tokenString = password
+	}
+
+	if tokenString == "" {
+		c.Header("WWW-Authenticate", `Basic realm="ServiceBookingApp CalDAV"`)
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	token, err := h.authSvc.VerifyIDToken(c.Request.Context(), tokenString)
+	if err != nil {
+		c.Header("WWW-Authenticate", `Basic realm="ServiceBookingApp CalDAV"`)
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	provider, err := h.providersRepo.Get(c.Request.Context(), c.Param("provider_id"))
+	if err != nil || provider == nil {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	c.Set("user", token)
+	if !authService.AuthorizeUser(c, provider.UserId) {
+		return
+	}
+	c.Set("provider", provider)
+	c.Next()
+}
+
+func (h *CalDAVHandler) Options(c *gin.Context) {
+	c.Header("DAV", "1, calendar-access")
+	c.Header("Allow", "OPTIONS, PROPFIND, REPORT, GET, PUT")
+	c.Status(http.StatusOK)
+}
+
+func (h *CalDAVHandler) Propfind(c *gin.Context) {
+	provider := c.MustGet("provider").(*domain.Providers)
+
+	ms := newMultistatus()
+	ms.Responses = append(ms.Responses, response{
+		Href: collectionHref(provider.ID),
+		Propstat: okPropstat(prop{
+			ResourceType:        &resourceType{Collection: &struct{}{}, Calendar: &struct{}{}},
+			DisplayName:         provider.EstablishmentName,
+			SupportedComponents: &supportedComponents{Comp: []comp{{Name: "VEVENT"}}},
+		}),
+	})
+
+	if c.GetHeader("Depth") != "0" {
+		appointments, err := h.listAppointments(c.Request.Context(), provider.ID, time.Time{}, time.Time{})
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		for _, appt := range appointments {
+			ms.Responses = append(ms.Responses, response{
+				Href: objectHref(provider.ID, appt.ID),
+				Propstat: okPropstat(prop{
+					GetETag:        etag(appt),
+					GetContentType: "text/calendar; charset=utf-8; component=VEVENT",
+				}),
+			})
+		}
+	}
+
+	writeMultistatus(c, ms)
+}
+
+func (h *CalDAVHandler) Report(c *gin.Context) {
+	provider := c.MustGet("provider").(*domain.Providers)
+
+	req, err := parseReport(c.Request.Body)
+	if err != nil {
+		c.String(http.StatusBadRequest, "invalid REPORT body")
+		return
+	}
+
+	ms := newMultistatus()
+
+	if req.Multiget {
+		for _, href := range req.Hrefs {
+			id := objectID(href)
+			appt, err := h.repo.Get(c.Request.Context(), id)
+			if err != nil || appt == nil || appt.ProviderId != provider.ID || appt.DeletedAt != nil {
+				ms.Responses = append(ms.Responses, response{Href: href, Status: "HTTP/1.1 404 Not Found"})
+				continue
+			}
+			ms.Responses = append(ms.Responses, eventResponse(provider.ID, appt))
+		}
+		writeMultistatus(c, ms)
+		return
+	}
+
+	appointments, err := h.listAppointments(c.Request.Context(), provider.ID, req.Start, req.End)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	for _, appt := range appointments {
+		ms.Responses = append(ms.Responses, eventResponse(provider.ID, appt))
+	}
+	writeMultistatus(c, ms)
+}
+
+func (h *CalDAVHandler) Get(c *gin.Context) {
+	provider := c.MustGet("provider").(*domain.Providers)
+
+	appt, err := h.repo.Get(c.Request.Context(), objectID(c.Param("object")))
+	if err != nil || appt == nil || appt.ProviderId != provider.ID || appt.DeletedAt != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	c.Header("ETag", etag(appt))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(encodeAppointment(appt)))
+}
+
+// Put applies a client edit to an existing appointment. Only the start time,
+// description and cancellation are taken from the event; duration and
+// summary keep following the booked service.
+func (h *CalDAVHandler) Put(c *gin.Context) {
+	provider := c.MustGet("provider").(*domain.Providers)
+
+	existing, err := h.repo.Get(c.Request.Context(), objectID(c.Param("object")))
+	if err != nil || existing == nil || existing.ProviderId != provider.ID || existing.DeletedAt != nil {
+		c.String(http.StatusForbidden, "new appointments must be booked through the booking widget")
+		return
+	}
+
+	if match := c.GetHeader("If-Match"); match != "" && match != "*" && match != etag(existing) {
+		c.Status(http.StatusPreconditionFailed)
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	ev, err := parseEvent(string(body))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if ev.Status == "CANCELLED" {
+		now := utils.Now()
+		existing.Status = "cancelled"
+		existing.DeletedAt = &now
+	} else {
+		// The calendar only carries the text; intake answers are kept.
+		existing.Notes.Text = ev.Description
+		if !ev.Start.Equal(existing.ScheduledAt) {
+			existing.ScheduledAt = ev.Start
+			if err := h.booker.Prepare(c.Request.Context(), provider.ID, existing); err != nil {
+				e := apperrors.From(err)
+				c.String(e.Status, e.Message)
+				return
+			}
+		}
+	}
+
+	if err := h.repo.Update(c.Request.Context(), existing.ID, existing); err != nil {
+		c.Status(apperrors.From(err).Status)
+		return
+	}
+
+	c.Header("ETag", etag(existing))
+	c.Status(http.StatusNoContent)
+}
+
+// listAppointments returns the live appointments of a provider, optionally
+// restricted to those overlapping [start, end).
+func (h *CalDAVHandler) listAppointments(ctx context.Context, providerId string, start, end time.Time) ([]*domain.Appointments, error) {
+	filter := domain.AppointmentsFilter{
+		ListOptions: domain.ListOptions{Limit: domain.MaxPageSize},
+		ProviderId:  providerId,
+		To:          end,
+	}
+	// Appointments never span more than a day, so this lower bound still
+	// catches the ones that started before the range and overlap it.
+	if !start.IsZero() {
+		filter.From = start.Add(-24 * time.Hour)
+	}
+
+	var results []*domain.Appointments
+	for {
+		page, nextCursor, err := h.repo.List(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, appt := range page {
+			if appt.DeletedAt != nil {
+				continue
+			}
+			apptEnd := appt.ScheduledAt.Add(time.Duration(appt.DurationMinutes) * time.Minute)
+			if !start.IsZero() && !apptEnd.After(start) {
+				continue
+			}
+			results = append(results, appt)
+		}
+		if nextCursor == "" {
+			return results, nil
+		}
+		filter.Cursor = nextCursor
+	}
+}
+
+func eventResponse(providerId string, appt *domain.Appointments) response {
+	return response{
+		Href: objectHref(providerId, appt.ID),
+		Propstat: okPropstat(prop{
+			GetETag:      etag(appt),
+			CalendarData: encodeAppointment(appt),
+		}),
+	}
+}
+
+func writeMultistatus(c *gin.Context, ms *multistatus) {
+	out, err := xml.Marshal(ms)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", append([]byte(xml.Header), out...))
+}
+
+func collectionHref(providerId string) string {
+	return "/caldav/providers/" + providerId + "/"
+}
+
+func objectHref(providerId, appointmentId string) string {
+	return collectionHref(providerId) + appointmentId + ".ics"
+}
+
+// objectID extracts the appointment ID from an object name or full href.
+func objectID(href string) string {
+	name := href[strings.LastIndex(href, "/")+1:]
+	return strings.TrimSuffix(name, ".ics")
+}
+
+func etag(appt *domain.Appointments) string {
+	raw := fmt.Sprintf("%s-%d", appt.ID, appt.UpdatedAt.UnixNano())
+	return `"` + base64.RawURLEncoding.EncodeToString([]byte(raw)) + `"`
+}
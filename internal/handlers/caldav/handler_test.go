@@ -0,0 +1,68 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/apperrors"
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/memory"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uidAuthService accepts any token and treats it as the caller's UID.
+type uidAuthService struct{}
+
+func (uidAuthService) VerifyIDToken(ctx context.Context, idToken string) (*auth.Token, error) {
+	return &auth.Token{UID: idToken}, nil
+}
+
+func TestPutRespectsHolds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	repo := memory.NewAppointmentsRepository(store)
+	servicesRepo := memory.NewServicesRepository(store)
+	providersRepo := memory.NewProvidersRepository(store)
+	holdsRepo := memory.NewHoldsRepository(store)
+
+	providerId, err := providersRepo.Create(ctx, &domain.Providers{UserId: "user-1"})
+	require.NoError(t, err)
+	serviceId, err := servicesRepo.Create(ctx, &domain.Services{ProviderId: providerId, Title: "Corte", DurationMinutes: 30})
+	require.NoError(t, err)
+	at := time.Now().Add(48 * time.Hour).Truncate(time.Hour).UTC()
+	appt := &domain.Appointments{ProviderId: providerId, ServiceId: serviceId, ScheduledAt: at, DurationMinutes: 30, Status: domain.StatusConfirmed}
+	id, err := repo.Create(ctx, appt)
+	require.NoError(t, err)
+	appt.ID = id
+	held := at.Add(2 * time.Hour)
+	_, err = holdsRepo.Create(ctx, &domain.Hold{ProviderId: providerId, ServiceId: serviceId, ScheduledAt: held, DurationMinutes: 30, ExpiresAt: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+
+	handler := NewCalDAVHandler(repo, servicesRepo, providersRepo, holdsRepo, uidAuthService{})
+	r := gin.New()
+	r.Use(apperrors.Middleware())
+	r.PUT("/caldav/providers/:provider_id/:object", handler.Authenticate, handler.Put)
+
+	put := func(start time.Time) int {
+		moved := *appt
+		moved.ScheduledAt = start
+		req := httptest.NewRequest("PUT", objectHref(providerId, id), strings.NewReader(encodeAppointment(&moved)))
+		req.Header.Set("Authorization", "Bearer user-1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusConflict, put(held.Add(15*time.Minute)), "a live hold takes the slot")
+	assert.Equal(t, http.StatusNoContent, put(held.Add(time.Hour)))
+}
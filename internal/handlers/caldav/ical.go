@@ -0,0 +1,167 @@
+package caldav
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+)
+
+const icalTimeFormat = "20060102T150405Z"
+
+// event is the subset of a VEVENT that maps onto an appointment.
+type event struct {
+	UID         string
+	Start       time.Time
+	Summary     string
+	Description string
+	Status      string
+}
+
+func encodeAppointment(m *domain.Appointments) string {
+	dur := m.DurationMinutes
+	if dur <= 0 {
+		dur = 30
+	}
+
+	status := "CONFIRMED"
+	if m.Status == "cancelled" || m.DeletedAt != nil {
+		status = "CANCELLED"
+	}
+
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//ServiceBookingApp//CalDAV//ES")
+	writeLine(&b, "BEGIN:VEVENT")
+	writeLine(&b, "UID:"+m.ID)
+	writeLine(&b, "DTSTAMP:"+m.UpdatedAt.UTC().Format(icalTimeFormat))
+	writeLine(&b, "DTSTART:"+m.ScheduledAt.UTC().Format(icalTimeFormat))
+	writeLine(&b, "DTEND:"+m.ScheduledAt.Add(time.Duration(dur)*time.Minute).UTC().Format(icalTimeFormat))
+	writeLine(&b, "SUMMARY:"+escapeText(m.ServiceName))
+	if m.Notes.Text != "" {
+		writeLine(&b, "DESCRIPTION:"+escapeText(m.Notes.Text))
+	}
+	writeLine(&b, "STATUS:"+status)
+	writeLine(&b, "END:VEVENT")
+	writeLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// writeLine folds content lines longer than 75 octets as required by RFC 5545.
+func writeLine(b *strings.Builder, line string) {
+	for len(line) > 75 {
+		cut := 75
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
+
+func escapeText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+func unescapeText(s string) string {
+	r := strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+	return r.Replace(s)
+}
+
+// parseEvent reads the first VEVENT of an iCalendar object.
+func parseEvent(data string) (*event, error) {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var ev *event
+	for _, line := range lines {
+		name, params, value, ok := splitProperty(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			if ev == nil {
+				ev = &event{}
+			}
+		case ev == nil:
+			continue
+		case name == "END" && value == "VEVENT":
+			if ev.Start.IsZero() {
+				return nil, fmt.Errorf("VEVENT is missing DTSTART")
+			}
+			return ev, nil
+		case name == "UID":
+			ev.UID = value
+		case name == "SUMMARY":
+			ev.Summary = unescapeText(value)
+		case name == "DESCRIPTION":
+			ev.Description = unescapeText(value)
+		case name == "STATUS":
+			ev.Status = strings.ToUpper(value)
+		case name == "DTSTART":
+			start, err := parseDateTime(value, params)
+			if err != nil {
+				return nil, err
+			}
+			ev.Start = start
+		}
+	}
+	return nil, fmt.Errorf("no VEVENT found")
+}
+
+func splitProperty(line string) (name string, params map[string]string, value string, ok bool) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return "", nil, "", false
+	}
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+	params = make(map[string]string)
+	for _, p := range parts[1:] {
+		if k, v, found := strings.Cut(p, "="); found {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value, true
+}
+
+func parseDateTime(value string, params map[string]string) (time.Time, error) {
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(icalTimeFormat, value)
+	}
+
+	loc := utils.ArgentinaLocation
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		return time.ParseInLocation("20060102", value, loc)
+	}
+	return time.ParseInLocation("20060102T150405", value, loc)
+}
@@ -0,0 +1,66 @@
+package caldav
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeParseRoundTrip(t *testing.T) {
+	start := time.Date(2024, 5, 10, 13, 30, 0, 0, time.UTC)
+	appt := &domain.Appointments{
+		ID:              "abc",
+		ServiceName:     "Corte, lavado; peinado",
+		Notes:           domain.Notes{Text: "traer foto\nde referencia"},
+		ScheduledAt:     start,
+		DurationMinutes: 45,
+		Status:          "confirmed",
+	}
+
+	ev, err := parseEvent(encodeAppointment(appt))
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", ev.UID)
+	assert.True(t, ev.Start.Equal(start))
+	assert.Equal(t, "Corte, lavado; peinado", ev.Summary)
+	assert.Equal(t, "traer foto\nde referencia", ev.Description)
+	assert.Equal(t, "CONFIRMED", ev.Status)
+}
+
+func TestParseEventWithTZIDAndFolding(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:xyz",
+		"DTSTART;TZID=America/Argentina/Buenos_Aires:20240510T100000",
+		"DESCRIPTION:una nota muy",
+		"  larga",
+		"STATUS:cancelled",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	ev, err := parseEvent(data)
+	assert.NoError(t, err)
+	assert.Equal(t, 13, ev.Start.UTC().Hour())
+	assert.Equal(t, "una nota muy larga", ev.Description)
+	assert.Equal(t, "CANCELLED", ev.Status)
+}
+
+func TestParseReport(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VEVENT">
+    <C:time-range start="20240501T000000Z" end="20240601T000000Z"/>
+  </C:comp-filter></C:comp-filter></C:filter>
+</C:calendar-query>`
+
+	req, err := parseReport(strings.NewReader(body))
+	assert.NoError(t, err)
+	assert.False(t, req.Multiget)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), req.Start)
+	assert.Equal(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), req.End)
+}
@@ -0,0 +1,115 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+)
+
+type multistatus struct {
+	XMLName   xml.Name   `xml:"D:multistatus"`
+	XmlnsD    string     `xml:"xmlns:D,attr"`
+	XmlnsC    string     `xml:"xmlns:C,attr"`
+	Responses []response `xml:"D:response"`
+}
+
+type response struct {
+	Href     string    `xml:"D:href"`
+	Propstat *propstat `xml:"D:propstat,omitempty"`
+	Status   string    `xml:"D:status,omitempty"`
+}
+
+type propstat struct {
+	Prop   prop   `xml:"D:prop"`
+	Status string `xml:"D:status"`
+}
+
+type prop struct {
+	ResourceType        *resourceType        `xml:"D:resourcetype,omitempty"`
+	DisplayName         string               `xml:"D:displayname,omitempty"`
+	GetETag             string               `xml:"D:getetag,omitempty"`
+	GetContentType      string               `xml:"D:getcontenttype,omitempty"`
+	SupportedComponents *supportedComponents `xml:"C:supported-calendar-component-set,omitempty"`
+	CalendarData        string               `xml:"C:calendar-data,omitempty"`
+}
+
+type resourceType struct {
+	Collection *struct{} `xml:"D:collection,omitempty"`
+	Calendar   *struct{} `xml:"C:calendar,omitempty"`
+}
+
+type supportedComponents struct {
+	Comp []comp `xml:"C:comp"`
+}
+
+type comp struct {
+	Name string `xml:"name,attr"`
+}
+
+func newMultistatus() *multistatus {
+	return &multistatus{XmlnsD: nsDAV, XmlnsC: nsCalDAV}
+}
+
+func okPropstat(p prop) *propstat {
+	return &propstat{Prop: p, Status: "HTTP/1.1 200 OK"}
+}
+
+// reportRequest is what we understand from a REPORT body: either a
+// calendar-query with an optional time-range, or a calendar-multiget.
+type reportRequest struct {
+	Multiget bool
+	Hrefs    []string
+	Start    time.Time
+	End      time.Time
+}
+
+// parseReport walks the XML tokens instead of unmarshalling into structs, so
+// filters nested at any depth and unknown elements are tolerated.
+func parseReport(r io.Reader) (*reportRequest, error) {
+	req := &reportRequest{}
+	dec := xml.NewDecoder(r)
+	var inHref bool
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return req, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == nsCalDAV && t.Name.Local == "calendar-multiget":
+				req.Multiget = true
+			case t.Name.Space == nsDAV && t.Name.Local == "href":
+				inHref = true
+			case t.Name.Space == nsCalDAV && t.Name.Local == "time-range":
+				for _, attr := range t.Attr {
+					v, err := time.Parse(icalTimeFormat, attr.Value)
+					if err != nil {
+						continue
+					}
+					switch attr.Name.Local {
+					case "start":
+						req.Start = v
+					case "end":
+						req.End = v
+					}
+				}
+			}
+		case xml.CharData:
+			if inHref {
+				req.Hrefs = append(req.Hrefs, string(t))
+			}
+		case xml.EndElement:
+			inHref = false
+		}
+	}
+}
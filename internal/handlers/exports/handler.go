@@ -0,0 +1,253 @@
+package exports
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"ServiceBookingApp/internal/apperrors"
+	authService "ServiceBookingApp/internal/auth"
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/export"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var appointmentColumns = []string{
+	"scheduled_at", "service", "duration_minutes", "price", "status",
+	"customer_name", "customer_email", "customer_phone",
+}
+
+var customerColumns = []string{
+	"customer_name", "customer_email", "customer_phone",
+	"appointments", "last_appointment", "total_spent",
+}
+
+type ExportsHandler struct {
+	repo   domain.AppointmentsRepository
+	policy *authService.Policy
+}
+
+func NewExportsHandler(repo domain.AppointmentsRepository, providersRepo domain.ProvidersRepository) *ExportsHandler {
+	return &ExportsHandler{
+		repo:   repo,
+		policy: authService.NewPolicy(providersRepo),
+	}
+}
+
+// exportRequest holds the query parameters shared by every export.
+type exportRequest struct {
+	providerId string
+	from       time.Time
+	to         time.Time
+	format     string
+	locale     export.Locale
+}
+
+func (h *ExportsHandler) parseRequest(c *gin.Context) (*exportRequest, bool) {
+	provider, ok := h.policy.RequireProvider(c)
+	if !ok {
+		return nil, false
+	}
+	req := &exportRequest{
+		providerId: provider.ID,
+		format:     strings.ToLower(c.DefaultQuery("format", export.FormatCSV)),
+		locale:     export.LookupLocale(c.Query("locale")),
+	}
+	if req.format != export.FormatCSV && req.format != export.FormatXLSX {
+		apperrors.Abort(c, apperrors.InvalidQuery("format must be csv or xlsx"))
+		return nil, false
+	}
+
+	var err error
+	if req.from, err = utils.ParseDateOrTime(c.Query("from")); err != nil {
+		apperrors.Abort(c, apperrors.InvalidQuery("from is required and must be a date"))
+		return nil, false
+	}
+	if req.to, err = utils.ParseDateOrTime(c.Query("to")); err != nil {
+		apperrors.Abort(c, apperrors.InvalidQuery("to is required and must be a date"))
+		return nil, false
+	}
+	if !req.from.Before(req.to) {
+		apperrors.Abort(c, apperrors.InvalidQuery("from must be before to"))
+		return nil, false
+	}
+	return req, true
+}
+
+func (h *ExportsHandler) startDownload(c *gin.Context, req *exportRequest, name string) (export.Writer, error) {
+	filename := fmt.Sprintf("%s_%s_%s.%s", name, req.from.Format("20060102"), req.to.Format("20060102"), req.format)
+	c.Header("Content-Type", export.ContentType(req.format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+	return export.NewWriter(req.format, c.Writer, req.locale)
+}
+
+// Appointments streams every appointment of the caller's provider scheduled
+// in [from, to), one page of the repository at a time.
+func (h *ExportsHandler) Appointments(c *gin.Context) {
+	req, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	filter := domain.AppointmentsFilter{
+		ListOptions: domain.ListOptions{Limit: domain.MaxPageSize, IncludeDeleted: true},
+		ProviderId:  req.providerId,
+		From:        req.from,
+		To:          req.to,
+	}
+	page, nextCursor, err := h.repo.List(c.Request.Context(), filter)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	w, err := h.startDownload(c, req, "appointments")
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	if err := w.WriteHeader(appointmentColumns); err != nil {
+		log.Printf("export: writing header: %v", err)
+		return
+	}
+
+	// Once the header is out the status is committed, so failures past this
+	// point can only be logged and the download ends truncated.
+	for {
+		for _, appt := range page {
+			row := []interface{}{
+				appt.ScheduledAt,
+				appt.ServiceName,
+				appt.DurationMinutes,
+				appt.Price,
+				appointmentStatus(appt),
+				appt.CustomerName,
+				appt.CustomerEmail,
+				appt.CustomerPhone,
+			}
+			if err := w.WriteRow(row); err != nil {
+				log.Printf("export: writing row: %v", err)
+				return
+			}
+		}
+		if nextCursor == "" {
+			break
+		}
+		filter.Cursor = nextCursor
+		page, nextCursor, err = h.repo.List(c.Request.Context(), filter)
+		if err != nil {
+			log.Printf("export: listing appointments: %v", err)
+			return
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		log.Printf("export: closing writer: %v", err)
+	}
+}
+
+type customerRow struct {
+	name            string
+	email           string
+	phone           string
+	appointments    int
+	lastAppointment time.Time
+	totalSpent      float64
+}
+
+// Customers exports one row per customer seen in [from, to), keyed by email,
+// then phone, then name. Cancelled appointments are not counted.
+func (h *ExportsHandler) Customers(c *gin.Context) {
+	req, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	customers := make(map[string]*customerRow)
+	filter := domain.AppointmentsFilter{
+		ListOptions: domain.ListOptions{Limit: domain.MaxPageSize, IncludeDeleted: true},
+		ProviderId:  req.providerId,
+		From:        req.from,
+		To:          req.to,
+	}
+	for {
+		page, nextCursor, err := h.repo.List(c.Request.Context(), filter)
+		if err != nil {
+			apperrors.Abort(c, err)
+			return
+		}
+		for _, appt := range page {
+			if appointmentStatus(appt) == domain.StatusCancelled {
+				continue
+			}
+			key := customerKey(appt)
+			if key == "" {
+				continue
+			}
+			row, ok := customers[key]
+			if !ok {
+				row = &customerRow{name: appt.CustomerName, email: appt.CustomerEmail, phone: appt.CustomerPhone}
+				customers[key] = row
+			}
+			row.appointments++
+			row.totalSpent += appt.Price
+			if appt.ScheduledAt.After(row.lastAppointment) {
+				row.lastAppointment = appt.ScheduledAt
+			}
+		}
+		if nextCursor == "" {
+			break
+		}
+		filter.Cursor = nextCursor
+	}
+
+	rows := make([]*customerRow, 0, len(customers))
+	for _, row := range customers {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return strings.ToLower(rows[i].name) < strings.ToLower(rows[j].name) })
+
+	w, err := h.startDownload(c, req, "customers")
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	if err := w.WriteHeader(customerColumns); err != nil {
+		log.Printf("export: writing header: %v", err)
+		return
+	}
+	for _, row := range rows {
+		if err := w.WriteRow([]interface{}{row.name, row.email, row.phone, row.appointments, row.lastAppointment, row.totalSpent}); err != nil {
+			log.Printf("export: writing row: %v", err)
+			return
+		}
+	}
+	if err := w.Close(); err != nil {
+		log.Printf("export: closing writer: %v", err)
+	}
+}
+
+func appointmentStatus(appt *domain.Appointments) string {
+	if appt.DeletedAt != nil {
+		return domain.StatusCancelled
+	}
+	return appt.Status
+}
+
+func customerKey(appt *domain.Appointments) string {
+	switch {
+	case appt.CustomerEmail != "":
+		return "email:" + strings.ToLower(appt.CustomerEmail)
+	case appt.CustomerPhone != "":
+		return "phone:" + appt.CustomerPhone
+	case appt.CustomerName != "":
+		return "name:" + strings.ToLower(appt.CustomerName)
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package imports
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"ServiceBookingApp/internal/apperrors"
+	authService "ServiceBookingApp/internal/auth"
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/importer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBodyBytes bounds the upload size of a single import.
+const maxBodyBytes = 10 << 20
+
+type ImportsHandler struct {
+	importer *importer.Importer
+	policy   *authService.Policy
+}
+
+func NewImportsHandler(importer *importer.Importer, providersRepo domain.ProvidersRepository) *ImportsHandler {
+	return &ImportsHandler{
+		importer: importer,
+		policy:   authService.NewPolicy(providersRepo),
+	}
+}
+
+func (h *ImportsHandler) Services(c *gin.Context) {
+	h.run(c, h.importer.Services)
+}
+
+func (h *ImportsHandler) Appointments(c *gin.Context) {
+	h.run(c, h.importer.Appointments)
+}
+
+type importFunc func(ctx context.Context, providerId string, rows []importer.Row, dryRun bool) (*importer.Report, error)
+
+// run reads the CSV or JSON body and applies fn. With ?dry_run=true only the
+// validation report is returned; otherwise rows are written only if every
+// one of them is valid.
+func (h *ImportsHandler) run(c *gin.Context, fn importFunc) {
+	provider, ok := h.policy.RequireProvider(c)
+	if !ok {
+		return
+	}
+	providerId := provider.ID
+
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes)
+	rows, err := importer.ReadRows(c.ContentType(), body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apperrors.Abort(c, apperrors.Wrap(err, http.StatusRequestEntityTooLarge, apperrors.CodeTooLarge, "import body is too large"))
+			return
+		}
+		apperrors.Abort(c, apperrors.InvalidBody(err))
+		return
+	}
+	if len(rows) == 0 {
+		apperrors.Abort(c, apperrors.New(http.StatusBadRequest, apperrors.CodeInvalidBody, "no rows to import"))
+		return
+	}
+
+	report, err := fn(c.Request.Context(), providerId, rows, dryRun)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	switch {
+	case dryRun:
+		c.JSON(http.StatusOK, report)
+	case len(report.Errors) > 0:
+		c.JSON(http.StatusUnprocessableEntity, report)
+	default:
+		c.JSON(http.StatusCreated, report)
+	}
+}
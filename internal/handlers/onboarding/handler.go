@@ -0,0 +1,84 @@
+package onboarding
+
+import (
+	"errors"
+	"net/http"
+
+	"ServiceBookingApp/internal/apperrors"
+	authService "ServiceBookingApp/internal/auth"
+	"ServiceBookingApp/internal/onboarding"
+	"ServiceBookingApp/internal/requests"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OnboardingHandler struct {
+	service *onboarding.Service
+}
+
+func NewOnboardingHandler(service *onboarding.Service) *OnboardingHandler {
+	return &OnboardingHandler{service: service}
+}
+
+// Status reports which onboarding steps the caller still has to complete.
+func (h *OnboardingHandler) Status(c *gin.Context) {
+	status, err := h.service.Status(c.Request.Context(), authService.CallerUID(c))
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// Templates lists the business types and what each one sets up.
+func (h *OnboardingHandler) Templates(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": onboarding.Templates})
+}
+
+// Establishment creates the caller's provider.
+func (h *OnboardingHandler) Establishment(c *gin.Context) {
+	var req onboarding.Establishment
+	if err := requests.Bind(c, &req); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	provider, err := h.service.CreateEstablishment(c.Request.Context(), authService.CallerUID(c), req)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, provider)
+}
+
+// Schedule sets up the default schedule of the caller's business type.
+func (h *OnboardingHandler) Schedule(c *gin.Context) {
+	schedule, err := h.service.ApplySchedule(c.Request.Context(), authService.CallerUID(c))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// Services creates the starter services of the caller's business type.
+func (h *OnboardingHandler) Services(c *gin.Context) {
+	created, err := h.service.ApplyServices(c.Request.Context(), authService.CallerUID(c))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": created})
+}
+
+func (h *OnboardingHandler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, onboarding.ErrUnknownBusinessType):
+		err = apperrors.Wrap(err, http.StatusBadRequest, apperrors.CodeUnknownBusinessType, err.Error())
+	case errors.Is(err, onboarding.ErrAlreadyOnboarded):
+		err = apperrors.Wrap(err, http.StatusConflict, apperrors.CodeAlreadyProvider, err.Error())
+	case errors.Is(err, onboarding.ErrNoEstablishment):
+		err = apperrors.Wrap(err, http.StatusConflict, apperrors.CodeNoEstablishment, err.Error())
+	}
+	apperrors.Abort(c, err)
+}
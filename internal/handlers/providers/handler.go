@@ -1,9 +1,14 @@
 package providers
 
 import (
+	"errors"
 	"net/http"
 
+	"ServiceBookingApp/internal/apperrors"
+	authService "ServiceBookingApp/internal/auth"
 	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/profile"
+	"ServiceBookingApp/internal/requests"
 	"ServiceBookingApp/internal/utils"
 
 	"firebase.google.com/go/v4/auth"
@@ -11,24 +16,25 @@ import (
 )
 
 type ProvidersHandler struct {
-	repo domain.ProvidersRepository
+	repo   domain.ProvidersRepository
+	policy *authService.Policy
 }
 
 func NewProvidersHandler(repo domain.ProvidersRepository) *ProvidersHandler {
-	return &ProvidersHandler{repo: repo}
+	return &ProvidersHandler{repo: repo, policy: authService.NewPolicy(repo)}
 }
 
 func (h *ProvidersHandler) List(c *gin.Context) {
 	u, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		apperrors.Abort(c, apperrors.ErrUnauthorized)
 		return
 	}
 	token := u.(*auth.Token)
 
 	provider, err := h.repo.GetByUserId(c.Request.Context(), token.UID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 
@@ -36,14 +42,26 @@ func (h *ProvidersHandler) List(c *gin.Context) {
 	if provider != nil {
 		results = append(results, provider)
 	}
-	c.JSON(http.StatusOK, results)
+	c.JSON(http.StatusOK, gin.H{"data": results, "next_cursor": ""})
 }
 
 func (h *ProvidersHandler) Get(c *gin.Context) {
 	id := c.Param("id")
-	result, err := h.repo.Get(c.Request.Context(), id)
+	includeDeleted, ok := authService.IncludeDeleted(c)
+	if !ok {
+		return
+	}
+
+	get := h.repo.Get
+	if includeDeleted {
+		get = h.repo.GetIncludingDeleted
+	}
+	result, err := get(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeProviderNotFound, "provider not found"))
+		return
+	}
+	if !authService.AuthorizeUser(c, result.UserId) {
 		return
 	}
 	c.JSON(http.StatusOK, result)
@@ -52,32 +70,36 @@ func (h *ProvidersHandler) Get(c *gin.Context) {
 func (h *ProvidersHandler) Create(c *gin.Context) {
 	u, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		apperrors.Abort(c, apperrors.ErrUnauthorized)
 		return
 	}
 	token := u.(*auth.Token)
 
 	existing, err := h.repo.GetByUserId(c.Request.Context(), token.UID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 	if existing != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "user already has a provider"})
+		apperrors.Abort(c, apperrors.New(http.StatusConflict, apperrors.CodeAlreadyProvider, "user already has a provider"))
 		return
 	}
 
-	var m domain.Providers
-	if err := c.ShouldBindJSON(&m); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req requests.CreateProvider
+	if err := requests.Bind(c, &req); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
 
-	m.UserId = token.UID
+	m := req.ToDomain(token.UID)
+	if err := profile.AssignSlug(c.Request.Context(), h.repo, m); err != nil {
+		slugError(c, err)
+		return
+	}
 
-	id, err := h.repo.Create(c.Request.Context(), &m)
+	id, err := h.repo.Create(c.Request.Context(), m)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 	m.ID = id
@@ -89,44 +111,32 @@ func (h *ProvidersHandler) Update(c *gin.Context) {
 	
 	existing, err := h.repo.Get(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "provider not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeProviderNotFound, "provider not found"))
 		return
 	}
-	
-	u, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		return
-	}
-	token := u.(*auth.Token)
-	if existing.UserId != token.UID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	if !authService.AuthorizeUser(c, existing.UserId) {
 		return
 	}
 	
-	var updates domain.Providers
-	if err := c.ShouldBindJSON(&updates); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req requests.UpdateProvider
+	if err := requests.Bind(c, &req); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
-	
-	if updates.Phone != "" {
-		existing.Phone = updates.Phone
-	}
-	if updates.Address != "" {
-		existing.Address = updates.Address
-	}
-	if updates.AvatarUrl != "" {
-		existing.AvatarUrl = updates.AvatarUrl
-	}
-	if updates.EstablishmentName != "" {
-		existing.EstablishmentName = updates.EstablishmentName
+
+	req.Apply(existing)
+	if req.Slug != "" && req.Slug != existing.Slug {
+		existing.Slug = req.Slug
+		if err := profile.AssignSlug(c.Request.Context(), h.repo, existing); err != nil {
+			slugError(c, err)
+			return
+		}
 	}
 	
 	existing.UpdatedAt = utils.Now()
 	
 	if err := h.repo.Update(c.Request.Context(), id, existing); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 	
@@ -138,18 +148,10 @@ func (h *ProvidersHandler) Delete(c *gin.Context) {
 	
 	provider, err := h.repo.Get(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "provider not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeProviderNotFound, "provider not found"))
 		return
 	}
-
-	u, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		return
-	}
-	token := u.(*auth.Token)
-	if provider.UserId != token.UID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	if !authService.AuthorizeUser(c, provider.UserId) {
 		return
 	}
 	
@@ -157,9 +159,55 @@ func (h *ProvidersHandler) Delete(c *gin.Context) {
 	provider.DeletedAt = &now
 	
 	if err := h.repo.Update(c.Request.Context(), id, provider); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 	
 	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
 }
+
+// Restore undeletes the provider of the caller, unless they created a new
+// one in the meantime.
+func (h *ProvidersHandler) Restore(c *gin.Context) {
+	id := c.Param("id")
+
+	provider, err := h.repo.GetIncludingDeleted(c.Request.Context(), id)
+	if err != nil {
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeProviderNotFound, "provider not found"))
+		return
+	}
+	if !authService.AuthorizeUser(c, provider.UserId) {
+		return
+	}
+	if provider.DeletedAt == nil {
+		apperrors.Abort(c, apperrors.New(http.StatusConflict, apperrors.CodeNotDeleted, "provider is not deleted"))
+		return
+	}
+
+	current, err := h.repo.GetByUserId(c.Request.Context(), provider.UserId)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	if current != nil {
+		apperrors.Abort(c, apperrors.New(http.StatusConflict, apperrors.CodeAlreadyProvider, "user already has a provider"))
+		return
+	}
+
+	provider.DeletedAt = nil
+	if err := h.repo.Update(c.Request.Context(), id, provider); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, provider)
+}
+
+func slugError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, profile.ErrSlugTaken):
+		err = apperrors.Wrap(err, http.StatusConflict, apperrors.CodeSlugTaken, err.Error())
+	case errors.Is(err, profile.ErrInvalidSlug):
+		err = apperrors.Wrap(err, http.StatusBadRequest, apperrors.CodeInvalidSlug, err.Error())
+	}
+	apperrors.Abort(c, err)
+}
@@ -1,12 +1,20 @@
 package public
 
 import (
-	"fmt"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"ServiceBookingApp/internal/apperrors"
+	"ServiceBookingApp/internal/booking"
+	"ServiceBookingApp/internal/catalog"
 	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/nearby"
+	"ServiceBookingApp/internal/profile"
+	"ServiceBookingApp/internal/requests"
 	"ServiceBookingApp/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -17,55 +25,56 @@ type PublicHandler struct {
 	schedulesRepo    domain.SchedulesRepository
 	appointmentsRepo domain.AppointmentsRepository
 	providersRepo    domain.ProvidersRepository
+	holdsRepo        domain.HoldsRepository
+	booker           *booking.Booker
+	finder           *nearby.Finder
+	// maxUpcoming caps the confirmed upcoming appointments one customer may
+	// book with a provider; 0 means no cap.
+	maxUpcoming int
 }
 
-func NewPublicHandler(servicesRepo domain.ServicesRepository, schedulesRepo domain.SchedulesRepository, appointmentsRepo domain.AppointmentsRepository, providersRepo domain.ProvidersRepository) *PublicHandler {
+func NewPublicHandler(servicesRepo domain.ServicesRepository, schedulesRepo domain.SchedulesRepository, appointmentsRepo domain.AppointmentsRepository, providersRepo domain.ProvidersRepository, holdsRepo domain.HoldsRepository, maxUpcoming int) *PublicHandler {
 	return &PublicHandler{
 		servicesRepo:     servicesRepo,
 		schedulesRepo:    schedulesRepo,
 		appointmentsRepo: appointmentsRepo,
 		providersRepo:    providersRepo,
+		holdsRepo:        holdsRepo,
+		booker:           booking.NewBooker(appointmentsRepo, servicesRepo, holdsRepo, schedulesRepo).PublicOnly(),
+		finder:           nearby.NewFinder(providersRepo, servicesRepo, schedulesRepo, appointmentsRepo, holdsRepo),
+		maxUpcoming:      maxUpcoming,
 	}
 }
 
-func (h *PublicHandler) GetServices(c *gin.Context) {
-	providerId := c.Param("provider_id")
-	if providerId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "provider_id is required"})
-		return
+// ResolveProvider loads the provider named by :provider_id, which may be
+// its ID or its slug, for the handlers that follow.
+func (h *PublicHandler) ResolveProvider(c *gin.Context) {
+	key := c.Param("provider_id")
+	provider, err := h.providersRepo.Get(c.Request.Context(), key)
+	if errors.Is(err, domain.ErrNotFound) {
+		provider, err = h.providersRepo.GetBySlug(c.Request.Context(), key)
 	}
-
-	c.Header("Cache-Control", "public, max-age=300")
-
-	services, err := h.servicesRepo.List(c.Request.Context(), 100, 0, providerId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeProviderNotFound, "provider not found"))
 		return
 	}
-	c.JSON(http.StatusOK, services)
+	c.Set("provider", provider)
+	c.Next()
 }
 
-func (h *PublicHandler) GetAvailableSlots(c *gin.Context) {
-	providerId := c.Param("provider_id")
-	dateStr := c.Query("date")
-	serviceID := c.Query("service")
-	tzOffsetStr := c.Query("timezone_offset")
-
-	if providerId == "" || dateStr == "" || serviceID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "provider_id, date and service are required"})
-		return
-	}
+func providerFrom(c *gin.Context) *domain.Providers {
+	return c.MustGet("provider").(*domain.Providers)
+}
 
-	service, err := h.servicesRepo.Get(c.Request.Context(), serviceID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
-		return
-	}
-	if service.ProviderId != providerId {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "service does not belong to provider"})
-		return
-	}
+// ProviderID returns the ID of the provider ResolveProvider loaded, so
+// rate limits count a provider once whether it is named by ID or slug.
+func ProviderID(c *gin.Context) string {
+	return providerFrom(c).ID
+}
 
+// parseDate reads a "2006-01-02" or RFC 3339 date. timezone_offset, in
+// minutes east of UTC, gives the location of a plain date.
+func parseDate(dateStr, tzOffsetStr string) (time.Time, error) {
 	loc := time.UTC
 	if tzOffsetStr != "" {
 		offset, err := strconv.Atoi(tzOffsetStr)
@@ -76,206 +85,278 @@ func (h *PublicHandler) GetAvailableSlots(c *gin.Context) {
 
 	date, err := time.ParseInLocation("2006-01-02", dateStr, loc)
 	if err != nil {
-		date, err = time.Parse(time.RFC3339, dateStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format"})
-			return
-		}
-		loc = date.Location()
+		return time.Parse(time.RFC3339, dateStr)
 	}
+	return date, nil
+}
 
-	schedule, err := h.schedulesRepo.GetByProvider(c.Request.Context(), providerId, domain.ScheduleTypeGlobal)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch schedule"})
+// Search lists public profiles, optionally filtered by city and by
+// category, the provider's business type. With lat and lng it searches
+// within radius_km instead, nearest first, and can also filter by service
+// title and by having a free slot on date.
+func (h *PublicHandler) Search(c *gin.Context) {
+	if c.Query("lat") != "" || c.Query("lng") != "" {
+		h.searchNearby(c)
 		return
 	}
 
-	if schedule == nil || len(schedule.Days) == 0 {
-		c.JSON(http.StatusOK, []string{})
-		return
+	filter := domain.ProviderFilter{
+		ListOptions:  domain.ListOptions{Cursor: c.Query("cursor")},
+		City:         c.Query("city"),
+		BusinessType: c.Query("category"),
+	}
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil && val > 0 {
+			filter.Limit = val
+		}
 	}
 
-	daysOfWeek := []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
-	dayOfWeekStr := daysOfWeek[date.Weekday()]
+	results, nextCursor, err := h.providersRepo.Search(c.Request.Context(), filter)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
 
-	daySchedule, ok := schedule.Days[dayOfWeekStr]
+	profiles := make([]profile.Profile, 0, len(results))
+	for _, p := range results {
+		profiles = append(profiles, profile.New(p, nil))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": profiles, "next_cursor": nextCursor})
+}
 
-	if !ok || !daySchedule.Enabled {
-		c.JSON(http.StatusOK, []string{})
+func (h *PublicHandler) searchNearby(c *gin.Context) {
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		apperrors.Abort(c, apperrors.InvalidQuery("lat and lng must be valid coordinates"))
 		return
 	}
 
-	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
-	appointments, err := h.appointmentsRepo.ListByDate(c.Request.Context(), startOfDay, providerId)
+	q := nearby.Query{
+		Lat:          lat,
+		Lng:          lng,
+		BusinessType: c.Query("category"),
+		Service:      c.Query("service"),
+	}
+	if r := c.Query("radius_km"); r != "" {
+		radius, err := strconv.ParseFloat(r, 64)
+		if err != nil || radius <= 0 {
+			apperrors.Abort(c, apperrors.InvalidQuery("invalid radius_km"))
+			return
+		}
+		q.RadiusKm = radius
+	}
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil && val > 0 {
+			q.Limit = val
+		}
+	}
+	if d := c.Query("date"); d != "" {
+		date, err := parseDate(d, c.Query("timezone_offset"))
+		if err != nil {
+			apperrors.Abort(c, apperrors.InvalidQuery("invalid date format"))
+			return
+		}
+		q.Date = &date
+	}
+
+	results, err := h.finder.Find(c.Request.Context(), q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 
-	type timeRange struct {
-		Start time.Time
-		End   time.Time
-	}
-	busySlots := []timeRange{}
-
-	var serviceDestinations map[string]int
-
-	for _, appt := range appointments {
-		dur := appt.DurationMinutes
-		if dur == 0 {
-			if serviceDestinations == nil {
-				allServices, _ := h.servicesRepo.List(c.Request.Context(), 100, 0, providerId)
-				serviceDestinations = make(map[string]int)
-				for _, s := range allServices {
-					serviceDestinations[s.ID] = s.DurationMinutes
-				}
-			}
-			dur = serviceDestinations[appt.ServiceId]
-			if dur == 0 {
-				dur = 60
-			}
-		}
-		end := appt.ScheduledAt.Add(time.Duration(dur) * time.Minute)
-		busySlots = append(busySlots, timeRange{Start: appt.ScheduledAt, End: end})
+	profiles := make([]profile.Profile, 0, len(results))
+	for _, r := range results {
+		p := profile.New(r.Provider, nil)
+		distance := r.DistanceKm
+		p.DistanceKm = &distance
+		profiles = append(profiles, p)
 	}
+	c.JSON(http.StatusOK, gin.H{"data": profiles})
+}
 
-	var availableSlots []string
+// GetProfile returns the public profile of the provider with its business
+// hours.
+func (h *PublicHandler) GetProfile(c *gin.Context) {
+	provider := providerFrom(c)
+	schedule, err := h.schedulesRepo.GetByProvider(c.Request.Context(), provider.ID, domain.ScheduleTypeGlobal)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
 
-	for _, workRange := range daySchedule.Ranges {
-		var startH, startM, endH, endM int
-		fmt.Sscanf(workRange.Start, "%d:%d", &startH, &startM)
-		fmt.Sscanf(workRange.End, "%d:%d", &endH, &endM)
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, profile.New(provider, schedule))
+}
 
-		currentSlot := time.Date(date.Year(), date.Month(), date.Day(), startH, startM, 0, 0, date.Location())
-		rangeEnd := time.Date(date.Year(), date.Month(), date.Day(), endH, endM, 0, 0, date.Location())
+// GetServices godoc
+// @Summary List Public Services
+// @Description List the public services of a provider that are active now, grouped by category in sort order; the services without a category come last, in a group with an empty name
+// @Tags Public
+// @Produce  json
+// @Param provider_id path string true "Provider ID or slug"
+// @Success 200 {array} catalog.Category
+// @Router /public/providers/{provider_id}/services [get]
+func (h *PublicHandler) GetServices(c *gin.Context) {
+	providerId := providerFrom(c).ID
 
-		for currentSlot.Before(rangeEnd) {
-			dur := service.DurationMinutes
-			if dur <= 0 {
-				dur = 30
-			}
-			slotEnd := currentSlot.Add(time.Duration(dur) * time.Minute)
+	c.Header("Cache-Control", "public, max-age=300")
 
-			if slotEnd.After(rangeEnd) {
-				break
-			}
+	services, err := catalog.Listed(c.Request.Context(), h.servicesRepo, providerId, utils.Now())
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, catalog.Group(services))
+}
 
-			isBusy := false
-			for _, busy := range busySlots {
-				if currentSlot.Before(busy.End) && slotEnd.After(busy.Start) {
-					isBusy = true
-					break
-				}
-			}
+func (h *PublicHandler) GetAvailableSlots(c *gin.Context) {
+	providerId := providerFrom(c).ID
+	dateStr := c.Query("date")
+	serviceID := c.Query("service")
+	tzOffsetStr := c.Query("timezone_offset")
 
-			if !isBusy {
-				availableSlots = append(availableSlots, currentSlot.Format("15:04"))
-			}
+	if dateStr == "" || serviceID == "" {
+		apperrors.Abort(c, apperrors.InvalidQuery("date and service are required"))
+		return
+	}
 
-			currentSlot = currentSlot.Add(30 * time.Minute)
-		}
+	service, err := h.servicesRepo.Get(c.Request.Context(), serviceID)
+	if err == nil && service.Private {
+		err = domain.ErrNotFound
+	}
+	if err != nil {
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeServiceNotFound, "service not found"))
+		return
+	}
+	if service.ProviderId != providerId {
+		apperrors.Abort(c, booking.ErrServiceNotOwned)
+		return
 	}
 
-	if availableSlots == nil {
-		availableSlots = []string{}
+	date, err := parseDate(dateStr, tzOffsetStr)
+	if err != nil {
+		apperrors.Abort(c, apperrors.InvalidQuery("invalid date format"))
+		return
 	}
 
-	c.JSON(http.StatusOK, availableSlots)
+	schedule, err := h.schedulesRepo.GetByProvider(c.Request.Context(), providerId, domain.ScheduleTypeGlobal)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	slots, err := h.booker.AvailableSlots(c.Request.Context(), service, schedule, date)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, slots)
 }
 
-func (h *PublicHandler) CreateAppointment(c *gin.Context) {
-	providerId := c.Param("provider_id")
-	if providerId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "provider_id is required"})
+// CreateHold reserves a slot while the customer fills in the booking form.
+// The hold expires after booking.HoldDuration; until then the slot is
+// taken for everyone else.
+func (h *PublicHandler) CreateHold(c *gin.Context) {
+	providerId := providerFrom(c).ID
+
+	var req requests.Hold
+	if err := requests.Bind(c, &req); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
 
-	var m domain.Appointments
-	if err := c.ShouldBindJSON(&m); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	hold, err := h.booker.Hold(c.Request.Context(), providerId, req.ToDomain())
+	if err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
+	c.JSON(http.StatusCreated, hold)
+}
 
-	m.ProviderId = providerId
-	if m.ServiceId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "service_id is required"})
+// ReleaseHold gives a held slot back, e.g. when the customer picks another
+// one.
+func (h *PublicHandler) ReleaseHold(c *gin.Context) {
+	hold, err := h.holdsRepo.Get(c.Request.Context(), c.Param("hold_id"))
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && hold.ProviderId != providerFrom(c).ID) {
+		apperrors.Abort(c, booking.ErrHoldNotFound)
 		return
 	}
-	service, err := h.servicesRepo.Get(c.Request.Context(), m.ServiceId)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service_id"})
+		apperrors.Abort(c, err)
 		return
 	}
-	if service.ProviderId != providerId {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "service does not belong to provider"})
+	if err := h.holdsRepo.Delete(c.Request.Context(), hold.ID); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
+	c.Status(http.StatusNoContent)
+}
 
-	m.DurationMinutes = service.DurationMinutes
-	if m.DurationMinutes == 0 {
-		m.DurationMinutes = 30
-	}
-	m.ServiceName = service.Title
+// CreateAppointment books a slot. With hold_id it books the slot of that
+// hold, taking its service and time, and releases it. The intake answers
+// in the notes must fill in the service's intake form.
+//
+// @Summary Book Appointment
+// @Description Book a slot from the public widget, answering the service's intake form
+// @Tags Public
+// @Accept  json
+// @Produce  json
+// @Param provider_id path string true "Provider ID or slug"
+// @Param body body requests.PublicAppointment true "Booking"
+// @Success 201 {object} domain.Appointments
+// @Failure 400 {object} apperrors.Problem
+// @Failure 409 {object} apperrors.Problem
+// @Router /public/providers/{provider_id}/appointments [post]
+func (h *PublicHandler) CreateAppointment(c *gin.Context) {
+	providerId := providerFrom(c).ID
 
-	now := utils.Now()
-	if m.ScheduledAt.Before(now.Add(-5 * time.Minute)) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot create appointment in the past"})
+	var req requests.PublicAppointment
+	if err := requests.Bind(c, &req); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
+	m := req.ToDomain()
 
-	date := m.ScheduledAt
-	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, waitingLocation(date.Location()))
-	
-	appointments, err := h.appointmentsRepo.ListByDate(c.Request.Context(), startOfDay, providerId)
+	var err error
+	if req.HoldId != "" {
+		err = h.booker.PrepareHeld(c.Request.Context(), providerId, m, req.HoldId)
+	} else {
+		err = h.booker.Prepare(c.Request.Context(), providerId, m)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check availability"})
+		apperrors.Abort(c, err)
+		return
+	}
+	if err := h.booker.CheckIntake(c.Request.Context(), m); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
 
-	newApptStart := m.ScheduledAt
-	newApptEnd := newApptStart.Add(time.Duration(m.DurationMinutes) * time.Minute)
-
-	var serviceDestinations map[string]int
-
-	for _, existing := range appointments {
-		existingDur := existing.DurationMinutes
-		if existingDur == 0 {
-			if serviceDestinations == nil {
-				allServices, _ := h.servicesRepo.List(c.Request.Context(), 100, 0, providerId)
-				serviceDestinations = make(map[string]int)
-				for _, s := range allServices {
-					serviceDestinations[s.ID] = s.DurationMinutes
-				}
-			}
-			existingDur = serviceDestinations[existing.ServiceId]
-			if existingDur == 0 {
-				existingDur = 60
-			}
-		}
-
-		existingStart := existing.ScheduledAt
-		existingEnd := existingStart.Add(time.Duration(existingDur) * time.Minute)
-
-		if newApptStart.Before(existingEnd) && newApptEnd.After(existingStart) {
-			c.JSON(http.StatusConflict, gin.H{"error": "time slot is already booked"})
+	m.CustomerEmail = strings.ToLower(strings.TrimSpace(m.CustomerEmail))
+	if h.maxUpcoming > 0 {
+		if err := h.booker.CheckCustomerLimit(c.Request.Context(), m, h.maxUpcoming); err != nil {
+			apperrors.Abort(c, err)
 			return
 		}
 	}
-	
+
 	m.Status = "confirmed"
-	
-	id, err := h.appointmentsRepo.Create(c.Request.Context(), &m)
+
+	id, err := h.appointmentsRepo.Create(c.Request.Context(), m)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 	m.ID = id
-	c.JSON(http.StatusCreated, m)
-}
 
-func waitingLocation(loc *time.Location) *time.Location {
-	if loc == nil {
-		return time.UTC
+	if req.HoldId != "" {
+		// The booking already covers the slot; a hold left behind just
+		// expires.
+		if err := h.holdsRepo.Delete(c.Request.Context(), req.HoldId); err != nil {
+			log.Printf("releasing hold %s: %v", req.HoldId, err)
+		}
 	}
-	return loc
+	c.JSON(http.StatusCreated, m)
 }
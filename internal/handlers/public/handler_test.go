@@ -0,0 +1,341 @@
+package public
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/apperrors"
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/memory"
+	"ServiceBookingApp/internal/profile"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	providersRepo := memory.NewProvidersRepository(store)
+	schedulesRepo := memory.NewSchedulesRepository(store)
+	id, err := providersRepo.Create(ctx, &domain.Providers{UserId: "user-1", Slug: "tano", EstablishmentName: "Tano", City: "Rosario", BusinessType: "barbershop"})
+	require.NoError(t, err)
+	_, err = providersRepo.Create(ctx, &domain.Providers{UserId: "user-2", Slug: "clinica", City: "Rosario", BusinessType: "clinic"})
+	require.NoError(t, err)
+	require.NoError(t, schedulesRepo.Upsert(ctx, &domain.Schedule{ProviderId: id, Type: domain.ScheduleTypeGlobal, Days: map[string]domain.DaySchedule{
+		"mon": {Enabled: true, Ranges: []domain.TimeRange{{Start: "09:00", End: "13:00"}}},
+	}}))
+
+	handler := NewPublicHandler(memory.NewServicesRepository(store), schedulesRepo, memory.NewAppointmentsRepository(store), providersRepo, memory.NewHoldsRepository(store), 0)
+	r := gin.New()
+	r.Use(apperrors.Middleware())
+	r.GET("/public/providers", handler.Search)
+	group := r.Group("/public/providers/:provider_id", handler.ResolveProvider)
+	group.GET("", handler.GetProfile)
+	group.GET("/services", handler.GetServices)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, key := range []string{id, "tano"} {
+		w := get("/public/providers/" + key)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "user-1")
+
+		var p profile.Profile
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		assert.Equal(t, id, p.ID)
+		assert.Equal(t, "Tano", p.EstablishmentName)
+		require.Len(t, p.Hours, 7)
+		assert.True(t, p.Hours[0].Open)
+	}
+
+	assert.Equal(t, http.StatusOK, get("/public/providers/tano/services").Code)
+	assert.Equal(t, http.StatusNotFound, get("/public/providers/nobody").Code)
+	assert.Equal(t, http.StatusNotFound, get("/public/providers/nobody/services").Code)
+
+	var resp struct {
+		Data []profile.Profile `json:"data"`
+	}
+	w := get("/public/providers?city=Rosario&category=barbershop")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "tano", resp.Data[0].Slug)
+}
+
+func TestCreateAppointmentCustomerLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	providersRepo := memory.NewProvidersRepository(store)
+	servicesRepo := memory.NewServicesRepository(store)
+	providerId, err := providersRepo.Create(ctx, &domain.Providers{UserId: "user-1"})
+	require.NoError(t, err)
+	serviceId, err := servicesRepo.Create(ctx, &domain.Services{ProviderId: providerId, Title: "Corte", DurationMinutes: 30})
+	require.NoError(t, err)
+
+	handler := NewPublicHandler(servicesRepo, memory.NewSchedulesRepository(store), memory.NewAppointmentsRepository(store), providersRepo, memory.NewHoldsRepository(store), 2)
+	r := gin.New()
+	r.Use(apperrors.Middleware())
+	r.POST("/public/providers/:provider_id/appointments", handler.ResolveProvider, handler.CreateAppointment)
+
+	book := func(email string, hours int) int {
+		body, _ := json.Marshal(map[string]interface{}{
+			"service_id":     serviceId,
+			"customer_email": email,
+			"scheduled_at":   time.Now().Add(time.Duration(hours) * time.Hour).UTC(),
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/public/providers/"+providerId+"/appointments", bytes.NewReader(body))
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, book("ana@example.com", 24))
+	assert.Equal(t, http.StatusCreated, book("Ana@Example.com ", 25))
+	assert.Equal(t, http.StatusConflict, book("ana@example.com", 26))
+	assert.Equal(t, http.StatusCreated, book("bea@example.com", 27))
+	assert.Equal(t, http.StatusBadRequest, book("", 28))
+}
+
+func TestCreateAppointmentIntake(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	providersRepo := memory.NewProvidersRepository(store)
+	servicesRepo := memory.NewServicesRepository(store)
+	appointmentsRepo := memory.NewAppointmentsRepository(store)
+	providerId, err := providersRepo.Create(ctx, &domain.Providers{UserId: "user-1"})
+	require.NoError(t, err)
+	serviceId, err := servicesRepo.Create(ctx, &domain.Services{ProviderId: providerId, Title: "Tintura", DurationMinutes: 60, IntakeForm: []domain.IntakeField{
+		{Id: "allergies", Label: "¿Tenés alergias?", Type: domain.IntakeText, Required: true},
+		{Id: "length", Label: "Largo del pelo", Type: domain.IntakeChoice, Required: true, Options: []string{"corto", "medio", "largo"}},
+		{Id: "last_dye", Label: "Última tintura", Type: domain.IntakeDate},
+		{Id: "consent", Label: "Acepto la prueba de alergia", Type: domain.IntakeCheckbox, Required: true},
+	}})
+	require.NoError(t, err)
+
+	handler := NewPublicHandler(servicesRepo, memory.NewSchedulesRepository(store), appointmentsRepo, providersRepo, memory.NewHoldsRepository(store), 0)
+	r := gin.New()
+	r.Use(apperrors.Middleware())
+	r.POST("/public/providers/:provider_id/appointments", handler.ResolveProvider, handler.CreateAppointment)
+
+	book := func(hours int, answers []gin.H) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(gin.H{
+			"service_id":     serviceId,
+			"customer_email": "ana@example.com",
+			"scheduled_at":   time.Now().Add(time.Duration(hours) * time.Hour).UTC(),
+			"notes":          gin.H{"text": "primera vez", "answers": answers},
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/public/providers/"+providerId+"/appointments", bytes.NewReader(body))
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := book(24, []gin.H{
+		{"field_id": "length", "answer": "largo"},
+		{"field_id": "consent", "answer": false},
+		{"field_id": "last_dye", "answer": "ayer"},
+		{"field_id": "color", "answer": "rojo"},
+	})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var problem apperrors.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, apperrors.CodeValidationFailed, problem.Code)
+	assert.ElementsMatch(t, []apperrors.FieldError{
+		{Field: "notes.answers[color]", Message: "is not a field of the intake form"},
+		{Field: "notes.answers[allergies]", Message: "is required"},
+		{Field: "notes.answers[last_dye]", Message: "must be a date as YYYY-MM-DD"},
+		{Field: "notes.answers[consent]", Message: "is required"},
+	}, problem.Errors)
+
+	w = book(24, []gin.H{
+		{"field_id": "consent", "answer": true},
+		{"field_id": "length", "answer": "largo"},
+		{"field_id": "allergies", "answer": " Ninguna "},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created domain.Appointments
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	stored, err := appointmentsRepo.Get(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Notes{Text: "primera vez", Answers: []domain.IntakeAnswer{
+		{FieldId: "allergies", Question: "¿Tenés alergias?", Answer: "Ninguna"},
+		{FieldId: "length", Question: "Largo del pelo", Answer: "largo"},
+		{FieldId: "consent", Question: "Acepto la prueba de alergia", Answer: "true"},
+	}}, stored.Notes)
+}
+
+func TestHolds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	providersRepo := memory.NewProvidersRepository(store)
+	servicesRepo := memory.NewServicesRepository(store)
+	schedulesRepo := memory.NewSchedulesRepository(store)
+	holdsRepo := memory.NewHoldsRepository(store)
+	providerId, err := providersRepo.Create(ctx, &domain.Providers{UserId: "user-1"})
+	require.NoError(t, err)
+	serviceId, err := servicesRepo.Create(ctx, &domain.Services{ProviderId: providerId, Title: "Corte", DurationMinutes: 60})
+	require.NoError(t, err)
+	days := map[string]domain.DaySchedule{}
+	for _, d := range []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"} {
+		days[d] = domain.DaySchedule{Enabled: true, Ranges: []domain.TimeRange{{Start: "10:00", End: "12:00"}}}
+	}
+	require.NoError(t, schedulesRepo.Upsert(ctx, &domain.Schedule{ProviderId: providerId, Type: domain.ScheduleTypeGlobal, Days: days}))
+
+	handler := NewPublicHandler(servicesRepo, schedulesRepo, memory.NewAppointmentsRepository(store), providersRepo, holdsRepo, 0)
+	r := gin.New()
+	r.Use(apperrors.Middleware())
+	group := r.Group("/public/providers/:provider_id", handler.ResolveProvider)
+	group.GET("/slots", handler.GetAvailableSlots)
+	group.POST("/holds", handler.CreateHold)
+	group.DELETE("/holds/:hold_id", handler.ReleaseHold)
+	group.POST("/appointments", handler.CreateAppointment)
+
+	base := "/public/providers/" + providerId
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, base+path, bytes.NewReader(b))
+		r.ServeHTTP(w, req)
+		return w
+	}
+	day := time.Now().UTC().AddDate(0, 0, 2).Truncate(24 * time.Hour)
+	slots := func() []string {
+		w := do("GET", "/slots?service="+serviceId+"&date="+day.Format("2006-01-02"), nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var s []string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &s))
+		return s
+	}
+	slot := map[string]interface{}{"service_id": serviceId, "scheduled_at": day.Add(10 * time.Hour)}
+
+	require.Equal(t, []string{"10:00", "10:30", "11:00"}, slots())
+
+	w := do("POST", "/holds", slot)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var hold domain.Hold
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &hold))
+	assert.True(t, hold.ExpiresAt.After(time.Now()))
+
+	assert.Equal(t, []string{"11:00"}, slots())
+	assert.Equal(t, http.StatusConflict, do("POST", "/holds", slot).Code)
+	assert.Equal(t, http.StatusConflict, do("POST", "/appointments", slot).Code)
+
+	w = do("POST", "/holds", map[string]interface{}{"service_id": serviceId, "scheduled_at": day.Add(11*time.Hour + 30*time.Minute)})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, "the hour runs past closing time")
+	assert.Contains(t, w.Body.String(), `"code":"OUTSIDE_BUSINESS_HOURS"`)
+
+	w = do("POST", "/appointments", map[string]interface{}{"hold_id": hold.ID, "customer_name": "Ana"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var appt domain.Appointments
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &appt))
+	assert.Equal(t, serviceId, appt.ServiceId)
+	assert.True(t, day.Add(10*time.Hour).Equal(appt.ScheduledAt))
+	_, err = holdsRepo.Get(ctx, hold.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Equal(t, http.StatusNotFound, do("POST", "/appointments", map[string]interface{}{"hold_id": hold.ID}).Code)
+
+	expired, err := holdsRepo.Create(ctx, &domain.Hold{ProviderId: providerId, ServiceId: serviceId, ScheduledAt: day.Add(11 * time.Hour), DurationMinutes: 60, ExpiresAt: time.Now().Add(-time.Second)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"11:00"}, slots())
+	assert.Equal(t, http.StatusGone, do("POST", "/appointments", map[string]interface{}{"hold_id": expired}).Code)
+
+	w = do("POST", "/holds", map[string]interface{}{"service_id": serviceId, "scheduled_at": day.Add(11 * time.Hour)})
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &hold))
+	assert.Empty(t, slots())
+	assert.Equal(t, http.StatusNoContent, do("DELETE", "/holds/"+hold.ID, nil).Code)
+	assert.Equal(t, []string{"11:00"}, slots())
+}
+
+func TestServiceVisibility(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	providersRepo := memory.NewProvidersRepository(store)
+	servicesRepo := memory.NewServicesRepository(store)
+	providerId, err := providersRepo.Create(ctx, &domain.Providers{UserId: "user-1"})
+	require.NoError(t, err)
+	day := time.Now().UTC().AddDate(0, 0, 2).Truncate(24 * time.Hour)
+	seasonEnd := day.Add(-time.Hour)
+	create := func(s *domain.Services) string {
+		s.ProviderId, s.DurationMinutes = providerId, 30
+		id, err := servicesRepo.Create(ctx, s)
+		require.NoError(t, err)
+		return id
+	}
+	create(&domain.Services{Title: "Tinte", Category: "Color", SortOrder: 2})
+	create(&domain.Services{Title: "Corte", Category: "Cortes", SortOrder: 1})
+	create(&domain.Services{Title: "Consulta", SortOrder: 0})
+	create(&domain.Services{Title: "Mechas", Category: "Color", SortOrder: 3})
+	privateId := create(&domain.Services{Title: "Retoque", Category: "Color", Private: true})
+	seasonalId := create(&domain.Services{Title: "Peinado de fiesta", Category: "Eventos", SortOrder: 4, ActiveTo: &seasonEnd})
+
+	handler := NewPublicHandler(servicesRepo, memory.NewSchedulesRepository(store), memory.NewAppointmentsRepository(store), providersRepo, memory.NewHoldsRepository(store), 0)
+	r := gin.New()
+	r.Use(apperrors.Middleware())
+	group := r.Group("/public/providers/:provider_id", handler.ResolveProvider)
+	group.GET("/services", handler.GetServices)
+	group.GET("/slots", handler.GetAvailableSlots)
+	group.POST("/appointments", handler.CreateAppointment)
+
+	base := "/public/providers/" + providerId
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, base+path, bytes.NewReader(b))
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("GET", "/services", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var groups []struct {
+		Name     string            `json:"name"`
+		Services []domain.Services `json:"services"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &groups))
+	titles := map[string][]string{}
+	var names []string
+	for _, g := range groups {
+		names = append(names, g.Name)
+		for _, s := range g.Services {
+			titles[g.Name] = append(titles[g.Name], s.Title)
+		}
+	}
+	assert.Equal(t, []string{"Cortes", "Color", "Eventos", ""}, names)
+	assert.Equal(t, []string{"Tinte", "Mechas"}, titles["Color"])
+	assert.Equal(t, []string{"Consulta"}, titles[""])
+
+	book := func(serviceId string) *httptest.ResponseRecorder {
+		return do("POST", "/appointments", gin.H{"service_id": serviceId, "customer_email": "ana@example.com", "scheduled_at": day.Add(10 * time.Hour)})
+	}
+	w = book(privateId)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"SERVICE_NOT_FOUND"`)
+	assert.Equal(t, http.StatusNotFound, do("GET", "/slots?service="+privateId+"&date="+day.Format("2006-01-02"), nil).Code)
+
+	w = book(seasonalId)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"SERVICE_UNAVAILABLE"`)
+}
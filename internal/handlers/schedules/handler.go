@@ -3,28 +3,35 @@ package schedules
 import (
 	"net/http"
 
+	"ServiceBookingApp/internal/apperrors"
+	authService "ServiceBookingApp/internal/auth"
 	"ServiceBookingApp/internal/domain"
-	
-	"firebase.google.com/go/v4/auth"
+	"ServiceBookingApp/internal/requests"
+
 	"github.com/gin-gonic/gin"
 )
 
 type SchedulesHandler struct {
 	repo          domain.SchedulesRepository
 	providersRepo domain.ProvidersRepository
+	policy        *authService.Policy
 }
 
 func NewSchedulesHandler(repo domain.SchedulesRepository, providersRepo domain.ProvidersRepository) *SchedulesHandler {
 	return &SchedulesHandler{
 		repo:          repo,
 		providersRepo: providersRepo,
+		policy:        authService.NewPolicy(providersRepo),
 	}
 }
 
 func (h *SchedulesHandler) GetByProvider(c *gin.Context) {
 	providerID := c.Query("provider_id")
 	if providerID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "provider_id is required"})
+		apperrors.Abort(c, apperrors.InvalidQuery("provider_id is required"))
+		return
+	}
+	if !h.policy.AuthorizeProvider(c, providerID) {
 		return
 	}
 
@@ -35,7 +42,7 @@ func (h *SchedulesHandler) GetByProvider(c *gin.Context) {
 
 	schedule, err := h.repo.GetByProvider(c.Request.Context(), providerID, domain.ScheduleType(scheduleType))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 
@@ -52,33 +59,20 @@ func (h *SchedulesHandler) GetByProvider(c *gin.Context) {
 }
 
 func (h *SchedulesHandler) Upsert(c *gin.Context) {
-	u, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		return
-	}
-	token := u.(*auth.Token)
-
-	provider, err := h.providersRepo.GetByUserId(c.Request.Context(), token.UID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	if provider == nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "user is not a provider"})
+	provider, ok := h.policy.RequireProvider(c)
+	if !ok {
 		return
 	}
 
-	var schedule domain.Schedule
-	if err := c.ShouldBindJSON(&schedule); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req requests.Schedule
+	if err := requests.Bind(c, &req); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
+	schedule := req.ToDomain(provider.ID)
 
-	schedule.ProviderId = provider.ID
-
-	if err := h.repo.Upsert(c.Request.Context(), &schedule); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.repo.Upsert(c.Request.Context(), schedule); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
 
@@ -1,183 +1,242 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 
+	"ServiceBookingApp/internal/apperrors"
+	authService "ServiceBookingApp/internal/auth"
 	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/requests"
 	"ServiceBookingApp/internal/utils"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
 )
 
 type ServicesHandler struct {
 	repo          domain.ServicesRepository
 	providersRepo domain.ProvidersRepository
+	policy        *authService.Policy
 }
 
 func NewServicesHandler(repo domain.ServicesRepository, providersRepo domain.ProvidersRepository) *ServicesHandler {
 	return &ServicesHandler{
 		repo:          repo,
 		providersRepo: providersRepo,
+		policy:        authService.NewPolicy(providersRepo),
 	}
 }
 
 func (h *ServicesHandler) getProviderID(c *gin.Context) (string, error) {
-	u, exists := c.Get("user")
-	if !exists {
-		return "", fmt.Errorf("user not found in context")
-	}
-	token := u.(*auth.Token)
-	
-	provider, err := h.providersRepo.GetByUserId(c.Request.Context(), token.UID)
+	provider, err := h.policy.CallerProvider(c)
 	if err != nil {
 		return "", err
 	}
-	if provider == nil {
-		return "", fmt.Errorf("user is not a provider")
-	}
 	return provider.ID, nil
 }
 
 func (h *ServicesHandler) List(c *gin.Context) {
 	providerId := c.Query("provider_id")
 	if providerId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "provider_id is required"})
+		apperrors.Abort(c, apperrors.InvalidQuery("provider_id is required"))
+		return
+	}
+	if !h.policy.AuthorizeProvider(c, providerId) {
 		return
 	}
 
-	limit := 20
+	includeDeleted, ok := authService.IncludeDeleted(c)
+	if !ok {
+		return
+	}
+
+	opts := domain.ListOptions{Cursor: c.Query("cursor"), IncludeDeleted: includeDeleted}
 	if l := c.Query("limit"); l != "" {
 		if val, err := strconv.Atoi(l); err == nil && val > 0 {
-			limit = val
+			opts.Limit = val
 		}
 	}
-	page := 1
-	if p := c.Query("page"); p != "" {
-		if val, err := strconv.Atoi(p); err == nil && val > 0 {
-			page = val
-		}
-	}
-	offset := (page - 1) * limit
 
-	results, err := h.repo.List(c.Request.Context(), limit, offset, providerId)
+	results, nextCursor, err := h.repo.List(c.Request.Context(), opts, providerId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, results)
+	c.JSON(http.StatusOK, gin.H{"data": results, "next_cursor": nextCursor})
 }
 
+// Get godoc
+// @Summary Get Service
+// @Description Get a service of the authenticated provider
+// @Tags Services
+// @Produce  json
+// @Param id path string true "Service ID"
+// @Success 200 {object} domain.Services
+// @Failure 404 {object} apperrors.Problem
+// @Router /api/services/{id} [get]
 func (h *ServicesHandler) Get(c *gin.Context) {
 	id := c.Param("id")
-	result, err := h.repo.Get(c.Request.Context(), id)
+	includeDeleted, ok := authService.IncludeDeleted(c)
+	if !ok {
+		return
+	}
+
+	get := h.repo.Get
+	if includeDeleted {
+		get = h.repo.GetIncludingDeleted
+	}
+	result, err := get(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeServiceNotFound, "service not found"))
+		return
+	}
+	// Customers read services through the public widget; this route is for
+	// the provider's dashboard.
+	if !h.policy.AuthorizeProvider(c, result.ProviderId) {
 		return
 	}
-	// TODO: Checks regarding ownership?
-	// Publicly readable? Usually yes for booking.
 	c.JSON(http.StatusOK, result)
 }
 
+// Create godoc
+// @Summary Create Service
+// @Description Create a service; the Markdown description is stored with its sanitized HTML
+// @Tags Services
+// @Accept  json
+// @Produce  json
+// @Param body body requests.CreateService true "Service"
+// @Success 201 {object} domain.Services
+// @Failure 400 {object} apperrors.Problem
+// @Router /api/services [post]
 func (h *ServicesHandler) Create(c *gin.Context) {
 	providerId, err := h.getProviderID(c)
 	if err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "must be a provider to create services"})
+		apperrors.Abort(c, err)
 		return
 	}
 
-	var m domain.Services
-	if err := c.ShouldBindJSON(&m); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req requests.CreateService
+	if err := requests.Bind(c, &req); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
+	m := req.ToDomain(providerId)
 
-	m.ProviderId = providerId
-
-	id, err := h.repo.Create(c.Request.Context(), &m)
+	id, err := h.repo.Create(c.Request.Context(), m)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 	m.ID = id
 	c.JSON(http.StatusCreated, m)
 }
 
+// Update godoc
+// @Summary Update Service
+// @Description Update a service; omitted fields are left as they are
+// @Tags Services
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Service ID"
+// @Param body body requests.UpdateService true "Fields to change"
+// @Success 200 {object} domain.Services
+// @Failure 400 {object} apperrors.Problem
+// @Failure 404 {object} apperrors.Problem
+// @Router /api/services/{id} [put]
 func (h *ServicesHandler) Update(c *gin.Context) {
 	id := c.Param("id")
 	
-	providerId, err := h.getProviderID(c)
-	if err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "must be a provider"})
-		return
-	}
-
 	existing, err := h.repo.Get(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeServiceNotFound, "service not found"))
 		return
 	}
-	
-	if existing.ProviderId != providerId {
-		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	if !h.policy.AuthorizeProvider(c, existing.ProviderId) {
 		return
 	}
 
-	var updates domain.Services
-	if err := c.ShouldBindJSON(&updates); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req requests.UpdateService
+	if err := requests.Bind(c, &req); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
-	
-	if updates.Title != "" {
-		existing.Title = updates.Title
-	}
-	if updates.Description != nil {
-		existing.Description = updates.Description
-	}
-	if updates.DurationMinutes != 0 {
-		existing.DurationMinutes = updates.DurationMinutes
-	}
-	if updates.Price != 0 {
-		existing.Price = updates.Price
-	}
-	if updates.IconUrl != "" {
-		existing.IconUrl = updates.IconUrl
-	}
-	if updates.Color != "" {
-		existing.Color = updates.Color
+	req.Apply(existing)
+	// The body may move one bound past the other one already stored.
+	if existing.ActiveFrom != nil && existing.ActiveTo != nil && !existing.ActiveTo.After(*existing.ActiveFrom) {
+		apperrors.Abort(c, apperrors.Invalid([]apperrors.FieldError{{Field: "active_to", Message: "must be after active_from"}}))
+		return
 	}
-	
+
 	existing.UpdatedAt = utils.Now()
 	
 	if err := h.repo.Update(c.Request.Context(), id, existing); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 	
 	c.JSON(http.StatusOK, existing)
 }
 
-func (h *ServicesHandler) Delete(c *gin.Context) {
-	id := c.Param("id")
-	
-	providerId, err := h.getProviderID(c)
+// Reorder godoc
+// @Summary Reorder Services
+// @Description Set the order services are listed in: the services in service_ids come first, in that order, followed by the caller's other services in their current order. Returns every service of the caller in the new order.
+// @Tags Services
+// @Accept  json
+// @Produce  json
+// @Param body body requests.ReorderServices true "Service IDs in order"
+// @Success 200 {array} domain.Services
+// @Failure 400 {object} apperrors.Problem
+// @Router /api/services/order [put]
+func (h *ServicesHandler) Reorder(c *gin.Context) {
+	var req requests.ReorderServices
+	if err := requests.Bind(c, &req); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	// Check every ID before writing, so a bad one is reported per field.
+	ctx := c.Request.Context()
+	var providerId string
+	var fields []apperrors.FieldError
+	for i, id := range req.ServiceIds {
+		service, err := h.repo.Get(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			fields = append(fields, apperrors.FieldError{Field: fmt.Sprintf("service_ids[%d]", i), Message: "service not found"})
+			continue
+		}
+		if err != nil {
+			apperrors.Abort(c, err)
+			return
+		}
+		if !h.policy.AuthorizeProvider(c, service.ProviderId) {
+			return
+		}
+		providerId = service.ProviderId
+	}
+	if len(fields) > 0 {
+		apperrors.Abort(c, apperrors.Invalid(fields))
+		return
+	}
+
+	results, err := h.repo.Reorder(ctx, providerId, req.ServiceIds)
 	if err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "must be a provider"})
+		apperrors.Abort(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, results)
+}
 
+func (h *ServicesHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+	
 	service, err := h.repo.Get(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeServiceNotFound, "service not found"))
 		return
 	}
-	
-	if service.ProviderId != providerId {
-		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	if !h.policy.AuthorizeProvider(c, service.ProviderId) {
 		return
 	}
 
@@ -185,9 +244,33 @@ func (h *ServicesHandler) Delete(c *gin.Context) {
 	service.DeletedAt = &now
 	
 	if err := h.repo.Update(c.Request.Context(), id, service); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 	
 	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
 }
+
+func (h *ServicesHandler) Restore(c *gin.Context) {
+	id := c.Param("id")
+
+	service, err := h.repo.GetIncludingDeleted(c.Request.Context(), id)
+	if err != nil {
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeServiceNotFound, "service not found"))
+		return
+	}
+	if !h.policy.AuthorizeProvider(c, service.ProviderId) {
+		return
+	}
+	if service.DeletedAt == nil {
+		apperrors.Abort(c, apperrors.New(http.StatusConflict, apperrors.CodeNotDeleted, "service is not deleted"))
+		return
+	}
+
+	service.DeletedAt = nil
+	if err := h.repo.Update(c.Request.Context(), id, service); err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, service)
+}
@@ -8,80 +8,145 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"ServiceBookingApp/internal/apperrors"
 	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/memory"
+
+	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-type MockServicesRepository struct {
-	Data map[string]*domain.Services
-}
-
-func (m *MockServicesRepository) List(ctx context.Context, limit, offset int) ([]*domain.Services, error) {
-	var results []*domain.Services
-	for _, v := range m.Data {
-		results = append(results, v)
-	}
-	
-	if offset >= len(results) {
-		return []*domain.Services{}, nil
-	}
-	end := offset + limit
-	if end > len(results) {
-		end = len(results)
-	}
-	return results[offset:end], nil
-}
-
-func (m *MockServicesRepository) Get(ctx context.Context, id string) (*domain.Services, error) {
-	if val, ok := m.Data[id]; ok {
-		return val, nil
-	}
-	return nil, nil
-}
-
-func (m *MockServicesRepository) Create(ctx context.Context, model *domain.Services) (string, error) {
-	id := "test-id"
-	model.ID = id
-	if m.Data == nil {
-		m.Data = make(map[string]*domain.Services)
-	}
-	m.Data[id] = model
-	return id, nil
-}
-
-func (m *MockServicesRepository) Update(ctx context.Context, id string, model *domain.Services) error {
-	m.Data[id] = model
-	return nil
-}
-
-func (m *MockServicesRepository) Delete(ctx context.Context, id string) error {
-	delete(m.Data, id)
-	return nil
-}
-
 func TestServicesHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	repo := &MockServicesRepository{Data: make(map[string]*domain.Services)}
-	handler := NewServicesHandler(repo)
+
+	store := memory.NewStore()
+	repo := memory.NewServicesRepository(store)
+	providersRepo := memory.NewProvidersRepository(store)
+	providerId, err := providersRepo.Create(context.Background(), &domain.Providers{UserId: "user-1"})
+	require.NoError(t, err)
+
+	handler := NewServicesHandler(repo, providersRepo)
 	r := gin.Default()
+	r.Use(apperrors.Middleware())
+	r.Use(func(c *gin.Context) {
+		c.Set("user", &auth.Token{UID: "user-1"})
+	})
 
 	r.GET("/services", handler.List)
 	r.POST("/services", handler.Create)
+	r.GET("/services/:id", handler.Get)
+	r.PUT("/services/order", handler.Reorder)
+	r.PUT("/services/:id", handler.Update)
+	r.DELETE("/services/:id", handler.Delete)
+	r.POST("/services/:id/restore", handler.Restore)
 
 	t.Run("Create", func(t *testing.T) {
 		w := httptest.NewRecorder()
-		body := domain.Services{}
+		body := gin.H{"title": "Corte", "duration_minutes": 30, "description": "Corte **clásico** <script>alert(1)</script>"}
 		jsonBody, _ := json.Marshal(body)
 		req, _ := http.NewRequest("POST", "/services", bytes.NewBuffer(jsonBody))
 		r.ServeHTTP(w, req)
 		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var created domain.Services
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		assert.Equal(t, providerId, created.ProviderId)
+		assert.Equal(t, "Corte **clásico** <script>alert(1)</script>", created.Description.Markdown)
+		assert.Equal(t, "<p>Corte <strong>clásico</strong> alert(1)</p>\n", created.Description.HTML)
 	})
 
 	t.Run("List", func(t *testing.T) {
 		w := httptest.NewRecorder()
-		req, _ := http.NewRequest("GET", "/services?page=1&limit=10", nil)
+		req, _ := http.NewRequest("GET", "/services?provider_id="+providerId+"&limit=10", nil)
 		r.ServeHTTP(w, req)
 		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Data []domain.Services `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Data, 1)
+	})
+
+	t.Run("Reorder", func(t *testing.T) {
+		ctx := context.Background()
+		var ids []string
+		for _, title := range []string{"Color", "Barba", "Peinado"} {
+			id, err := repo.Create(ctx, &domain.Services{ProviderId: providerId, Title: title})
+			require.NoError(t, err)
+			ids = append(ids, id)
+		}
+		otherId, err := repo.Create(ctx, &domain.Services{ProviderId: "someone-else", Title: "Ajeno"})
+		require.NoError(t, err)
+
+		put := func(body interface{}) *httptest.ResponseRecorder {
+			b, _ := json.Marshal(body)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PUT", "/services/order", bytes.NewReader(b))
+			r.ServeHTTP(w, req)
+			return w
+		}
+
+		w := put(gin.H{"service_ids": []string{ids[2], "missing"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"service_ids[1]"`)
+		assert.Equal(t, http.StatusForbidden, put(gin.H{"service_ids": []string{ids[2], otherId}}).Code)
+		stored, err := repo.Get(ctx, ids[2])
+		require.NoError(t, err)
+		assert.Equal(t, 0, stored.SortOrder, "a rejected reorder changes nothing")
+
+		require.Equal(t, http.StatusOK, put(gin.H{"service_ids": []string{ids[2], ids[0], ids[1]}}).Code)
+		for i, id := range []string{ids[2], ids[0], ids[1]} {
+			stored, err := repo.Get(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, i, stored.SortOrder)
+		}
+
+		// Services left out follow in their current order, "Corte" included.
+		w = put(gin.H{"service_ids": []string{ids[1]}})
+		require.Equal(t, http.StatusOK, w.Code)
+		var reordered []domain.Services
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &reordered))
+		var titles []string
+		for i, m := range reordered {
+			assert.Equal(t, i, m.SortOrder)
+			titles = append(titles, m.Title)
+		}
+		assert.Equal(t, []string{"Barba", "Peinado", "Color", "Corte"}, titles)
+
+		// Moving active_to before the stored active_from is caught after the
+		// update is applied.
+		b, _ := json.Marshal(gin.H{"active_from": "2030-12-01T00:00:00Z"})
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/services/"+ids[0], bytes.NewReader(b))
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		b, _ = json.Marshal(gin.H{"active_to": "2030-11-01T00:00:00Z"})
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("PUT", "/services/"+ids[0], bytes.NewReader(b))
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"active_to"`)
+	})
+
+	t.Run("DeleteAndRestore", func(t *testing.T) {
+		id, err := repo.Create(context.Background(), &domain.Services{ProviderId: providerId, Title: "Barba"})
+		require.NoError(t, err)
+
+		do := func(method, path string) int {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, path, nil)
+			r.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		assert.Equal(t, http.StatusConflict, do("POST", "/services/"+id+"/restore"))
+		assert.Equal(t, http.StatusOK, do("DELETE", "/services/"+id))
+		assert.Equal(t, http.StatusNotFound, do("GET", "/services/"+id))
+		assert.Equal(t, http.StatusForbidden, do("GET", "/services/"+id+"?include_deleted=true"))
+		assert.Equal(t, http.StatusOK, do("POST", "/services/"+id+"/restore"))
+		assert.Equal(t, http.StatusOK, do("GET", "/services/"+id))
 	})
 }
@@ -0,0 +1,76 @@
+package stats
+
+import (
+	"net/http"
+	"time"
+
+	"ServiceBookingApp/internal/apperrors"
+	authService "ServiceBookingApp/internal/auth"
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/stats"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxRange = 366 * 24 * time.Hour
+
+type StatsHandler struct {
+	service *stats.Service
+	policy  *authService.Policy
+}
+
+func NewStatsHandler(service *stats.Service, providersRepo domain.ProvidersRepository) *StatsHandler {
+	return &StatsHandler{
+		service: service,
+		policy:  authService.NewPolicy(providersRepo),
+	}
+}
+
+// Get returns the dashboard summary of the caller's provider for [from, to).
+// Both bounds accept a date or an RFC3339 timestamp; the default range is
+// the last 30 days.
+func (h *StatsHandler) Get(c *gin.Context) {
+	provider, ok := h.policy.RequireProvider(c)
+	if !ok {
+		return
+	}
+	providerId := provider.ID
+
+	now := utils.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := today.AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -30)
+
+	var err error
+
+	if s := c.Query("from"); s != "" {
+		if from, err = utils.ParseDateOrTime(s); err != nil {
+			apperrors.Abort(c, apperrors.InvalidQuery("invalid from date"))
+			return
+		}
+	}
+	if s := c.Query("to"); s != "" {
+		if to, err = utils.ParseDateOrTime(s); err != nil {
+			apperrors.Abort(c, apperrors.InvalidQuery("invalid to date"))
+			return
+		}
+	}
+	if !from.Before(to) {
+		apperrors.Abort(c, apperrors.InvalidQuery("from must be before to"))
+		return
+	}
+	if to.Sub(from) > maxRange {
+		apperrors.Abort(c, apperrors.InvalidQuery("date range cannot exceed one year"))
+		return
+	}
+
+	summary, err := h.service.Summary(c.Request.Context(), providerId, from, to)
+	if err != nil {
+		apperrors.Abort(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.JSON(http.StatusOK, summary)
+}
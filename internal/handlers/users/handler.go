@@ -1,11 +1,13 @@
 package users
 
 import (
+	"ServiceBookingApp/internal/apperrors"
+	authService "ServiceBookingApp/internal/auth"
 	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/requests"
 	"ServiceBookingApp/internal/utils"
 	"github.com/gin-gonic/gin"
 	"net/http"
-	"strconv"
 )
 
 type UsersHandler struct {
@@ -16,52 +18,51 @@ func NewUsersHandler(repo domain.UsersRepository) *UsersHandler {
 	return &UsersHandler{repo: repo}
 }
 
+// List returns the caller's own user. Users belong to different tenants, so
+// nobody gets to page through the others; the list shape is kept for
+// existing clients.
 func (h *UsersHandler) List(c *gin.Context) {
-	limit := 20
-	if l := c.Query("limit"); l != "" {
-		if val, err := strconv.Atoi(l); err == nil && val > 0 {
-			limit = val
-		}
-	}
-	page := 1
-	if p := c.Query("page"); p != "" {
-		if val, err := strconv.Atoi(p); err == nil && val > 0 {
-			page = val
-		}
+	uid := authService.CallerUID(c)
+	if uid == "" {
+		apperrors.Abort(c, apperrors.ErrUnauthorized)
+		return
 	}
-	offset := (page - 1) * limit
 
-	results, err := h.repo.List(c.Request.Context(), limit, offset)
+	result, err := h.repo.Get(c.Request.Context(), uid)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeUserNotFound, "user not found"))
 		return
 	}
-	c.JSON(http.StatusOK, results)
+	c.JSON(http.StatusOK, gin.H{"data": []*domain.Users{result}, "next_cursor": ""})
 }
 
 func (h *UsersHandler) Get(c *gin.Context) {
 	id := c.Param("id")
+	if !authService.AuthorizeUser(c, id) {
+		return
+	}
 	result, err := h.repo.Get(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeUserNotFound, "user not found"))
 		return
 	}
 	c.JSON(http.StatusOK, result)
 }
 
 func (h *UsersHandler) Create(c *gin.Context) {
-	var m domain.Users
-	if err := c.ShouldBindJSON(&m); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req requests.User
+	if err := requests.Bind(c, &req); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
+	m := req.ToDomain()
 	if m.IsActive == nil {
 		active := false
 		m.IsActive = &active
 	}
-	id, err := h.repo.Create(c.Request.Context(), &m)
+	id, err := h.repo.Create(c.Request.Context(), m)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 	m.ID = id
@@ -70,34 +71,25 @@ func (h *UsersHandler) Create(c *gin.Context) {
 
 func (h *UsersHandler) Update(c *gin.Context) {
 	id := c.Param("id")
+	if !authService.AuthorizeUser(c, id) {
+		return
+	}
 
 	existing, err := h.repo.Get(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeUserNotFound, "user not found"))
 		return
 	}
 
-	var updates domain.Users
-	if err := c.ShouldBindJSON(&updates); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req requests.User
+	if err := requests.Bind(c, &req); err != nil {
+		apperrors.Abort(c, err)
 		return
 	}
-
-	if updates.Name != "" {
-		existing.Name = updates.Name
-	}
-	if updates.Email != "" {
-		existing.Email = updates.Email
-	}
-	if updates.Picture != "" {
-		existing.Picture = updates.Picture
-	}
-	if updates.RoleId != "" {
-		existing.RoleId = updates.RoleId
-	}
+	req.Apply(existing)
 
 	if err := h.repo.Update(c.Request.Context(), id, existing); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 
@@ -106,10 +98,13 @@ func (h *UsersHandler) Update(c *gin.Context) {
 
 func (h *UsersHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
+	if !authService.AuthorizeUser(c, id) {
+		return
+	}
 
 	user, err := h.repo.Get(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		apperrors.Abort(c, apperrors.NotFound(err, apperrors.CodeUserNotFound, "user not found"))
 		return
 	}
 
@@ -117,7 +112,7 @@ func (h *UsersHandler) Delete(c *gin.Context) {
 	user.DeletedAt = &now
 
 	if err := h.repo.Update(c.Request.Context(), id, user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Abort(c, err)
 		return
 	}
 
@@ -8,63 +8,22 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"ServiceBookingApp/internal/apperrors"
 	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/memory"
+
+	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-type MockUsersRepository struct {
-	Data map[string]*domain.Users
-}
-
-func (m *MockUsersRepository) List(ctx context.Context, limit, offset int) ([]*domain.Users, error) {
-	var results []*domain.Users
-	for _, v := range m.Data {
-		results = append(results, v)
-	}
-	
-	if offset >= len(results) {
-		return []*domain.Users{}, nil
-	}
-	end := offset + limit
-	if end > len(results) {
-		end = len(results)
-	}
-	return results[offset:end], nil
-}
-
-func (m *MockUsersRepository) Get(ctx context.Context, id string) (*domain.Users, error) {
-	if val, ok := m.Data[id]; ok {
-		return val, nil
-	}
-	return nil, nil
-}
-
-func (m *MockUsersRepository) Create(ctx context.Context, model *domain.Users) (string, error) {
-	id := "test-id"
-	model.ID = id
-	if m.Data == nil {
-		m.Data = make(map[string]*domain.Users)
-	}
-	m.Data[id] = model
-	return id, nil
-}
-
-func (m *MockUsersRepository) Update(ctx context.Context, id string, model *domain.Users) error {
-	m.Data[id] = model
-	return nil
-}
-
-func (m *MockUsersRepository) Delete(ctx context.Context, id string) error {
-	delete(m.Data, id)
-	return nil
-}
-
 func TestUsersHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	repo := &MockUsersRepository{Data: make(map[string]*domain.Users)}
+	repo := memory.NewUsersRepository(memory.NewStore())
 	handler := NewUsersHandler(repo)
 	r := gin.Default()
+	r.Use(apperrors.Middleware())
 
 	r.GET("/users", handler.List)
 	r.POST("/users", handler.Create)
@@ -79,9 +38,34 @@ func TestUsersHandler(t *testing.T) {
 	})
 
 	t.Run("List", func(t *testing.T) {
+		ctx := context.Background()
+		aliceId, err := repo.Create(ctx, &domain.Users{Email: "alice@example.com"})
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, &domain.Users{Email: "bob@example.com"})
+		require.NoError(t, err)
+
+		r := gin.New()
+		r.Use(apperrors.Middleware())
+		r.Use(func(c *gin.Context) { c.Set("user", &auth.Token{UID: aliceId}) })
+		r.GET("/users", handler.List)
+
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/users?page=1&limit=10", nil)
 		r.ServeHTTP(w, req)
 		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Data []domain.Users `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Data, 1, "only the caller is listed")
+		assert.Equal(t, "alice@example.com", resp.Data[0].Email)
+	})
+
+	t.Run("List without caller", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
 }
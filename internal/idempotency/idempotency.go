@@ -0,0 +1,131 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"ServiceBookingApp/internal/apperrors"
+	"ServiceBookingApp/internal/auth"
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Header is the request header carrying the client's key.
+const Header = "Idempotency-Key"
+
+const (
+	// TTL is how long a response is replayed for its key.
+	TTL = 24 * time.Hour
+	// pendingTTL frees the key of a request that never finished, e.g.
+	// because the instance serving it died.
+	pendingTTL = time.Minute
+)
+
+// Middleware makes a route safe to retry. A request with an Idempotency-Key
+// runs once; retries with the same key and body get the stored response
+// back, with Idempotent-Replayed: true. Reusing a key with another body
+// answers 422, and retrying while the first request is still running
+// answers 409. Requests without the header are not affected.
+//
+// Only successful responses are stored. After an error the key is released,
+// so the client can fix the request or wait out a rate limit and retry with
+// the same key.
+//
+// Keys are scoped to the caller and the route, so clients cannot replay
+// each other's responses.
+func Middleware(store domain.IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(Header)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apperrors.Abort(c, apperrors.InvalidBody(err))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := utils.Now()
+		rec := &domain.IdempotencyRecord{
+			Key:         hash(auth.CallerUID(c), c.Request.Method, c.Request.URL.Path, key),
+			RequestHash: hash(string(body)),
+			ExpiresAt:   now.Add(pendingTTL),
+		}
+		existing, err := store.Start(c.Request.Context(), rec, now)
+		if err != nil {
+			apperrors.Abort(c, err)
+			return
+		}
+		if existing != nil {
+			switch {
+			case existing.RequestHash != rec.RequestHash:
+				apperrors.Abort(c, apperrors.New(http.StatusUnprocessableEntity, apperrors.CodeIdempotencyKeyReused, "Idempotency-Key was already used with a different request body"))
+			case !existing.Completed():
+				apperrors.Abort(c, apperrors.New(http.StatusConflict, apperrors.CodeIdempotencyInProgress, "a request with this Idempotency-Key is still in progress"))
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		w := &recorder{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		// The request may have been cancelled; the outcome still has to be
+		// recorded. Errors are only rendered once the chain unwinds, so an
+		// aborted request has not written its status yet.
+		ctx := context.WithoutCancel(c.Request.Context())
+		if status := w.Status(); len(c.Errors) > 0 || status < 200 || status >= 300 {
+			if err := store.Delete(ctx, rec.Key); err != nil {
+				log.Printf("idempotency: releasing key: %v", err)
+			}
+			return
+		}
+		rec.StatusCode = w.Status()
+		rec.ContentType = w.Header().Get("Content-Type")
+		rec.Body = w.body.Bytes()
+		rec.ExpiresAt = utils.Now().Add(TTL)
+		if err := store.Complete(ctx, rec); err != nil {
+			log.Printf("idempotency: storing response: %v", err)
+		}
+	}
+}
+
+func hash(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder keeps a copy of the response body.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *recorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,60 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ServiceBookingApp/internal/apperrors"
+	"ServiceBookingApp/internal/infrastructure/memory"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	created := 0
+	r := gin.New()
+	r.Use(apperrors.Middleware())
+	r.POST("/appointments", Middleware(memory.NewIdempotencyStore(memory.NewStore())), func(c *gin.Context) {
+		if strings.Contains(c.GetHeader("X-Test"), "fail") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad"})
+			return
+		}
+		created++
+		c.JSON(http.StatusCreated, gin.H{"n": created})
+	})
+
+	post := func(key, body, test string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/appointments", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(Header, key)
+		}
+		req.Header.Set("X-Test", test)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("k1", `{"a":1}`, "")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"n":1}`, w.Body.String())
+
+	w = post("k1", `{"a":1}`, "")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"n":1}`, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, post("k1", `{"a":2}`, "").Code)
+
+	// Errors are not stored, so the same key can be retried.
+	assert.Equal(t, http.StatusBadRequest, post("k2", `{"a":1}`, "fail").Code)
+	assert.Equal(t, http.StatusCreated, post("k2", `{"a":1}`, "").Code)
+
+	assert.Equal(t, http.StatusCreated, post("", `{"a":1}`, "").Code)
+	assert.Equal(t, 3, created)
+}
@@ -0,0 +1,272 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/richtext"
+	"ServiceBookingApp/internal/utils"
+)
+
+// Report is the outcome of an import. When DryRun is set, or when any row
+// failed, nothing has been written.
+type Report struct {
+	DryRun   bool       `json:"dry_run"`
+	Total    int        `json:"total"`
+	Valid    int        `json:"valid"`
+	Imported int        `json:"imported"`
+	Errors   []RowError `json:"errors"`
+}
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+var importableStatuses = map[string]bool{
+	domain.StatusCompleted: true,
+	domain.StatusCancelled: true,
+	domain.StatusNoShow:    true,
+	domain.StatusConfirmed: true,
+}
+
+type Importer struct {
+	servicesRepo     domain.ServicesRepository
+	appointmentsRepo domain.AppointmentsRepository
+}
+
+func NewImporter(servicesRepo domain.ServicesRepository, appointmentsRepo domain.AppointmentsRepository) *Importer {
+	return &Importer{
+		servicesRepo:     servicesRepo,
+		appointmentsRepo: appointmentsRepo,
+	}
+}
+
+// Services validates rows as services of providerId and, unless dryRun or a
+// row failed, writes them all in batches.
+func (im *Importer) Services(ctx context.Context, providerId string, rows []Row, dryRun bool) (*Report, error) {
+	report := &Report{DryRun: dryRun, Total: len(rows), Errors: []RowError{}}
+	seen := make(map[string]int)
+
+	var models []*domain.Services
+	for _, row := range rows {
+		m, errs := parseService(row, seen)
+		report.Errors = append(report.Errors, errs...)
+		if len(errs) > 0 {
+			continue
+		}
+		m.ProviderId = providerId
+		models = append(models, m)
+	}
+	report.Valid = len(models)
+
+	if dryRun || len(report.Errors) > 0 || len(models) == 0 {
+		return report, nil
+	}
+	// Files do not carry intake forms or catalog settings, so services
+	// imported again keep the ones set up in the dashboard.
+	existing, err := im.providerServices(ctx, providerId)
+	if err != nil {
+		return nil, err
+	}
+	byExternalId := make(map[string]*domain.Services, len(existing))
+	for _, s := range existing {
+		if s.ExternalId != "" {
+			byExternalId[s.ExternalId] = s
+		}
+	}
+	for _, m := range models {
+		if s, ok := byExternalId[m.ExternalId]; ok {
+			m.IntakeForm = s.IntakeForm
+			m.Category = s.Category
+			m.SortOrder = s.SortOrder
+			m.Private = s.Private
+			m.ActiveFrom = s.ActiveFrom
+			m.ActiveTo = s.ActiveTo
+		}
+	}
+	if err := im.servicesRepo.Import(ctx, models); err != nil {
+		return nil, err
+	}
+	report.Imported = len(models)
+	return report, nil
+}
+
+func parseService(row Row, seen map[string]int) (*domain.Services, []RowError) {
+	var errs []RowError
+	fail := func(field, format string, args ...interface{}) {
+		errs = append(errs, RowError{Row: row.Line, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	m := &domain.Services{
+		ExternalId: row.Get("external_id"),
+		Title:      row.Get("title"),
+		Color:      row.Get("color"),
+		IconUrl:    row.Get("icon_url"),
+	}
+	if d := row.Get("description"); d != "" {
+		m.Description = richtext.NewDescription(d)
+	}
+
+	checkExternalId(row, m.ExternalId, seen, fail)
+	if m.Title == "" {
+		fail("title", "is required")
+	}
+	if m.Color != "" && !colorPattern.MatchString(m.Color) {
+		fail("color", "must be a hex color like #aabbcc")
+	}
+
+	dur, err := strconv.Atoi(row.Get("duration_minutes"))
+	if err != nil || dur <= 0 || dur > 24*60 {
+		fail("duration_minutes", "must be a whole number of minutes between 1 and 1440")
+	}
+	m.DurationMinutes = dur
+
+	if p := row.Get("price"); p != "" {
+		price, err := parseDecimal(p)
+		if err != nil || price < 0 {
+			fail("price", "must be a non-negative number")
+		}
+		m.Price = price
+	}
+
+	return m, errs
+}
+
+// Appointments validates rows as past appointments of providerId. Each row
+// references its service by service_id or by the service_external_id used
+// in a previous services import.
+func (im *Importer) Appointments(ctx context.Context, providerId string, rows []Row, dryRun bool) (*Report, error) {
+	report := &Report{DryRun: dryRun, Total: len(rows), Errors: []RowError{}}
+
+	services, err := im.providerServices(ctx, providerId)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*domain.Services, len(services))
+	byExternalID := make(map[string]*domain.Services, len(services))
+	for _, s := range services {
+		byID[s.ID] = s
+		if s.ExternalId != "" {
+			byExternalID[s.ExternalId] = s
+		}
+	}
+
+	now := utils.Now()
+	seen := make(map[string]int)
+
+	var models []*domain.Appointments
+	for _, row := range rows {
+		var errs []RowError
+		fail := func(field, format string, args ...interface{}) {
+			errs = append(errs, RowError{Row: row.Line, Field: field, Message: fmt.Sprintf(format, args...)})
+		}
+
+		m := &domain.Appointments{
+			ProviderId:    providerId,
+			ExternalId:    row.Get("external_id"),
+			Status:        strings.ToLower(row.Get("status")),
+			CustomerName:  row.Get("customer_name"),
+			CustomerEmail: strings.ToLower(row.Get("customer_email")),
+			CustomerPhone: row.Get("customer_phone"),
+		}
+		m.Notes.Text = row.Get("notes")
+
+		checkExternalId(row, m.ExternalId, seen, fail)
+
+		var service *domain.Services
+		if id := row.Get("service_id"); id != "" {
+			service = byID[id]
+		} else if ext := row.Get("service_external_id"); ext != "" {
+			service = byExternalID[ext]
+		}
+		if service == nil {
+			fail("service_id", "must reference an existing service by service_id or service_external_id")
+		} else {
+			m.ServiceId = service.ID
+			m.ServiceName = service.Title
+			m.DurationMinutes = service.DurationMinutes
+			m.Price = service.Price
+		}
+
+		if d := row.Get("duration_minutes"); d != "" {
+			dur, err := strconv.Atoi(d)
+			if err != nil || dur <= 0 || dur > 24*60 {
+				fail("duration_minutes", "must be a whole number of minutes between 1 and 1440")
+			}
+			m.DurationMinutes = dur
+		}
+
+		scheduledAt, err := utils.ParseDateOrTime(row.Get("scheduled_at"))
+		if err != nil {
+			fail("scheduled_at", "must be a date or an RFC3339 timestamp")
+		} else if !scheduledAt.Before(now) {
+			fail("scheduled_at", "only past appointments can be imported")
+		}
+		m.ScheduledAt = scheduledAt
+
+		if m.Status == "" {
+			m.Status = domain.StatusCompleted
+		}
+		if !importableStatuses[m.Status] {
+			fail("status", "must be one of completed, cancelled, no_show or confirmed")
+		}
+		if m.Status == domain.StatusCancelled {
+			deletedAt := scheduledAt
+			m.DeletedAt = &deletedAt
+		}
+
+		report.Errors = append(report.Errors, errs...)
+		if len(errs) == 0 {
+			models = append(models, m)
+		}
+	}
+	report.Valid = len(models)
+
+	if dryRun || len(report.Errors) > 0 || len(models) == 0 {
+		return report, nil
+	}
+	if err := im.appointmentsRepo.Import(ctx, models); err != nil {
+		return nil, err
+	}
+	report.Imported = len(models)
+	return report, nil
+}
+
+func (im *Importer) providerServices(ctx context.Context, providerId string) ([]*domain.Services, error) {
+	opts := domain.ListOptions{Limit: domain.MaxPageSize}
+	var results []*domain.Services
+	for {
+		page, nextCursor, err := im.servicesRepo.List(ctx, opts, providerId)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, page...)
+		if nextCursor == "" {
+			return results, nil
+		}
+		opts.Cursor = nextCursor
+	}
+}
+
+func checkExternalId(row Row, externalId string, seen map[string]int, fail func(field, format string, args ...interface{})) {
+	if externalId == "" {
+		fail("external_id", "is required")
+		return
+	}
+	if first, dup := seen[externalId]; dup {
+		fail("external_id", "duplicates row %d", first)
+		return
+	}
+	seen[externalId] = row.Line
+}
+
+// parseDecimal accepts both "1234.50" and the es-AR "1.234,50".
+func parseDecimal(s string) (float64, error) {
+	if strings.Contains(s, ",") {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.ReplaceAll(s, ",", ".")
+	}
+	return strconv.ParseFloat(s, 64)
+}
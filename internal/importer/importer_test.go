@@ -0,0 +1,58 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRowsCSVAndJSON(t *testing.T) {
+	csvRows, err := ReadRows("text/csv", strings.NewReader("External_ID,title,price\nsvc-1,Corte,\"1.500,50\"\n"))
+	assert.NoError(t, err)
+	assert.Len(t, csvRows, 1)
+	assert.Equal(t, 2, csvRows[0].Line)
+	assert.Equal(t, "svc-1", csvRows[0].Get("external_id"))
+	assert.Equal(t, "1.500,50", csvRows[0].Get("price"))
+
+	jsonRows, err := ReadRows("application/json", strings.NewReader(`[{"external_id":"svc-1","price":1500.5,"duration_minutes":30}]`))
+	assert.NoError(t, err)
+	assert.Len(t, jsonRows, 1)
+	assert.Equal(t, 1, jsonRows[0].Line)
+	assert.Equal(t, "1500.5", jsonRows[0].Get("price"))
+	assert.Equal(t, "30", jsonRows[0].Get("duration_minutes"))
+}
+
+func TestParseServiceReportsEveryFieldError(t *testing.T) {
+	seen := map[string]int{"dup": 2}
+	row := Row{Line: 3, Fields: map[string]string{
+		"external_id":      "dup",
+		"color":            "red",
+		"duration_minutes": "0",
+		"price":            "-1",
+	}}
+
+	_, errs := parseService(row, seen)
+
+	fields := make([]string, 0, len(errs))
+	for _, e := range errs {
+		assert.Equal(t, 3, e.Row)
+		fields = append(fields, e.Field)
+	}
+	assert.ElementsMatch(t, []string{"external_id", "title", "color", "duration_minutes", "price"}, fields)
+}
+
+func TestParseServiceValid(t *testing.T) {
+	row := Row{Line: 2, Fields: map[string]string{
+		"external_id":      "svc-1",
+		"title":            "Corte",
+		"duration_minutes": "45",
+		"price":            "1.234,50",
+		"color":            "#AA00ff",
+	}}
+
+	m, errs := parseService(row, map[string]int{})
+	assert.Empty(t, errs)
+	assert.Equal(t, 45, m.DurationMinutes)
+	assert.Equal(t, 1234.5, m.Price)
+}
@@ -0,0 +1,118 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MaxRows caps the size of a single import request.
+const MaxRows = 5000
+
+var ErrTooManyRows = fmt.Errorf("imports are limited to %d rows", MaxRows)
+
+// Row is one input record with lower-cased column names. Line is the
+// 1-based position reported back in errors: the CSV line, or the array index
+// plus one for JSON.
+type Row struct {
+	Line   int
+	Fields map[string]string
+}
+
+func (r Row) Get(key string) string {
+	return strings.TrimSpace(r.Fields[key])
+}
+
+// RowError describes why a row was rejected.
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ReadRows parses a CSV document with a header line, or a JSON array of
+// flat objects when contentType is JSON.
+func ReadRows(contentType string, r io.Reader) ([]Row, error) {
+	if strings.Contains(contentType, "json") {
+		return readJSON(r)
+	}
+	return readCSV(r)
+}
+
+func readCSV(r io.Reader) ([]Row, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, h := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+	}
+
+	var rows []Row
+	line := 1
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line++
+		if len(rows) == MaxRows {
+			return nil, ErrTooManyRows
+		}
+		fields := make(map[string]string, len(header))
+		for i, v := range record {
+			if i < len(header) {
+				fields[header[i]] = v
+			}
+		}
+		rows = append(rows, Row{Line: line, Fields: fields})
+	}
+}
+
+func readJSON(r io.Reader) ([]Row, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var records []map[string]interface{}
+	if err := dec.Decode(&records); err != nil {
+		return nil, errors.New("body must be a JSON array of objects")
+	}
+	if len(records) > MaxRows {
+		return nil, ErrTooManyRows
+	}
+
+	rows := make([]Row, 0, len(records))
+	for i, rec := range records {
+		fields := make(map[string]string, len(rec))
+		for k, v := range rec {
+			switch val := v.(type) {
+			case nil:
+			case string:
+				fields[strings.ToLower(k)] = val
+			case json.Number:
+				fields[strings.ToLower(k)] = val.String()
+			case bool:
+				fields[strings.ToLower(k)] = fmt.Sprint(val)
+			default:
+				encoded, _ := json.Marshal(val)
+				fields[strings.ToLower(k)] = string(bytes.TrimSpace(encoded))
+			}
+		}
+		rows = append(rows, Row{Line: i + 1, Fields: fields})
+	}
+	return rows, nil
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
 	"ServiceBookingApp/internal/utils"
 
 	"cloud.google.com/go/firestore"
@@ -46,53 +48,112 @@ func (r *AppointmentsRepository) ListByDate(ctx context.Context, date time.Time,
 		if err := doc.DataTo(&m); err != nil {
 			return nil, err
 		}
+		if m.DeletedAt != nil {
+			continue
+		}
 		m.ID = doc.Ref.ID
 		results = append(results, &m)
 	}
 	return results, nil
 }
 
-func (r *AppointmentsRepository) List(ctx context.Context, limit, offset int, filterType string, providerId string) ([]*domain.Appointments, error) {
+type equality struct {
+	field string
+	value string
+}
+
+// appointmentEqualities lists the equality filters List may combine with
+// its ScheduledAt range and ordering. CompositeIndexes derives the indexes
+// from it, so a new filter only needs to be added here.
+func appointmentEqualities(filter domain.AppointmentsFilter) []equality {
+	return []equality{
+		{"ProviderId", filter.ProviderId},
+		{"Status", filter.Status},
+		{"ServiceId", filter.ServiceId},
+		{"CustomerEmail", filter.Customer},
+	}
+}
+
+func (r *AppointmentsRepository) List(ctx context.Context, filter domain.AppointmentsFilter) ([]*domain.Appointments, string, error) {
+	after, err := pagination.Decode(filter.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
 	query := r.client.client.Collection("appointments").Query
 	now := utils.Now()
 
-	if providerId != "" {
-		query = query.Where("ProviderId", "==", providerId)
+	for _, eq := range appointmentEqualities(filter) {
+		if eq.value != "" {
+			query = query.Where(eq.field, "==", eq.value)
+		}
+	}
+
+	direction := firestore.Desc
+	if filter.Type == "upcoming" {
+		query = query.Where("ScheduledAt", ">=", now)
+		direction = firestore.Asc
+	} else if filter.Type == "past" {
+		query = query.Where("ScheduledAt", "<", now)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("ScheduledAt", ">=", filter.From)
 	}
+	if !filter.To.IsZero() {
+		query = query.Where("ScheduledAt", "<", filter.To)
+	}
+
+	query = query.OrderBy("ScheduledAt", direction).OrderBy(firestore.DocumentID, direction)
 
-	if filterType == "upcoming" {
-		query = query.Where("ScheduledAt", ">=", now).OrderBy("ScheduledAt", firestore.Asc)
-	} else if filterType == "past" {
-		query = query.Where("ScheduledAt", "<", now).OrderBy("ScheduledAt", firestore.Desc)
-	} else {
-		query = query.OrderBy("ScheduledAt", firestore.Desc)
+	if after != nil {
+		if after.ScheduledAt == nil {
+			return nil, "", domain.ErrInvalidCursor
+		}
+		query = query.StartAfter(*after.ScheduledAt, after.ID)
 	}
 
-	iter := query.Offset(offset).Limit(limit).Documents(ctx)
+	limit := filter.PageSize()
 	var results []*domain.Appointments
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
+	err = readPage(ctx, query, limit, func(doc *firestore.DocumentSnapshot) (bool, error) {
 		var m domain.Appointments
 		if err := doc.DataTo(&m); err != nil {
-			return nil, err
+			return false, err
+		}
+		if m.DeletedAt != nil && !filter.IncludeDeleted {
+			return false, nil
 		}
 		m.ID = doc.Ref.ID
 		results = append(results, &m)
+		return true, nil
+	})
+	if err != nil {
+		return nil, "", err
 	}
-	return results, nil
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	last := results[limit-1]
+	return results, pagination.Encode(pagination.Cursor{ScheduledAt: &last.ScheduledAt, ID: last.ID}), nil
 }
 
 func (r *AppointmentsRepository) Get(ctx context.Context, id string) (*domain.Appointments, error) {
-	doc, err := r.client.client.Collection("appointments").Doc(id).Get(ctx)
+	m, err := r.GetIncludingDeleted(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if m.DeletedAt != nil {
+		return nil, domain.ErrNotFound
+	}
+	return m, nil
+}
+
+func (r *AppointmentsRepository) GetIncludingDeleted(ctx context.Context, id string) (*domain.Appointments, error) {
+	doc, err := r.client.client.Collection("appointments").Doc(id).Get(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
 	var m domain.Appointments
 	if err := doc.DataTo(&m); err != nil {
 		return nil, err
@@ -105,8 +166,8 @@ func (r *AppointmentsRepository) Create(ctx context.Context, model *domain.Appoi
 	now := utils.Now()
 	model.CreatedAt = now
 	model.UpdatedAt = now
-	ref, _, err := r.client.client.Collection("appointments").Add(ctx, model)
-	if err != nil {
+	ref := r.client.client.Collection("appointments").NewDoc()
+	if err := r.save(ctx, ref, model); err != nil {
 		return "", err
 	}
 	return ref.ID, nil
@@ -114,11 +175,71 @@ func (r *AppointmentsRepository) Create(ctx context.Context, model *domain.Appoi
 
 func (r *AppointmentsRepository) Update(ctx context.Context, id string, m *domain.Appointments) error {
 	m.UpdatedAt = utils.Now()
-	_, err := r.client.client.Collection("appointments").Doc(id).Set(ctx, m)
-	return err
+	return r.save(ctx, r.client.client.Collection("appointments").Doc(id), m)
+}
+
+// save sets the document at ref to m in a transaction that locks the
+// provider and rejects the write with domain.ErrSlotTaken when a live
+// appointment overlaps it.
+func (r *AppointmentsRepository) save(ctx context.Context, ref *firestore.DocumentRef, m *domain.Appointments) error {
+	client := r.client.client
+	return client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := lockProvider(tx, client, m.ProviderId); err != nil {
+			return err
+		}
+		if m.DeletedAt == nil && m.Status != domain.StatusCancelled {
+			end := m.ScheduledAt.Add(time.Duration(m.DurationMinutes) * time.Minute)
+			docs, err := tx.Documents(client.Collection("appointments").
+				Where("ProviderId", "==", m.ProviderId).
+				Where("ScheduledAt", ">", m.ScheduledAt.Add(-maxAppointmentDuration)).
+				Where("ScheduledAt", "<", end)).GetAll()
+			if err != nil {
+				return err
+			}
+			for _, doc := range docs {
+				var other domain.Appointments
+				if err := doc.DataTo(&other); err != nil {
+					return err
+				}
+				if doc.Ref.ID == ref.ID || other.DeletedAt != nil || other.Status == domain.StatusCancelled {
+					continue
+				}
+				if overlaps(m.ScheduledAt, m.DurationMinutes, other.ScheduledAt, other.DurationMinutes) {
+					return domain.ErrSlotTaken
+				}
+			}
+		}
+		return tx.Set(ref, m)
+	})
 }
 
 func (r *AppointmentsRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.client.client.Collection("appointments").Doc(id).Delete(ctx)
 	return err
 }
+
+func (r *AppointmentsRepository) Import(ctx context.Context, models []*domain.Appointments) error {
+	now := utils.Now()
+	ids := make([]string, len(models))
+	docs := make([]interface{}, len(models))
+	for i, m := range models {
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = now
+		}
+		m.UpdatedAt = now
+		m.ID = docid.Import(m.ProviderId, m.ExternalId)
+		ids[i] = m.ID
+		docs[i] = m
+	}
+	return batchSet(ctx, r.client.client, "appointments", ids, docs)
+}
+
+// Purge hard-deletes the appointments soft-deleted before deletedBefore,
+// except cancelled ones: their DeletedAt marks the cancellation, which stats
+// and exports still report.
+func (r *AppointmentsRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	query := r.client.client.Collection("appointments").Where("DeletedAt", "<", deletedBefore)
+	return deleteWhere(ctx, r.client.client, query, func(doc *firestore.DocumentSnapshot) bool {
+		return doc.Data()["Status"] == domain.StatusCancelled
+	})
+}
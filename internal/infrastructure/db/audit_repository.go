@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"ServiceBookingApp/internal/utils"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+)
+
+type AuditRepository struct {
+	client *FirestoreRepository
+}
+
+func NewAuditRepository(client *FirestoreRepository) *AuditRepository {
+	return &AuditRepository{client: client}
+}
+
+// auditEqualities lists the equality filters List may combine with its
+// CreatedAt range and ordering; see appointmentEqualities.
+func auditEqualities(filter domain.AuditFilter) []equality {
+	return []equality{
+		{"ProviderId", filter.ProviderId},
+		{"EntityType", filter.EntityType},
+		{"EntityId", filter.EntityId},
+		{"ActorUID", filter.ActorUID},
+	}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, entry *domain.AuditEntry) (string, error) {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = utils.Now()
+	}
+	ref, _, err := r.client.client.Collection("audit").Add(ctx, entry)
+	if err != nil {
+		return "", err
+	}
+	return ref.ID, nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, string, error) {
+	after, err := pagination.Decode(filter.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := r.client.client.Collection("audit").Query
+	for _, eq := range auditEqualities(filter) {
+		if eq.value != "" {
+			query = query.Where(eq.field, "==", eq.value)
+		}
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("CreatedAt", ">=", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("CreatedAt", "<", filter.To)
+	}
+	query = query.OrderBy("CreatedAt", firestore.Desc).OrderBy(firestore.DocumentID, firestore.Desc)
+
+	if after != nil {
+		if after.ScheduledAt == nil {
+			return nil, "", domain.ErrInvalidCursor
+		}
+		query = query.StartAfter(*after.ScheduledAt, after.ID)
+	}
+
+	limit := filter.PageSize()
+	iter := query.Limit(limit + 1).Documents(ctx)
+	defer iter.Stop()
+	var results []*domain.AuditEntry
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		var m domain.AuditEntry
+		if err := doc.DataTo(&m); err != nil {
+			return nil, "", err
+		}
+		m.ID = doc.Ref.ID
+		results = append(results, &m)
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	last := results[limit-1]
+	return results, pagination.Encode(pagination.Cursor{ScheduledAt: &last.CreatedAt, ID: last.ID}), nil
+}
+
+func (r *AuditRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	return deleteWhere(ctx, r.client.client, r.client.client.Collection("audit").Where("CreatedAt", "<", before), nil)
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxAppointmentDuration bounds how long before a slot an overlapping
+// booking can start. Firestore queries take a range on one field only, so
+// overlap checks query ScheduledAt from that far back and compare the ends
+// in code.
+const maxAppointmentDuration = 24 * time.Hour
+
+// lockProvider reads and bumps the provider's document in "booking_locks"
+// within tx. Transactions that book for the same provider then contend on
+// it and are retried one after the other, so two of them cannot both find
+// the same slot free.
+func lockProvider(tx *firestore.Transaction, client *firestore.Client, providerId string) error {
+	ref := client.Collection("booking_locks").Doc(providerId)
+	if _, err := tx.Get(ref); err != nil && status.Code(err) != codes.NotFound {
+		return err
+	}
+	return tx.Set(ref, map[string]interface{}{"Version": firestore.Increment(1)}, firestore.MergeAll)
+}
+
+// overlaps reports whether [start, start+minutes) and [otherStart,
+// otherStart+otherMinutes) intersect.
+func overlaps(start time.Time, minutes int, otherStart time.Time, otherMinutes int) bool {
+	end := start.Add(time.Duration(minutes) * time.Minute)
+	otherEnd := otherStart.Add(time.Duration(otherMinutes) * time.Minute)
+	return start.Before(otherEnd) && end.After(otherStart)
+}
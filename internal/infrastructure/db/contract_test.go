@@ -0,0 +1,26 @@
+package db
+
+import (
+	"testing"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/domain/repotest"
+)
+
+// TestRepositoryContract runs the shared repository suite against the
+// Firestore emulator; see newEmulatorRepository.
+func TestRepositoryContract(t *testing.T) {
+	repo := newEmulatorRepository(t)
+
+	repotest.Run(t, repotest.Factory{
+		Appointments: func(t *testing.T) domain.AppointmentsRepository { return NewAppointmentsRepository(repo) },
+		Services:     func(t *testing.T) domain.ServicesRepository { return NewServicesRepository(repo) },
+		Providers:    func(t *testing.T) domain.ProvidersRepository { return NewProvidersRepository(repo) },
+		Schedules:    func(t *testing.T) domain.SchedulesRepository { return NewSchedulesRepository(repo) },
+		Users:        func(t *testing.T) domain.UsersRepository { return NewUsersRepository(repo) },
+		Audit:        func(t *testing.T) domain.AuditRepository { return NewAuditRepository(repo) },
+		Holds:        func(t *testing.T) domain.HoldsRepository { return NewHoldsRepository(repo) },
+		Idempotency:  func(t *testing.T) domain.IdempotencyStore { return NewIdempotencyStore(repo) },
+		RateLimits:   func(t *testing.T) domain.RateLimitStore { return NewRateLimitStore(repo) },
+	})
+}
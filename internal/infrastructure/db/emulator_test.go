@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newEmulatorRepository connects to the Firestore emulator through
+// NewFirestoreRepository, the same way the API does, in a project of its own
+// so tests never see each other's data. It skips the test unless
+// FIRESTORE_EMULATOR_HOST is set, e.g.
+// gcloud emulators firestore start --host-port=localhost:8081
+// FIRESTORE_EMULATOR_HOST=localhost:8081 go test ./internal/infrastructure/db/
+func newEmulatorRepository(t *testing.T) *FirestoreRepository {
+	t.Helper()
+	host := os.Getenv("FIRESTORE_EMULATOR_HOST")
+	if host == "" {
+		t.Skip("FIRESTORE_EMULATOR_HOST not set")
+	}
+
+	projectID := fmt.Sprintf("demo-test-%d", time.Now().UnixNano())
+	t.Setenv("FIREBASE_PROJECT_ID", projectID)
+
+	base, err := NewFirestoreRepository()
+	require.NoError(t, err)
+	repo := base.(*FirestoreRepository)
+
+	t.Cleanup(func() {
+		repo.Close()
+		clearEmulatorProject(t, host, projectID)
+	})
+	return repo
+}
+
+// clearEmulatorProject drops every document of projectID through the
+// emulator's REST endpoint, which is not part of the client library.
+func clearEmulatorProject(t *testing.T, host, projectID string) {
+	url := fmt.Sprintf("http://%s/emulator/v1/projects/%s/databases/(default)/documents", host, projectID)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodDelete, url, nil)
+	if err != nil {
+		t.Logf("clearing emulator project: %v", err)
+		return
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Logf("clearing emulator project: %v", err)
+		return
+	}
+	resp.Body.Close()
+}
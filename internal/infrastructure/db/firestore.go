@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"ServiceBookingApp/internal/config"
 	"cloud.google.com/go/firestore"
@@ -27,6 +28,10 @@ type FirestoreRepository struct {
 	client *firestore.Client
 }
 
+// emulatorProjectID is used against the emulator when no project is
+// configured. Projects prefixed with "demo-" never reach production.
+const emulatorProjectID = "demo-servicebooking"
+
 // NewFirestoreRepository initializes the Firestore client and returns a Repository
 func NewFirestoreRepository() (Repository, error) {
 	ctx := context.Background()
@@ -35,6 +40,15 @@ func NewFirestoreRepository() (Repository, error) {
 	opt := option.WithCredentialsFile("firebaseCredentials.json")
 
 	projectID := config.GetFirebaseProjectID()
+
+	// The emulator accepts any project and no credentials, so local runs and
+	// tests do not need firebaseCredentials.json.
+	if os.Getenv("FIRESTORE_EMULATOR_HOST") != "" {
+		opt = option.WithoutAuthentication()
+		if projectID == "" {
+			projectID = emulatorProjectID
+		}
+	}
 	conf := &firebase.Config{ProjectID: projectID}
 
 	app, err := firebase.NewApp(ctx, conf, opt)
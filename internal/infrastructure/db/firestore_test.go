@@ -0,0 +1,171 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFirestoreRepository(t *testing.T) {
+	repo := newEmulatorRepository(t)
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, "things", map[string]interface{}{"name": "a", "count": int64(1)})
+	require.NoError(t, err)
+
+	got, err := repo.Get(ctx, "things", id)
+	require.NoError(t, err)
+	assert.Equal(t, id, got["id"])
+	assert.Equal(t, "a", got["name"])
+
+	require.NoError(t, repo.Update(ctx, "things", id, map[string]interface{}{"name": "b"}))
+	got, err = repo.Get(ctx, "things", id)
+	require.NoError(t, err)
+	assert.Equal(t, "b", got["name"])
+	assert.Equal(t, int64(1), got["count"], "Update merges")
+
+	all, err := repo.List(ctx, "things")
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	require.NoError(t, repo.Delete(ctx, "things", id))
+	_, err = repo.Get(ctx, "things", id)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// TestAppointmentsListQueries runs every combination of filters that
+// AppointmentsRepository.List turns into a composite query, paging one
+// document at a time so the cursor is part of the query too. The emulator
+// does not enforce firestore.indexes.json, so this checks the results, not
+// that the indexes are deployed.
+func TestAppointmentsListQueries(t *testing.T) {
+	repo := NewAppointmentsRepository(newEmulatorRepository(t))
+	ctx := context.Background()
+
+	providerId := "prov-queries"
+	base := utils.Now().Truncate(time.Minute)
+	var past, upcoming []string
+	for i, offset := range []time.Duration{-48 * time.Hour, -24 * time.Hour, 24 * time.Hour, 48 * time.Hour} {
+		m := &domain.Appointments{
+			ProviderId:      providerId,
+			ServiceId:       "svc-1",
+			CustomerEmail:   "ana@example.com",
+			ScheduledAt:     base.Add(offset),
+			DurationMinutes: 30,
+			Status:          domain.StatusConfirmed,
+		}
+		if i%2 == 1 {
+			m.ServiceId = "svc-2"
+			m.CustomerEmail = "juan@example.com"
+			m.Status = domain.StatusCompleted
+		}
+		id, err := repo.Create(ctx, m)
+		require.NoError(t, err)
+		if offset < 0 {
+			past = append(past, id)
+		} else {
+			upcoming = append(upcoming, id)
+		}
+	}
+	other, err := repo.Create(ctx, &domain.Appointments{ProviderId: "prov-other", ServiceId: "svc-1", ScheduledAt: base, Status: domain.StatusConfirmed})
+	require.NoError(t, err)
+
+	list := func(filter domain.AppointmentsFilter) []string {
+		t.Helper()
+		filter.Limit = 1
+		var ids []string
+		for {
+			page, next, err := repo.List(ctx, filter)
+			require.NoError(t, err)
+			for _, m := range page {
+				ids = append(ids, m.ID)
+			}
+			if next == "" {
+				return ids
+			}
+			filter.Cursor = next
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter domain.AppointmentsFilter
+		want   []string
+	}{
+		{"all providers", domain.AppointmentsFilter{}, append([]string{upcoming[1], upcoming[0], other}, past[1], past[0])},
+		{"provider", domain.AppointmentsFilter{ProviderId: providerId}, []string{upcoming[1], upcoming[0], past[1], past[0]}},
+		{"provider upcoming", domain.AppointmentsFilter{ProviderId: providerId, Type: "upcoming"}, upcoming},
+		{"provider past", domain.AppointmentsFilter{ProviderId: providerId, Type: "past"}, []string{past[1], past[0]}},
+		{"provider status", domain.AppointmentsFilter{ProviderId: providerId, Status: domain.StatusConfirmed}, []string{upcoming[0], past[0]}},
+		{"provider status upcoming", domain.AppointmentsFilter{ProviderId: providerId, Status: domain.StatusCompleted, Type: "upcoming"}, []string{upcoming[1]}},
+		{"provider service past", domain.AppointmentsFilter{ProviderId: providerId, ServiceId: "svc-1", Type: "past"}, []string{past[0]}},
+		{"provider customer", domain.AppointmentsFilter{ProviderId: providerId, Customer: "juan@example.com"}, []string{upcoming[1], past[1]}},
+		{"provider range", domain.AppointmentsFilter{ProviderId: providerId, From: base.Add(-30 * time.Hour), To: base.Add(30 * time.Hour)}, []string{upcoming[0], past[1]}},
+		{"provider status range", domain.AppointmentsFilter{ProviderId: providerId, Status: domain.StatusConfirmed, From: base.Add(-72 * time.Hour), To: base}, []string{past[0]}},
+		{"provider upcoming range", domain.AppointmentsFilter{ProviderId: providerId, Type: "upcoming", To: base.Add(30 * time.Hour)}, []string{upcoming[0]}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, list(tc.filter))
+		})
+	}
+}
+
+func TestImportSplitsBatches(t *testing.T) {
+	repo := NewServicesRepository(newEmulatorRepository(t))
+	ctx := context.Background()
+
+	models := make([]*domain.Services, maxBatchWrites+1)
+	for i := range models {
+		models[i] = &domain.Services{ProviderId: "prov-import", ExternalId: fmt.Sprintf("ext-%d", i), Title: "Corte"}
+	}
+	require.NoError(t, repo.Import(ctx, models))
+
+	count := func() int {
+		t.Helper()
+		n := 0
+		filter := domain.ListOptions{Limit: domain.MaxPageSize}
+		for {
+			page, next, err := repo.List(ctx, filter, "prov-import")
+			require.NoError(t, err)
+			n += len(page)
+			if next == "" {
+				return n
+			}
+			filter.Cursor = next
+		}
+	}
+	assert.Equal(t, len(models), count())
+
+	require.NoError(t, repo.Import(ctx, models))
+	assert.Equal(t, len(models), count(), "re-importing overwrites")
+}
+
+func TestPurgeSplitsBatches(t *testing.T) {
+	client := newEmulatorRepository(t)
+	repo := NewServicesRepository(client)
+	ctx := context.Background()
+
+	deletedAt := utils.Now().Add(-time.Hour)
+	models := make([]*domain.Services, maxBatchWrites+1)
+	for i := range models {
+		models[i] = &domain.Services{ProviderId: "prov-purge", ExternalId: fmt.Sprintf("ext-%d", i), DeletedAt: &deletedAt}
+	}
+	require.NoError(t, repo.Import(ctx, models))
+
+	n, err := repo.Purge(ctx, utils.Now())
+	require.NoError(t, err)
+	assert.Equal(t, len(models), n)
+
+	page, _, err := repo.List(ctx, domain.ListOptions{IncludeDeleted: true}, "prov-purge")
+	require.NoError(t, err)
+	assert.Empty(t, page)
+}
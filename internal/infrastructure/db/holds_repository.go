@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+)
+
+type HoldsRepository struct {
+	client *FirestoreRepository
+}
+
+func NewHoldsRepository(client *FirestoreRepository) *HoldsRepository {
+	return &HoldsRepository{client: client}
+}
+
+// Create locks the provider like AppointmentsRepository.save, so the check
+// for a live overlapping hold and the write happen in one transaction.
+func (r *HoldsRepository) Create(ctx context.Context, hold *domain.Hold) (string, error) {
+	hold.CreatedAt = utils.Now()
+	client := r.client.client
+	ref := client.Collection("holds").NewDoc()
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := lockProvider(tx, client, hold.ProviderId); err != nil {
+			return err
+		}
+		end := hold.ScheduledAt.Add(time.Duration(hold.DurationMinutes) * time.Minute)
+		docs, err := tx.Documents(client.Collection("holds").
+			Where("ProviderId", "==", hold.ProviderId).
+			Where("ScheduledAt", ">", hold.ScheduledAt.Add(-maxAppointmentDuration)).
+			Where("ScheduledAt", "<", end)).GetAll()
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			var other domain.Hold
+			if err := doc.DataTo(&other); err != nil {
+				return err
+			}
+			if !other.Expired(hold.CreatedAt) && overlaps(hold.ScheduledAt, hold.DurationMinutes, other.ScheduledAt, other.DurationMinutes) {
+				return domain.ErrSlotTaken
+			}
+		}
+		return tx.Create(ref, hold)
+	})
+	if err != nil {
+		return "", err
+	}
+	return ref.ID, nil
+}
+
+func (r *HoldsRepository) Get(ctx context.Context, id string) (*domain.Hold, error) {
+	doc, err := r.client.client.Collection("holds").Doc(id).Get(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	var m domain.Hold
+	if err := doc.DataTo(&m); err != nil {
+		return nil, err
+	}
+	m.ID = doc.Ref.ID
+	return &m, nil
+}
+
+// ListByDate filters out expired holds after the query, since Firestore
+// allows a range on only one field.
+func (r *HoldsRepository) ListByDate(ctx context.Context, date time.Time, providerId string, now time.Time) ([]*domain.Hold, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	iter := r.client.client.Collection("holds").
+		Where("ProviderId", "==", providerId).
+		Where("ScheduledAt", ">=", startOfDay).
+		Where("ScheduledAt", "<", endOfDay).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var results []*domain.Hold
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var m domain.Hold
+		if err := doc.DataTo(&m); err != nil {
+			return nil, err
+		}
+		if m.Expired(now) {
+			continue
+		}
+		m.ID = doc.Ref.ID
+		results = append(results, &m)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ScheduledAt.Before(results[j].ScheduledAt)
+	})
+	return results, nil
+}
+
+func (r *HoldsRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.client.client.Collection("holds").Doc(id).Delete(ctx)
+	return err
+}
+
+func (r *HoldsRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	return deleteWhere(ctx, r.client.client, r.client.client.Collection("holds").Where("ExpiresAt", "<", before), nil)
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IdempotencyStore keeps one document per key in "idempotency_keys".
+type IdempotencyStore struct {
+	client *FirestoreRepository
+}
+
+func NewIdempotencyStore(client *FirestoreRepository) *IdempotencyStore {
+	return &IdempotencyStore{client: client}
+}
+
+func (r *IdempotencyStore) doc(key string) *firestore.DocumentRef {
+	return r.client.client.Collection("idempotency_keys").Doc(key)
+}
+
+func (r *IdempotencyStore) Start(ctx context.Context, rec *domain.IdempotencyRecord, now time.Time) (*domain.IdempotencyRecord, error) {
+	ref := r.doc(rec.Key)
+	var existing *domain.IdempotencyRecord
+	err := r.client.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		existing = nil
+		snap, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			var m domain.IdempotencyRecord
+			if err := snap.DataTo(&m); err != nil {
+				return err
+			}
+			if m.ExpiresAt.After(now) {
+				m.Key = rec.Key
+				existing = &m
+				return nil
+			}
+		}
+		return tx.Set(ref, rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (r *IdempotencyStore) Complete(ctx context.Context, rec *domain.IdempotencyRecord) error {
+	_, err := r.doc(rec.Key).Set(ctx, rec)
+	return err
+}
+
+func (r *IdempotencyStore) Delete(ctx context.Context, key string) error {
+	_, err := r.doc(key).Delete(ctx)
+	return err
+}
+
+func (r *IdempotencyStore) Purge(ctx context.Context, before time.Time) (int, error) {
+	return deleteWhere(ctx, r.client.client, r.client.client.Collection("idempotency_keys").Where("ExpiresAt", "<", before), nil)
+}
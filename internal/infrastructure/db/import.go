@@ -0,0 +1,28 @@
+package db
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+)
+
+// maxBatchWrites is the Firestore limit of writes per batch commit.
+const maxBatchWrites = 500
+
+// batchSet writes docs with batched Set calls of at most maxBatchWrites.
+func batchSet(ctx context.Context, client *firestore.Client, collection string, ids []string, docs []interface{}) error {
+	for start := 0; start < len(docs); start += maxBatchWrites {
+		end := start + maxBatchWrites
+		if end > len(docs) {
+			end = len(docs)
+		}
+		batch := client.Batch()
+		for i := start; i < end; i++ {
+			batch.Set(client.Collection(collection).Doc(ids[i]), docs[i])
+		}
+		if _, err := batch.Commit(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"encoding/json"
+	"sort"
+
+	"ServiceBookingApp/internal/domain"
+)
+
+// IndexesFile is the format of firestore.indexes.json, as deployed with
+// `firebase deploy --only firestore:indexes`.
+type IndexesFile struct {
+	Indexes        []Index         `json:"indexes"`
+	FieldOverrides []FieldOverride `json:"fieldOverrides"`
+}
+
+type Index struct {
+	CollectionGroup string       `json:"collectionGroup"`
+	QueryScope      string       `json:"queryScope"`
+	Fields          []IndexField `json:"fields"`
+}
+
+type IndexField struct {
+	FieldPath string `json:"fieldPath"`
+	Order     string `json:"order"`
+}
+
+type FieldOverride struct {
+	CollectionGroup string       `json:"collectionGroup"`
+	FieldPath       string       `json:"fieldPath"`
+	TTL             bool         `json:"ttl,omitempty"`
+	Indexes         []IndexField `json:"indexes"`
+}
+
+// CompositeIndexes returns the composite indexes the repositories' queries
+// need. Single-field indexes, which Firestore creates on its own, cover the
+// rest: lookups by one or more equalities, and ordering by document ID.
+//
+// AppointmentsRepository.List combines any subset of its equality filters
+// with a range on ScheduledAt ordered in either direction, and ListByDate
+// uses the ascending one with ProviderId. AuditRepository.List does the same
+// on CreatedAt, newest first only. HoldsRepository.ListByDate is
+// ListByDate's query on holds. ServicesRepository.List orders a provider's
+// services by SortOrder.
+//
+// The field overrides hold the TTL policies.
+func CompositeIndexes() IndexesFile {
+	var indexes []Index
+	indexes = append(indexes, equalitySubsetIndexes("appointments", appointmentEqualities(domain.AppointmentsFilter{}), "ScheduledAt", "ASCENDING", "DESCENDING")...)
+	indexes = append(indexes, equalitySubsetIndexes("audit", auditEqualities(domain.AuditFilter{}), "CreatedAt", "DESCENDING")...)
+	indexes = append(indexes, equalitySubsetIndexes("holds", []equality{{"ProviderId", ""}}, "ScheduledAt", "ASCENDING")...)
+	indexes = append(indexes, equalitySubsetIndexes("services", []equality{{"ProviderId", ""}}, "SortOrder", "ASCENDING")...)
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return indexKey(indexes[i]) < indexKey(indexes[j])
+	})
+	return IndexesFile{Indexes: indexes, FieldOverrides: []FieldOverride{
+		// RateLimitStore documents are deleted once their window is over and
+		// never queried, so they skip the single-field index.
+		{CollectionGroup: "rate_limits", FieldPath: "ExpiresAt", TTL: true, Indexes: []IndexField{}},
+	}}
+}
+
+// equalitySubsetIndexes returns an index for every non-empty subset of
+// equalities followed by orderField in each of orders.
+func equalitySubsetIndexes(collection string, equalities []equality, orderField string, orders ...string) []Index {
+	var indexes []Index
+	for mask := 1; mask < 1<<len(equalities); mask++ {
+		for _, order := range orders {
+			index := Index{CollectionGroup: collection, QueryScope: "COLLECTION"}
+			for i, eq := range equalities {
+				if mask&(1<<i) != 0 {
+					index.Fields = append(index.Fields, IndexField{FieldPath: eq.field, Order: "ASCENDING"})
+				}
+			}
+			index.Fields = append(index.Fields, IndexField{FieldPath: orderField, Order: order})
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes
+}
+
+func indexKey(index Index) string {
+	key := index.CollectionGroup
+	for _, f := range index.Fields {
+		key += "|" + f.FieldPath + ":" + f.Order
+	}
+	return key
+}
+
+// MarshalIndexes renders f the way firestore.indexes.json is committed.
+func MarshalIndexes(f IndexesFile) ([]byte, error) {
+	out, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompositeIndexesFileIsCurrent(t *testing.T) {
+	want, err := MarshalIndexes(CompositeIndexes())
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("../../../firestore.indexes.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "run: go run ./cmd/migrate indexes")
+}
+
+func TestCompositeIndexesCoverProviderListing(t *testing.T) {
+	for _, order := range []string{"ASCENDING", "DESCENDING"} {
+		assert.Contains(t, CompositeIndexes().Indexes, Index{
+			CollectionGroup: "appointments",
+			QueryScope:      "COLLECTION",
+			Fields: []IndexField{
+				{FieldPath: "ProviderId", Order: "ASCENDING"},
+				{FieldPath: "Status", Order: "ASCENDING"},
+				{FieldPath: "ScheduledAt", Order: order},
+			},
+		})
+	}
+}
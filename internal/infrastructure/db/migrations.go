@@ -0,0 +1,483 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ServiceBookingApp/internal/config"
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/geo"
+	"ServiceBookingApp/internal/richtext"
+	"ServiceBookingApp/internal/utils"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+)
+
+// migrationsCollection holds one document per applied migration, keyed by
+// its zero-padded version.
+const migrationsCollection = "_migrations"
+
+// Migration is a versioned, one-off change to the data. Up must be safe to
+// run again if a previous run failed halfway, since it is only recorded as
+// applied once it returns nil.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, client *firestore.Client) error
+}
+
+// AppliedMigration is the record stored in migrationsCollection.
+type AppliedMigration struct {
+	Version   int       `firestore:"Version"`
+	Name      string    `firestore:"Name"`
+	AppliedAt time.Time `firestore:"AppliedAt"`
+}
+
+// Migrations lists every data migration in the order they are applied. New
+// ones go at the end with the next version; applied ones are never edited.
+var Migrations = []Migration{
+	{Version: 1, Name: "backfill appointment duration and service name", Up: backfillAppointmentService},
+	{Version: 2, Name: "backfill provider slugs", Up: backfillProviderSlugs},
+	{Version: 3, Name: "type appointment notes and service descriptions", Up: normalizeRichText},
+	{Version: 4, Name: "backfill service sort order", Up: backfillServiceSortOrder},
+	{Version: 5, Name: "geocode provider addresses", Up: geocodeProviders},
+	{Version: 6, Name: "backfill appointment price", Up: backfillAppointmentPrice},
+}
+
+type Migrator struct {
+	client     *firestore.Client
+	migrations []Migration
+}
+
+func NewMigrator(client *FirestoreRepository, migrations []Migration) *Migrator {
+	return &Migrator{client: client.client, migrations: migrations}
+}
+
+// Applied returns the applied migrations by version.
+func (m *Migrator) Applied(ctx context.Context) (map[int]AppliedMigration, error) {
+	iter := m.client.Collection(migrationsCollection).Documents(ctx)
+	defer iter.Stop()
+	applied := map[int]AppliedMigration{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return applied, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var a AppliedMigration
+		if err := doc.DataTo(&a); err != nil {
+			return nil, err
+		}
+		applied[a.Version] = a
+	}
+}
+
+// Pending returns the migrations not applied yet, in order.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies the pending migrations in order and stops at the first error.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for _, mig := range pending {
+		log.Printf("migrate: applying %04d %s", mig.Version, mig.Name)
+		if err := mig.Up(ctx, m.client); err != nil {
+			return done, fmt.Errorf("migration %04d %s: %w", mig.Version, mig.Name, err)
+		}
+		record := AppliedMigration{Version: mig.Version, Name: mig.Name, AppliedAt: utils.Now()}
+		if _, err := m.client.Collection(migrationsCollection).Doc(fmt.Sprintf("%04d", mig.Version)).Set(ctx, record); err != nil {
+			return done, err
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// serviceFields and appointmentFields are the fields backfillAppointmentService
+// reads. It does not decode into the domain models, whose notes and
+// descriptions are only typed after migration 3.
+type serviceFields struct {
+	DurationMinutes int    `firestore:"DurationMinutes"`
+	Title           string `firestore:"Title"`
+}
+
+type appointmentFields struct {
+	ServiceId       string `firestore:"ServiceId"`
+	DurationMinutes int    `firestore:"DurationMinutes"`
+	ServiceName     string `firestore:"ServiceName"`
+}
+
+// backfillAppointmentService copies DurationMinutes and ServiceName from the
+// booked service onto appointments created before they were denormalized.
+// Appointments whose service no longer exists are left as they are.
+func backfillAppointmentService(ctx context.Context, client *firestore.Client) error {
+	services := map[string]*serviceFields{}
+	service := func(id string) (*serviceFields, error) {
+		if s, ok := services[id]; ok {
+			return s, nil
+		}
+		doc, err := client.Collection("services").Doc(id).Get(ctx)
+		if err != nil {
+			if errors.Is(translateError(err), domain.ErrNotFound) {
+				services[id] = nil
+				return nil, nil
+			}
+			return nil, err
+		}
+		var s serviceFields
+		if err := doc.DataTo(&s); err != nil {
+			return nil, err
+		}
+		services[id] = &s
+		return &s, nil
+	}
+
+	iter := client.Collection("appointments").Documents(ctx)
+	defer iter.Stop()
+	batch := client.Batch()
+	n := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var a appointmentFields
+		if err := doc.DataTo(&a); err != nil {
+			return err
+		}
+		if (a.DurationMinutes != 0 && a.ServiceName != "") || a.ServiceId == "" {
+			continue
+		}
+		s, err := service(a.ServiceId)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			continue
+		}
+
+		var updates []firestore.Update
+		if a.DurationMinutes == 0 && s.DurationMinutes != 0 {
+			updates = append(updates, firestore.Update{Path: "DurationMinutes", Value: s.DurationMinutes})
+		}
+		if a.ServiceName == "" && s.Title != "" {
+			updates = append(updates, firestore.Update{Path: "ServiceName", Value: s.Title})
+		}
+		if len(updates) == 0 {
+			continue
+		}
+		batch.Update(doc.Ref, updates)
+		n++
+		if n == maxBatchWrites {
+			if _, err := batch.Commit(ctx); err != nil {
+				return err
+			}
+			batch = client.Batch()
+			n = 0
+		}
+	}
+	if n > 0 {
+		if _, err := batch.Commit(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillProviderSlugs gives providers created before slugs existed their
+// document ID as slug, so their public URLs keep working.
+func backfillProviderSlugs(ctx context.Context, client *firestore.Client) error {
+	iter := client.Collection("providers").Documents(ctx)
+	defer iter.Stop()
+	batch := client.Batch()
+	n := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var p domain.Providers
+		if err := doc.DataTo(&p); err != nil {
+			return err
+		}
+		if p.Slug != "" {
+			continue
+		}
+		batch.Update(doc.Ref, []firestore.Update{{Path: "Slug", Value: doc.Ref.ID}})
+		n++
+		if n == maxBatchWrites {
+			if _, err := batch.Commit(ctx); err != nil {
+				return err
+			}
+			batch = client.Batch()
+			n = 0
+		}
+	}
+	if n > 0 {
+		if _, err := batch.Commit(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeRichText rewrites the notes and descriptions stored as free-form
+// values before they were typed into domain.Notes and domain.Description,
+// rendering the descriptions' Markdown.
+func normalizeRichText(ctx context.Context, client *firestore.Client) error {
+	err := normalizeField(ctx, client, "appointments", "Notes", "Text",
+		func(v interface{}) interface{} { return richtext.LegacyNotes(v) })
+	if err != nil {
+		return err
+	}
+	return normalizeField(ctx, client, "services", "Description", "HTML",
+		func(v interface{}) interface{} { return richtext.LegacyDescription(v) })
+}
+
+// normalizeField replaces the non-null values of field that are not maps
+// with the marker key by their conversion.
+func normalizeField(ctx context.Context, client *firestore.Client, collection, field, marker string, convert func(interface{}) interface{}) error {
+	iter := client.Collection(collection).Documents(ctx)
+	defer iter.Stop()
+	batch := client.Batch()
+	n := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		v := doc.Data()[field]
+		if v == nil {
+			continue
+		}
+		if m, ok := v.(map[string]interface{}); ok {
+			if _, typed := m[marker]; typed {
+				continue
+			}
+		}
+		batch.Update(doc.Ref, []firestore.Update{{Path: field, Value: convert(v)}})
+		n++
+		if n == maxBatchWrites {
+			if _, err := batch.Commit(ctx); err != nil {
+				return err
+			}
+			batch = client.Batch()
+			n = 0
+		}
+	}
+	if n > 0 {
+		if _, err := batch.Commit(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillServiceSortOrder gives services stored before they had a sort
+// order a SortOrder of 0. Queries ordered by a field skip documents that
+// lack it, so the listing would not return them.
+func backfillServiceSortOrder(ctx context.Context, client *firestore.Client) error {
+	iter := client.Collection("services").Documents(ctx)
+	defer iter.Stop()
+	batch := client.Batch()
+	n := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := doc.Data()["SortOrder"]; ok {
+			continue
+		}
+		batch.Update(doc.Ref, []firestore.Update{{Path: "SortOrder", Value: 0}})
+		n++
+		if n == maxBatchWrites {
+			if _, err := batch.Commit(ctx); err != nil {
+				return err
+			}
+			batch = client.Batch()
+			n = 0
+		}
+	}
+	if n > 0 {
+		if _, err := batch.Commit(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// geocodeInterval spaces the lookups of geocodeProviders, as Nominatim
+// allows one request per second.
+const geocodeInterval = time.Second
+
+// geocodeProviders locates the providers saved before addresses were
+// geocoded, using the geocoder GEOCODER names. Without one the API does not
+// geocode either, so there is nothing to backfill.
+func geocodeProviders(ctx context.Context, client *firestore.Client) error {
+	geocoder, err := geo.NewGeocoder(config.GetGeocoder(), config.GetGeocoderURL())
+	if err != nil {
+		return err
+	}
+	if geocoder == nil {
+		log.Println("migrate: GEOCODER is not set, providers are left without coordinates")
+		return nil
+	}
+	return locateProviders(ctx, client, geocoder, geocodeInterval)
+}
+
+// locateProviders geocodes every provider that has an address but no
+// Geohash. The providers are read up front so the query does not stay open
+// while the lookups are throttled; addresses that cannot be found are left
+// as they are.
+func locateProviders(ctx context.Context, client *firestore.Client, geocoder geo.Geocoder, interval time.Duration) error {
+	docs, err := client.Collection("providers").Documents(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+
+	batch := client.Batch()
+	n, lookups := 0, 0
+	for _, doc := range docs {
+		var p domain.Providers
+		if err := doc.DataTo(&p); err != nil {
+			return err
+		}
+		if p.Geohash != "" || p.Address == "" {
+			continue
+		}
+		if lookups > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+		lookups++
+		if !geo.Locate(ctx, geocoder, &p) {
+			continue
+		}
+		batch.Update(doc.Ref, []firestore.Update{
+			{Path: "Latitude", Value: p.Latitude},
+			{Path: "Longitude", Value: p.Longitude},
+			{Path: "Geohash", Value: p.Geohash},
+		})
+		n++
+		if n == maxBatchWrites {
+			if _, err := batch.Commit(ctx); err != nil {
+				return err
+			}
+			batch = client.Batch()
+			n = 0
+		}
+	}
+	if n > 0 {
+		if _, err := batch.Commit(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillAppointmentPrice gives appointments stored before they kept their
+// price the current price of their service. Appointments whose service no
+// longer exists are left without one.
+func backfillAppointmentPrice(ctx context.Context, client *firestore.Client) error {
+	prices := map[string]interface{}{}
+	price := func(id string) (interface{}, error) {
+		if p, ok := prices[id]; ok {
+			return p, nil
+		}
+		doc, err := client.Collection("services").Doc(id).Get(ctx)
+		if err != nil {
+			if errors.Is(translateError(err), domain.ErrNotFound) {
+				prices[id] = nil
+				return nil, nil
+			}
+			return nil, err
+		}
+		p, ok := doc.Data()["Price"]
+		if !ok {
+			p = 0.0
+		}
+		prices[id] = p
+		return p, nil
+	}
+
+	iter := client.Collection("appointments").Documents(ctx)
+	defer iter.Stop()
+	batch := client.Batch()
+	n := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		data := doc.Data()
+		if _, ok := data["Price"]; ok {
+			continue
+		}
+		serviceId, _ := data["ServiceId"].(string)
+		if serviceId == "" {
+			continue
+		}
+		p, err := price(serviceId)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			continue
+		}
+		batch.Update(doc.Ref, []firestore.Update{{Path: "Price", Value: p}})
+		n++
+		if n == maxBatchWrites {
+			if _, err := batch.Commit(ctx); err != nil {
+				return err
+			}
+			batch = client.Batch()
+			n = 0
+		}
+	}
+	if n > 0 {
+		if _, err := batch.Commit(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
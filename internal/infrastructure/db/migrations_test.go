@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/geo"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator(t *testing.T) {
+	client := newEmulatorRepository(t)
+	ctx := context.Background()
+
+	services := NewServicesRepository(client)
+	appointments := NewAppointmentsRepository(client)
+
+	serviceId, err := services.Create(ctx, &domain.Services{ProviderId: "prov-1", Title: "Corte", DurationMinutes: 45, Price: 15})
+	require.NoError(t, err)
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	legacy, err := appointments.Create(ctx, &domain.Appointments{ProviderId: "prov-1", ServiceId: serviceId, ScheduledAt: at})
+	require.NoError(t, err)
+	current, err := appointments.Create(ctx, &domain.Appointments{ProviderId: "prov-1", ServiceId: serviceId, ScheduledAt: at, DurationMinutes: 20, ServiceName: "Corte corto", Price: 12})
+	require.NoError(t, err)
+	// Appointments saved before they kept their price lack the field.
+	_, err = client.client.Collection("appointments").Doc(legacy).Update(ctx, []firestore.Update{{Path: "Price", Value: firestore.Delete}})
+	require.NoError(t, err)
+	orphan, err := appointments.Create(ctx, &domain.Appointments{ProviderId: "prov-1", ServiceId: "gone", ScheduledAt: at})
+	require.NoError(t, err)
+
+	providers := NewProvidersRepository(client)
+	unslugged, err := providers.Create(ctx, &domain.Providers{UserId: "user-1"})
+	require.NoError(t, err)
+	slugged, err := providers.Create(ctx, &domain.Providers{UserId: "user-2", Slug: "barberia"})
+	require.NoError(t, err)
+
+	migrator := NewMigrator(client, Migrations)
+	done, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, done, len(Migrations))
+
+	m, err := appointments.Get(ctx, legacy)
+	require.NoError(t, err)
+	assert.Equal(t, 45, m.DurationMinutes)
+	assert.Equal(t, "Corte", m.ServiceName)
+	assert.Equal(t, 15.0, m.Price)
+
+	m, err = appointments.Get(ctx, current)
+	require.NoError(t, err)
+	assert.Equal(t, 20, m.DurationMinutes, "existing values are kept")
+	assert.Equal(t, "Corte corto", m.ServiceName)
+	assert.Equal(t, 12.0, m.Price)
+
+	m, err = appointments.Get(ctx, orphan)
+	require.NoError(t, err)
+	assert.Zero(t, m.DurationMinutes)
+
+	p, err := providers.Get(ctx, unslugged)
+	require.NoError(t, err)
+	assert.Equal(t, unslugged, p.Slug)
+	p, err = providers.Get(ctx, slugged)
+	require.NoError(t, err)
+	assert.Equal(t, "barberia", p.Slug)
+
+	applied, err := migrator.Applied(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "backfill appointment duration and service name", applied[1].Name)
+
+	done, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, done, "applied migrations are not run again")
+}
+
+func TestLocateProviders(t *testing.T) {
+	client := newEmulatorRepository(t)
+	ctx := context.Background()
+
+	providers := NewProvidersRepository(client)
+	missing, err := providers.Create(ctx, &domain.Providers{UserId: "user-1", Address: "Av. Corrientes 1234", City: "Buenos Aires"})
+	require.NoError(t, err)
+	unknown, err := providers.Create(ctx, &domain.Providers{UserId: "user-2", Address: "Nowhere 1"})
+	require.NoError(t, err)
+	located, err := providers.Create(ctx, &domain.Providers{UserId: "user-3", Address: "Florida 100", Latitude: 1, Longitude: 2, Geohash: "s00twy01"})
+	require.NoError(t, err)
+
+	geocoder := geo.StaticGeocoder{
+		"Av. Corrientes 1234, Buenos Aires": {Lat: -34.6037, Lng: -58.3816},
+		"Florida 100":                       {Lat: -34.6, Lng: -58.37},
+	}
+	require.NoError(t, locateProviders(ctx, client.client, geocoder, 0))
+
+	p, err := providers.Get(ctx, missing)
+	require.NoError(t, err)
+	assert.Equal(t, -34.6037, p.Latitude)
+	assert.Equal(t, geo.Encode(-34.6037, -58.3816, geo.Precision), p.Geohash)
+
+	p, err = providers.Get(ctx, unknown)
+	require.NoError(t, err)
+	assert.Empty(t, p.Geohash, "addresses that are not found are left alone")
+
+	p, err = providers.Get(ctx, located)
+	require.NoError(t, err)
+	assert.Equal(t, "s00twy01", p.Geohash, "providers with a location are not looked up again")
+	assert.Equal(t, 1.0, p.Latitude)
+}
@@ -2,8 +2,11 @@ package db
 
 import (
 	"context"
+	"time"
 	
 	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"cloud.google.com/go/firestore"
 	"ServiceBookingApp/internal/utils"
 	"google.golang.org/api/iterator"
 )
@@ -16,32 +19,58 @@ func NewProvidersRepository(client *FirestoreRepository) *ProvidersRepository {
 	return &ProvidersRepository{client: client}
 }
 
-func (r *ProvidersRepository) List(ctx context.Context, limit, offset int) ([]*domain.Providers, error) {
-	iter := r.client.client.Collection("providers").Offset(offset).Limit(limit).Documents(ctx)
+func (r *ProvidersRepository) List(ctx context.Context, opts domain.ListOptions) ([]*domain.Providers, string, error) {
+	after, err := pagination.Decode(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := r.client.client.Collection("providers").OrderBy(firestore.DocumentID, firestore.Asc)
+	if after != nil {
+		query = query.StartAfter(after.ID)
+	}
+
+	limit := opts.PageSize()
 	var results []*domain.Providers
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
+	err = readPage(ctx, query, limit, func(doc *firestore.DocumentSnapshot) (bool, error) {
 		var m domain.Providers
 		if err := doc.DataTo(&m); err != nil {
-			return nil, err
+			return false, err
+		}
+		if m.DeletedAt != nil && !opts.IncludeDeleted {
+			return false, nil
 		}
 		m.ID = doc.Ref.ID
 		results = append(results, &m)
+		return true, nil
+	})
+	if err != nil {
+		return nil, "", err
 	}
-	return results, nil
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	return results, pagination.Encode(pagination.Cursor{ID: results[limit-1].ID}), nil
 }
 
 func (r *ProvidersRepository) Get(ctx context.Context, id string) (*domain.Providers, error) {
-	doc, err := r.client.client.Collection("providers").Doc(id).Get(ctx)
+	m, err := r.GetIncludingDeleted(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if m.DeletedAt != nil {
+		return nil, domain.ErrNotFound
+	}
+	return m, nil
+}
+
+func (r *ProvidersRepository) GetIncludingDeleted(ctx context.Context, id string) (*domain.Providers, error) {
+	doc, err := r.client.client.Collection("providers").Doc(id).Get(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
 	var m domain.Providers
 	if err := doc.DataTo(&m); err != nil {
 		return nil, err
@@ -51,20 +80,128 @@ func (r *ProvidersRepository) Get(ctx context.Context, id string) (*domain.Provi
 }
 
 func (r *ProvidersRepository) GetByUserId(ctx context.Context, userId string) (*domain.Providers, error) {
-	iter := r.client.client.Collection("providers").Where("UserId", "==", userId).Limit(1).Documents(ctx)
-	doc, err := iter.Next()
-	if err == iterator.Done {
-		return nil, nil
+	iter := r.client.client.Collection("providers").Where("UserId", "==", userId).Documents(ctx)
+	defer iter.Stop()
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var m domain.Providers
+		if err := doc.DataTo(&m); err != nil {
+			return nil, err
+		}
+		if m.DeletedAt != nil {
+			continue
+		}
+		m.ID = doc.Ref.ID
+		return &m, nil
+	}
+}
+
+func (r *ProvidersRepository) GetBySlug(ctx context.Context, slug string) (*domain.Providers, error) {
+	iter := r.client.client.Collection("providers").Where("Slug", "==", slug).Documents(ctx)
+	defer iter.Stop()
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return nil, domain.ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		var m domain.Providers
+		if err := doc.DataTo(&m); err != nil {
+			return nil, err
+		}
+		if m.DeletedAt != nil {
+			continue
+		}
+		m.ID = doc.Ref.ID
+		return &m, nil
 	}
+}
+
+// Search only combines equalities with the document ID order, which the
+// single-field indexes serve. Deleted providers are skipped while reading.
+func (r *ProvidersRepository) Search(ctx context.Context, filter domain.ProviderFilter) ([]*domain.Providers, string, error) {
+	after, err := pagination.Decode(filter.Cursor)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	var m domain.Providers
-	if err := doc.DataTo(&m); err != nil {
-		return nil, err
+
+	query := r.client.client.Collection("providers").Query
+	if filter.City != "" {
+		query = query.Where("City", "==", filter.City)
+	}
+	if filter.BusinessType != "" {
+		query = query.Where("BusinessType", "==", filter.BusinessType)
+	}
+	query = query.OrderBy(firestore.DocumentID, firestore.Asc)
+	if after != nil {
+		query = query.StartAfter(after.ID)
+	}
+
+	limit := filter.PageSize()
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+	var results []*domain.Providers
+	for len(results) <= limit {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		var m domain.Providers
+		if err := doc.DataTo(&m); err != nil {
+			return nil, "", err
+		}
+		if m.DeletedAt != nil {
+			continue
+		}
+		m.ID = doc.Ref.ID
+		results = append(results, &m)
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	return results, pagination.Encode(pagination.Cursor{ID: results[limit-1].ID}), nil
+}
+
+// ListByGeohash reads the prefix as a range on Geohash: every character of
+// the geohash alphabet sorts before "~".
+func (r *ProvidersRepository) ListByGeohash(ctx context.Context, prefix string) ([]*domain.Providers, error) {
+	iter := r.client.client.Collection("providers").
+		Where("Geohash", ">=", prefix).
+		Where("Geohash", "<", prefix+"~").
+		Documents(ctx)
+	defer iter.Stop()
+	var results []*domain.Providers
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return results, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var m domain.Providers
+		if err := doc.DataTo(&m); err != nil {
+			return nil, err
+		}
+		if m.DeletedAt != nil {
+			continue
+		}
+		m.ID = doc.Ref.ID
+		results = append(results, &m)
 	}
-	m.ID = doc.Ref.ID
-	return &m, nil
 }
 
 func (r *ProvidersRepository) Create(ctx context.Context, model *domain.Providers) (string, error) {
@@ -88,3 +225,7 @@ func (r *ProvidersRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.client.client.Collection("providers").Doc(id).Delete(ctx)
 	return err
 }
+
+func (r *ProvidersRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	return purgeDeleted(ctx, r.client.client, "providers", deletedBefore)
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimitStore keeps one document per key and window in "rate_limits".
+// Finished windows are never read again; the TTL policy on ExpiresAt in
+// firestore.indexes.json deletes them.
+type RateLimitStore struct {
+	client *FirestoreRepository
+}
+
+func NewRateLimitStore(client *FirestoreRepository) *RateLimitStore {
+	return &RateLimitStore{client: client}
+}
+
+type rateLimitDoc struct {
+	Count     int       `firestore:"Count"`
+	ExpiresAt time.Time `firestore:"ExpiresAt"`
+}
+
+func (r *RateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	resetAt := domain.WindowStart(utils.Now(), window).Add(window)
+	// Document IDs cannot contain slashes.
+	id := fmt.Sprintf("%s@%d", strings.ReplaceAll(key, "/", "_"), resetAt.Unix())
+	ref := r.client.client.Collection("rate_limits").Doc(id)
+
+	var count int
+	err := r.client.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var d rateLimitDoc
+		snap, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := snap.DataTo(&d); err != nil {
+				return err
+			}
+		}
+		d.Count++
+		d.ExpiresAt = resetAt
+		count = d.Count
+		return tx.Set(ref, d)
+	})
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return count, resetAt, nil
+}
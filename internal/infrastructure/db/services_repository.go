@@ -2,10 +2,13 @@ package db
 
 import (
 	"context"
+	"time"
 	
 	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"cloud.google.com/go/firestore"
 	"ServiceBookingApp/internal/utils"
-	"google.golang.org/api/iterator"
 )
 
 type ServicesRepository struct {
@@ -16,36 +19,66 @@ func NewServicesRepository(client *FirestoreRepository) *ServicesRepository {
 	return &ServicesRepository{client: client}
 }
 
-func (r *ServicesRepository) List(ctx context.Context, limit, offset int, providerId string) ([]*domain.Services, error) {
+func (r *ServicesRepository) List(ctx context.Context, opts domain.ListOptions, providerId string) ([]*domain.Services, string, error) {
+	after, err := pagination.Decode(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
 	query := r.client.client.Collection("services").Query
 	if providerId != "" {
 		query = query.Where("ProviderId", "==", providerId)
 	}
-	iter := query.Offset(offset).Limit(limit).Documents(ctx)
-	var results []*domain.Services
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, err
+	query = query.OrderBy("SortOrder", firestore.Asc).OrderBy(firestore.DocumentID, firestore.Asc)
+	if after != nil {
+		if after.SortOrder == nil {
+			return nil, "", domain.ErrInvalidCursor
 		}
+		query = query.StartAfter(*after.SortOrder, after.ID)
+	}
+
+	limit := opts.PageSize()
+	var results []*domain.Services
+	err = readPage(ctx, query, limit, func(doc *firestore.DocumentSnapshot) (bool, error) {
 		var m domain.Services
 		if err := doc.DataTo(&m); err != nil {
-			return nil, err
+			return false, err
+		}
+		if m.DeletedAt != nil && !opts.IncludeDeleted {
+			return false, nil
 		}
 		m.ID = doc.Ref.ID
 		results = append(results, &m)
+		return true, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
 	}
-	return results, nil
+	results = results[:limit]
+	last := results[limit-1]
+	return results, pagination.Encode(pagination.Cursor{SortOrder: &last.SortOrder, ID: last.ID}), nil
 }
 
 func (r *ServicesRepository) Get(ctx context.Context, id string) (*domain.Services, error) {
-	doc, err := r.client.client.Collection("services").Doc(id).Get(ctx)
+	m, err := r.GetIncludingDeleted(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if m.DeletedAt != nil {
+		return nil, domain.ErrNotFound
+	}
+	return m, nil
+}
+
+func (r *ServicesRepository) GetIncludingDeleted(ctx context.Context, id string) (*domain.Services, error) {
+	doc, err := r.client.client.Collection("services").Doc(id).Get(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
 	var m domain.Services
 	if err := doc.DataTo(&m); err != nil {
 		return nil, err
@@ -75,3 +108,74 @@ func (r *ServicesRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.client.client.Collection("services").Doc(id).Delete(ctx)
 	return err
 }
+
+// Reorder reads every service of the provider in the transaction, so a
+// concurrent change to any of them makes it retry rather than renumber from
+// a stale order.
+func (r *ServicesRepository) Reorder(ctx context.Context, providerId string, ids []string) ([]*domain.Services, error) {
+	client := r.client.client
+	var ordered []*domain.Services
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docs, err := tx.Documents(client.Collection("services").
+			Where("ProviderId", "==", providerId).
+			OrderBy("SortOrder", firestore.Asc).
+			OrderBy(firestore.DocumentID, firestore.Asc)).GetAll()
+		if err != nil {
+			return err
+		}
+		var services []*domain.Services
+		for _, doc := range docs {
+			var m domain.Services
+			if err := doc.DataTo(&m); err != nil {
+				return err
+			}
+			if m.DeletedAt != nil {
+				continue
+			}
+			m.ID = doc.Ref.ID
+			services = append(services, &m)
+		}
+
+		var changed []*domain.Services
+		ordered, changed, err = domain.ReorderServices(services, ids)
+		if err != nil {
+			return err
+		}
+		now := utils.Now()
+		for _, m := range changed {
+			m.UpdatedAt = now
+			err := tx.Update(client.Collection("services").Doc(m.ID), []firestore.Update{
+				{Path: "SortOrder", Value: m.SortOrder},
+				{Path: "UpdatedAt", Value: m.UpdatedAt},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ordered, nil
+}
+
+func (r *ServicesRepository) Import(ctx context.Context, models []*domain.Services) error {
+	now := utils.Now()
+	ids := make([]string, len(models))
+	docs := make([]interface{}, len(models))
+	for i, m := range models {
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = now
+		}
+		m.UpdatedAt = now
+		m.ID = docid.Import(m.ProviderId, m.ExternalId)
+		ids[i] = m.ID
+		docs[i] = m
+	}
+	return batchSet(ctx, r.client.client, "services", ids, docs)
+}
+
+func (r *ServicesRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	return purgeDeleted(ctx, r.client.client, "services", deletedBefore)
+}
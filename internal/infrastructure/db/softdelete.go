@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Live documents have no DeletedAt field at all (it is omitempty) and
+// Firestore cannot query for a missing field, so listings skip soft-deleted
+// documents while reading rather than in the query, through readPage.
+
+func translateError(err error) error {
+	if status.Code(err) == codes.NotFound {
+		return domain.ErrNotFound
+	}
+	return err
+}
+
+// readPage reads query in batches of at most limit+1 documents, each
+// starting after the last one read, and hands the documents to add until it
+// has accepted limit+1 of them or the query runs out. add rejects the
+// documents a listing skips, so a run of soft-deleted ones costs further
+// bounded batches rather than an unbounded read.
+func readPage(ctx context.Context, query firestore.Query, limit int, add func(*firestore.DocumentSnapshot) (bool, error)) error {
+	size := limit + 1
+	accepted := 0
+	var last *firestore.DocumentSnapshot
+	for {
+		q := query.Limit(size)
+		if last != nil {
+			q = q.StartAfter(last)
+		}
+		docs, err := q.Documents(ctx).GetAll()
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			ok, err := add(doc)
+			if err != nil {
+				return err
+			}
+			if ok {
+				accepted++
+				if accepted == size {
+					return nil
+				}
+			}
+		}
+		if len(docs) < size {
+			return nil
+		}
+		last = docs[len(docs)-1]
+	}
+}
+
+// purgeDeleted deletes the documents of collection soft-deleted before the
+// cutoff.
+func purgeDeleted(ctx context.Context, client *firestore.Client, collection string, before time.Time) (int, error) {
+	return deleteWhere(ctx, client, client.Collection(collection).Where("DeletedAt", "<", before), nil)
+}
+
+// deleteWhere deletes every document query matches, except those keep
+// reports true for when it is not nil, in batches of at most
+// maxBatchWrites.
+func deleteWhere(ctx context.Context, client *firestore.Client, query firestore.Query, keep func(*firestore.DocumentSnapshot) bool) (int, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	purged := 0
+	for done := false; !done; {
+		batch := client.Batch()
+		n := 0
+		for n < maxBatchWrites {
+			doc, err := iter.Next()
+			if err == iterator.Done {
+				done = true
+				break
+			}
+			if err != nil {
+				return purged, err
+			}
+			if keep != nil && keep(doc) {
+				continue
+			}
+			batch.Delete(doc.Ref)
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+		if _, err := batch.Commit(ctx); err != nil {
+			return purged, err
+		}
+		purged += n
+	}
+	return purged, nil
+}
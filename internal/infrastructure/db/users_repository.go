@@ -4,6 +4,8 @@ import (
 	"context"
 	
 	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"cloud.google.com/go/firestore"
 	"google.golang.org/api/iterator"
 )
 
@@ -15,8 +17,19 @@ func NewUsersRepository(client *FirestoreRepository) *UsersRepository {
 	return &UsersRepository{client: client}
 }
 
-func (r *UsersRepository) List(ctx context.Context, limit, offset int) ([]*domain.Users, error) {
-	iter := r.client.client.Collection("users").Offset(offset).Limit(limit).Documents(ctx)
+func (r *UsersRepository) List(ctx context.Context, opts domain.ListOptions) ([]*domain.Users, string, error) {
+	after, err := pagination.Decode(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := r.client.client.Collection("users").OrderBy(firestore.DocumentID, firestore.Asc)
+	if after != nil {
+		query = query.StartAfter(after.ID)
+	}
+
+	limit := opts.PageSize()
+	iter := query.Limit(limit + 1).Documents(ctx)
 	var results []*domain.Users
 	for {
 		doc, err := iter.Next()
@@ -24,22 +37,27 @@ func (r *UsersRepository) List(ctx context.Context, limit, offset int) ([]*domai
 			break
 		}
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		var m domain.Users
 		if err := doc.DataTo(&m); err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		m.ID = doc.Ref.ID
 		results = append(results, &m)
 	}
-	return results, nil
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	return results, pagination.Encode(pagination.Cursor{ID: results[limit-1].ID}), nil
 }
 
 func (r *UsersRepository) Get(ctx context.Context, id string) (*domain.Users, error) {
 	doc, err := r.client.client.Collection("users").Doc(id).Get(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	var m domain.Users
 	if err := doc.DataTo(&m); err != nil {
@@ -0,0 +1,34 @@
+// Package docid generates the document IDs shared by the storage adapters,
+// so IDs look alike whatever the driver.
+package docid
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+)
+
+const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// New returns a random 20 character ID, the same shape as Firestore's
+// auto-generated document IDs.
+func New() string {
+	b := make([]byte, 20)
+	max := big.NewInt(int64(len(alphabet)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		b[i] = alphabet[n.Int64()]
+	}
+	return string(b)
+}
+
+// Import derives a stable ID from the provider and the external ID of an
+// imported record, so re-imports overwrite instead of duplicating.
+func Import(providerId, externalId string) string {
+	sum := sha256.Sum256([]byte(providerId + "\x00" + externalId))
+	return "imp_" + hex.EncodeToString(sum[:])[:24]
+}
@@ -0,0 +1,20 @@
+package docid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	id := New()
+	assert.Len(t, id, 20)
+	assert.Regexp(t, `^[A-Za-z0-9]+$`, id)
+	assert.NotEqual(t, id, New())
+}
+
+func TestImport(t *testing.T) {
+	assert.Equal(t, Import("p1", "x"), Import("p1", "x"))
+	assert.NotEqual(t, Import("p1", "x"), Import("p2", "x"))
+	assert.Regexp(t, `^imp_[0-9a-f]{24}$`, Import("p1", "x"))
+}
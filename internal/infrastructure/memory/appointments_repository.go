@@ -0,0 +1,232 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"ServiceBookingApp/internal/utils"
+)
+
+type AppointmentsRepository struct {
+	store *Store
+}
+
+func NewAppointmentsRepository(store *Store) *AppointmentsRepository {
+	return &AppointmentsRepository{store: store}
+}
+
+func (r *AppointmentsRepository) ListByDate(ctx context.Context, date time.Time, providerId string) ([]*domain.Appointments, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	r.store.mu.RLock()
+	var results []*domain.Appointments
+	for _, m := range r.store.appointments {
+		if providerId != "" && m.ProviderId != providerId {
+			continue
+		}
+		if m.DeletedAt != nil {
+			continue
+		}
+		if m.ScheduledAt.Before(startOfDay) || !m.ScheduledAt.Before(endOfDay) {
+			continue
+		}
+		m := m
+		results = append(results, &m)
+	}
+	r.store.mu.RUnlock()
+
+	sortAppointments(results, true)
+	return results, nil
+}
+
+func (r *AppointmentsRepository) List(ctx context.Context, filter domain.AppointmentsFilter) ([]*domain.Appointments, string, error) {
+	after, err := pagination.Decode(filter.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if after != nil && after.ScheduledAt == nil {
+		return nil, "", domain.ErrInvalidCursor
+	}
+
+	now := utils.Now()
+	ascending := filter.Type == "upcoming"
+
+	r.store.mu.RLock()
+	var results []*domain.Appointments
+	for _, m := range r.store.appointments {
+		if !matches(&m, filter, now) {
+			continue
+		}
+		if after != nil && !isAfter(&m, after, ascending) {
+			continue
+		}
+		m := m
+		results = append(results, &m)
+	}
+	r.store.mu.RUnlock()
+
+	sortAppointments(results, ascending)
+
+	limit := filter.PageSize()
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	last := results[limit-1]
+	return results, pagination.Encode(pagination.Cursor{ScheduledAt: &last.ScheduledAt, ID: last.ID}), nil
+}
+
+func matches(m *domain.Appointments, f domain.AppointmentsFilter, now time.Time) bool {
+	switch {
+	case !f.IncludeDeleted && m.DeletedAt != nil,
+		f.ProviderId != "" && m.ProviderId != f.ProviderId,
+		f.Status != "" && m.Status != f.Status,
+		f.ServiceId != "" && m.ServiceId != f.ServiceId,
+		f.Customer != "" && m.CustomerEmail != f.Customer,
+		f.Type == "upcoming" && m.ScheduledAt.Before(now),
+		f.Type == "past" && !m.ScheduledAt.Before(now),
+		!f.From.IsZero() && m.ScheduledAt.Before(f.From),
+		!f.To.IsZero() && !m.ScheduledAt.Before(f.To):
+		return false
+	}
+	return true
+}
+
+// isAfter reports whether m comes after the cursor in the listing order.
+func isAfter(m *domain.Appointments, c *pagination.Cursor, ascending bool) bool {
+	if !m.ScheduledAt.Equal(*c.ScheduledAt) {
+		return m.ScheduledAt.After(*c.ScheduledAt) == ascending
+	}
+	if ascending {
+		return m.ID > c.ID
+	}
+	return m.ID < c.ID
+}
+
+func sortAppointments(results []*domain.Appointments, ascending bool) {
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if !a.ScheduledAt.Equal(b.ScheduledAt) {
+			return a.ScheduledAt.Before(b.ScheduledAt) == ascending
+		}
+		return (a.ID < b.ID) == ascending
+	})
+}
+
+func (r *AppointmentsRepository) Get(ctx context.Context, id string) (*domain.Appointments, error) {
+	m, err := r.GetIncludingDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.DeletedAt != nil {
+		return nil, domain.ErrNotFound
+	}
+	return m, nil
+}
+
+func (r *AppointmentsRepository) GetIncludingDeleted(ctx context.Context, id string) (*domain.Appointments, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	m, ok := r.store.appointments[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &m, nil
+}
+
+func (r *AppointmentsRepository) Create(ctx context.Context, model *domain.Appointments) (string, error) {
+	now := utils.Now()
+	model.CreatedAt = now
+	model.UpdatedAt = now
+	id := docid.New()
+	if err := r.save(id, model); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *AppointmentsRepository) Update(ctx context.Context, id string, m *domain.Appointments) error {
+	m.UpdatedAt = utils.Now()
+	return r.save(id, m)
+}
+
+func (r *AppointmentsRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	delete(r.store.appointments, id)
+	return nil
+}
+
+// Import upserts on (provider_id, external_id); rows already present keep
+// their ID. Imports are historical, so they skip the overlap check.
+func (r *AppointmentsRepository) Import(ctx context.Context, models []*domain.Appointments) error {
+	now := utils.Now()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, m := range models {
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = now
+		}
+		m.UpdatedAt = now
+		m.ID = docid.New()
+		for id, existing := range r.store.appointments {
+			if existing.ProviderId == m.ProviderId && existing.ExternalId == m.ExternalId {
+				m.ID = id
+				break
+			}
+		}
+		r.store.appointments[m.ID] = *m
+	}
+	return nil
+}
+
+// save stores m under id, replacing it as a whole like Firestore's Set. The
+// overlap check runs under the write lock, so two concurrent bookings of the
+// same slot cannot both succeed.
+func (r *AppointmentsRepository) save(id string, m *domain.Appointments) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if isLive(m) {
+		start := m.ScheduledAt
+		end := start.Add(time.Duration(m.DurationMinutes) * time.Minute)
+		for otherId, other := range r.store.appointments {
+			if otherId == id || other.ProviderId != m.ProviderId || !isLive(&other) {
+				continue
+			}
+			otherEnd := other.ScheduledAt.Add(time.Duration(other.DurationMinutes) * time.Minute)
+			if start.Before(otherEnd) && end.After(other.ScheduledAt) {
+				return domain.ErrSlotTaken
+			}
+		}
+	}
+
+	stored := *m
+	stored.ID = id
+	r.store.appointments[id] = stored
+	return nil
+}
+
+func isLive(m *domain.Appointments) bool {
+	return m.DeletedAt == nil && m.Status != domain.StatusCancelled
+}
+
+// Purge hard-deletes the appointments soft-deleted before deletedBefore,
+// except cancelled ones: their DeletedAt marks the cancellation, which stats
+// and exports still report.
+func (r *AppointmentsRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return purge(r.store.appointments, func(m domain.Appointments) *time.Time {
+		if m.Status == domain.StatusCancelled {
+			return nil
+		}
+		return m.DeletedAt
+	}, deletedBefore), nil
+}
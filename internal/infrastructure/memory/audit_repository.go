@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"ServiceBookingApp/internal/utils"
+)
+
+type AuditRepository struct {
+	store *Store
+}
+
+func NewAuditRepository(store *Store) *AuditRepository {
+	return &AuditRepository{store: store}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, entry *domain.AuditEntry) (string, error) {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = utils.Now()
+	}
+	id := docid.New()
+	m := *entry
+	m.ID = id
+	m.Diff = copyDiff(entry.Diff)
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.audit[id] = m
+	return id, nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, string, error) {
+	after, err := pagination.Decode(filter.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if after != nil && after.ScheduledAt == nil {
+		return nil, "", domain.ErrInvalidCursor
+	}
+
+	r.store.mu.RLock()
+	var results []*domain.AuditEntry
+	for _, m := range r.store.audit {
+		if !matchesAudit(&m, filter) {
+			continue
+		}
+		if after != nil && !(m.CreatedAt.Before(*after.ScheduledAt) || m.CreatedAt.Equal(*after.ScheduledAt) && m.ID < after.ID) {
+			continue
+		}
+		m := m
+		m.Diff = copyDiff(m.Diff)
+		results = append(results, &m)
+	}
+	r.store.mu.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+
+	limit := filter.PageSize()
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	last := results[limit-1]
+	return results, pagination.Encode(pagination.Cursor{ScheduledAt: &last.CreatedAt, ID: last.ID}), nil
+}
+
+func (r *AuditRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	n := 0
+	for id, m := range r.store.audit {
+		if m.CreatedAt.Before(before) {
+			delete(r.store.audit, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func matchesAudit(m *domain.AuditEntry, f domain.AuditFilter) bool {
+	switch {
+	case f.ProviderId != "" && m.ProviderId != f.ProviderId,
+		f.EntityType != "" && m.EntityType != f.EntityType,
+		f.EntityId != "" && m.EntityId != f.EntityId,
+		f.ActorUID != "" && m.ActorUID != f.ActorUID,
+		!f.From.IsZero() && m.CreatedAt.Before(f.From),
+		!f.To.IsZero() && !m.CreatedAt.Before(f.To):
+		return false
+	}
+	return true
+}
+
+func copyDiff(diff map[string]domain.AuditChange) map[string]domain.AuditChange {
+	if diff == nil {
+		return nil
+	}
+	out := make(map[string]domain.AuditChange, len(diff))
+	for k, v := range diff {
+		out[k] = v
+	}
+	return out
+}
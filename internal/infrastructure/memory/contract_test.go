@@ -0,0 +1,23 @@
+package memory
+
+import (
+	"testing"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/domain/repotest"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	store := NewStore()
+	repotest.Run(t, repotest.Factory{
+		Appointments: func(t *testing.T) domain.AppointmentsRepository { return NewAppointmentsRepository(store) },
+		Services:     func(t *testing.T) domain.ServicesRepository { return NewServicesRepository(store) },
+		Providers:    func(t *testing.T) domain.ProvidersRepository { return NewProvidersRepository(store) },
+		Schedules:    func(t *testing.T) domain.SchedulesRepository { return NewSchedulesRepository(store) },
+		Users:        func(t *testing.T) domain.UsersRepository { return NewUsersRepository(store) },
+		Audit:        func(t *testing.T) domain.AuditRepository { return NewAuditRepository(store) },
+		Holds:        func(t *testing.T) domain.HoldsRepository { return NewHoldsRepository(store) },
+		Idempotency:  func(t *testing.T) domain.IdempotencyStore { return NewIdempotencyStore(store) },
+		RateLimits:   func(t *testing.T) domain.RateLimitStore { return NewRateLimitStore(store) },
+	})
+}
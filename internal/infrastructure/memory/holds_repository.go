@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/utils"
+)
+
+type HoldsRepository struct {
+	store *Store
+}
+
+func NewHoldsRepository(store *Store) *HoldsRepository {
+	return &HoldsRepository{store: store}
+}
+
+func (r *HoldsRepository) Create(ctx context.Context, hold *domain.Hold) (string, error) {
+	hold.CreatedAt = utils.Now()
+	id := docid.New()
+	m := *hold
+	m.ID = id
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	start := m.ScheduledAt
+	end := start.Add(time.Duration(m.DurationMinutes) * time.Minute)
+	for _, other := range r.store.holds {
+		if other.ProviderId != m.ProviderId || other.Expired(m.CreatedAt) {
+			continue
+		}
+		otherEnd := other.ScheduledAt.Add(time.Duration(other.DurationMinutes) * time.Minute)
+		if start.Before(otherEnd) && end.After(other.ScheduledAt) {
+			return "", domain.ErrSlotTaken
+		}
+	}
+	r.store.holds[id] = m
+	return id, nil
+}
+
+func (r *HoldsRepository) Get(ctx context.Context, id string) (*domain.Hold, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	m, ok := r.store.holds[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &m, nil
+}
+
+func (r *HoldsRepository) ListByDate(ctx context.Context, date time.Time, providerId string, now time.Time) ([]*domain.Hold, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	r.store.mu.RLock()
+	var results []*domain.Hold
+	for _, m := range r.store.holds {
+		if m.ProviderId != providerId || m.Expired(now) {
+			continue
+		}
+		if m.ScheduledAt.Before(startOfDay) || !m.ScheduledAt.Before(endOfDay) {
+			continue
+		}
+		m := m
+		results = append(results, &m)
+	}
+	r.store.mu.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ScheduledAt.Before(results[j].ScheduledAt)
+	})
+	return results, nil
+}
+
+func (r *HoldsRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	delete(r.store.holds, id)
+	return nil
+}
+
+func (r *HoldsRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	n := 0
+	for id, m := range r.store.holds {
+		if m.ExpiresAt.Before(before) {
+			delete(r.store.holds, id)
+			n++
+		}
+	}
+	return n, nil
+}
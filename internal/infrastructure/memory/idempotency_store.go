@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+)
+
+type IdempotencyStore struct {
+	store *Store
+}
+
+func NewIdempotencyStore(store *Store) *IdempotencyStore {
+	return &IdempotencyStore{store: store}
+}
+
+func (r *IdempotencyStore) Start(ctx context.Context, rec *domain.IdempotencyRecord, now time.Time) (*domain.IdempotencyRecord, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if existing, ok := r.store.idempotency[rec.Key]; ok && existing.ExpiresAt.After(now) {
+		return copyIdempotencyRecord(existing), nil
+	}
+	r.store.idempotency[rec.Key] = *copyIdempotencyRecord(*rec)
+	return nil, nil
+}
+
+func (r *IdempotencyStore) Complete(ctx context.Context, rec *domain.IdempotencyRecord) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.idempotency[rec.Key] = *copyIdempotencyRecord(*rec)
+	return nil
+}
+
+func (r *IdempotencyStore) Delete(ctx context.Context, key string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	delete(r.store.idempotency, key)
+	return nil
+}
+
+func (r *IdempotencyStore) Purge(ctx context.Context, before time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	n := 0
+	for key, m := range r.store.idempotency {
+		if m.ExpiresAt.Before(before) {
+			delete(r.store.idempotency, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func copyIdempotencyRecord(m domain.IdempotencyRecord) *domain.IdempotencyRecord {
+	m.Body = append([]byte(nil), m.Body...)
+	return &m
+}
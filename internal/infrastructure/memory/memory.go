@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+)
+
+// Store keeps every collection in process memory behind a single lock. It is
+// meant for local development and tests: nothing survives a restart.
+// Repositories hand out copies, so callers can modify what they get without
+// touching the stored documents.
+type Store struct {
+	mu           sync.RWMutex
+	appointments map[string]domain.Appointments
+	services     map[string]domain.Services
+	providers    map[string]domain.Providers
+	schedules    map[string]domain.Schedule
+	users        map[string]domain.Users
+	audit        map[string]domain.AuditEntry
+	holds        map[string]domain.Hold
+	idempotency  map[string]domain.IdempotencyRecord
+	rateLimits   map[string]rateLimitCount
+}
+
+func NewStore() *Store {
+	return &Store{
+		appointments: make(map[string]domain.Appointments),
+		services:     make(map[string]domain.Services),
+		providers:    make(map[string]domain.Providers),
+		schedules:    make(map[string]domain.Schedule),
+		users:        make(map[string]domain.Users),
+		audit:        make(map[string]domain.AuditEntry),
+		holds:        make(map[string]domain.Hold),
+		idempotency:  make(map[string]domain.IdempotencyRecord),
+		rateLimits:   make(map[string]rateLimitCount),
+	}
+}
+
+// Seed is the format of the seed file: one array per collection, with the
+// same JSON fields the API uses. Documents without an id get a random one.
+type Seed struct {
+	Users        []domain.Users        `json:"users"`
+	Providers    []domain.Providers    `json:"providers"`
+	Services     []domain.Services     `json:"services"`
+	Schedules    []domain.Schedule     `json:"schedules"`
+	Appointments []domain.Appointments `json:"appointments"`
+}
+
+// LoadSeedFile reads a Seed from a JSON file into the store.
+func (s *Store) LoadSeedFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening seed file: %v", err)
+	}
+	defer f.Close()
+	return s.LoadSeed(f)
+}
+
+// LoadSeed reads a Seed from r. Documents are stored as given, without the
+// overlap check, and replace documents with the same ID.
+func (s *Store) LoadSeed(r io.Reader) error {
+	var seed Seed
+	if err := json.NewDecoder(r).Decode(&seed); err != nil {
+		return fmt.Errorf("error decoding seed: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range seed.Users {
+		s.users[idOrNew(&m.ID)] = m
+	}
+	for _, m := range seed.Providers {
+		s.providers[idOrNew(&m.ID)] = m
+	}
+	for _, m := range seed.Services {
+		s.services[idOrNew(&m.ID)] = m
+	}
+	for _, m := range seed.Schedules {
+		idOrNew(&m.ID)
+		s.schedules[scheduleKey(m.ProviderId, m.Type)] = m
+	}
+	for _, m := range seed.Appointments {
+		s.appointments[idOrNew(&m.ID)] = m
+	}
+	return nil
+}
+
+func idOrNew(id *string) string {
+	if *id == "" {
+		*id = docid.New()
+	}
+	return *id
+}
+
+// pageByID sorts items by ID and returns the page after the cursor, with the
+// cursor of the next page when there is one.
+func pageByID[T any](items []*T, id func(*T) string, opts domain.ListOptions) ([]*T, string, error) {
+	after, err := pagination.Decode(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	sort.Slice(items, func(i, j int) bool { return id(items[i]) < id(items[j]) })
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(items), func(i int) bool { return id(items[i]) > after.ID })
+	}
+	items = items[start:]
+
+	limit := opts.PageSize()
+	if len(items) <= limit {
+		return items, "", nil
+	}
+	items = items[:limit]
+	return items, pagination.Encode(pagination.Cursor{ID: id(items[limit-1])}), nil
+}
+
+// purge removes the documents soft-deleted before the cutoff.
+func purge[T any](docs map[string]T, deletedAt func(T) *time.Time, before time.Time) int {
+	purged := 0
+	for id, m := range docs {
+		if d := deletedAt(m); d != nil && d.Before(before) {
+			delete(docs, id)
+			purged++
+		}
+	}
+	return purged
+}
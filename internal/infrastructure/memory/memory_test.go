@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSeedFile(t *testing.T) {
+	store := NewStore()
+	require.NoError(t, store.LoadSeedFile("testdata/seed.json"))
+	ctx := context.Background()
+
+	provider, err := NewProvidersRepository(store).GetByUserId(ctx, "mock-user-id")
+	require.NoError(t, err)
+	assert.Equal(t, "prov-demo", provider.ID)
+
+	services, _, err := NewServicesRepository(store).List(ctx, domain.ListOptions{}, "prov-demo")
+	require.NoError(t, err)
+	assert.Len(t, services, 2)
+
+	schedule, err := NewSchedulesRepository(store).GetByProvider(ctx, "prov-demo", domain.ScheduleTypeGlobal)
+	require.NoError(t, err)
+	assert.NotEmpty(t, schedule.ID)
+	assert.True(t, schedule.Days["mon"].Enabled)
+
+	appt, err := NewAppointmentsRepository(store).Get(ctx, "appt-demo")
+	require.NoError(t, err)
+	assert.Equal(t, "juan@example.com", appt.CustomerEmail)
+}
+
+func TestAppointmentsRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAppointmentsRepository(NewStore())
+
+	base := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+	id, err := repo.Create(ctx, &domain.Appointments{ProviderId: "p1", ScheduledAt: base, DurationMinutes: 30, Status: domain.StatusConfirmed})
+	require.NoError(t, err)
+
+	t.Run("returns copies", func(t *testing.T) {
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		got.Status = domain.StatusCancelled
+
+		again, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusConfirmed, again.Status)
+	})
+
+	t.Run("rejects overlaps", func(t *testing.T) {
+		_, err := repo.Create(ctx, &domain.Appointments{ProviderId: "p1", ScheduledAt: base.Add(15 * time.Minute), DurationMinutes: 30})
+		assert.ErrorIs(t, err, domain.ErrSlotTaken)
+
+		_, err = repo.Create(ctx, &domain.Appointments{ProviderId: "p2", ScheduledAt: base, DurationMinutes: 30})
+		assert.NoError(t, err)
+	})
+
+	t.Run("concurrent bookings of one slot", func(t *testing.T) {
+		slot := base.Add(2 * time.Hour)
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		created := 0
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := repo.Create(ctx, &domain.Appointments{ProviderId: "p1", ScheduledAt: slot, DurationMinutes: 30}); err == nil {
+					mu.Lock()
+					created++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 1, created)
+	})
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/utils"
+)
+
+type ProvidersRepository struct {
+	store *Store
+}
+
+func NewProvidersRepository(store *Store) *ProvidersRepository {
+	return &ProvidersRepository{store: store}
+}
+
+func (r *ProvidersRepository) List(ctx context.Context, opts domain.ListOptions) ([]*domain.Providers, string, error) {
+	r.store.mu.RLock()
+	results := make([]*domain.Providers, 0, len(r.store.providers))
+	for _, m := range r.store.providers {
+		if !opts.IncludeDeleted && m.DeletedAt != nil {
+			continue
+		}
+		m := m
+		results = append(results, &m)
+	}
+	r.store.mu.RUnlock()
+
+	return pageByID(results, func(m *domain.Providers) string { return m.ID }, opts)
+}
+
+func (r *ProvidersRepository) Get(ctx context.Context, id string) (*domain.Providers, error) {
+	m, err := r.GetIncludingDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.DeletedAt != nil {
+		return nil, domain.ErrNotFound
+	}
+	return m, nil
+}
+
+func (r *ProvidersRepository) GetIncludingDeleted(ctx context.Context, id string) (*domain.Providers, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	m, ok := r.store.providers[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &m, nil
+}
+
+// GetByUserId returns the oldest live provider of the user, or nil when the
+// user has none.
+func (r *ProvidersRepository) GetByUserId(ctx context.Context, userId string) (*domain.Providers, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var found *domain.Providers
+	for _, m := range r.store.providers {
+		if m.UserId != userId || m.DeletedAt != nil {
+			continue
+		}
+		if found == nil || m.CreatedAt.Before(found.CreatedAt) {
+			m := m
+			found = &m
+		}
+	}
+	return found, nil
+}
+
+func (r *ProvidersRepository) GetBySlug(ctx context.Context, slug string) (*domain.Providers, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, m := range r.store.providers {
+		if m.Slug == slug && m.DeletedAt == nil {
+			return &m, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *ProvidersRepository) Search(ctx context.Context, filter domain.ProviderFilter) ([]*domain.Providers, string, error) {
+	r.store.mu.RLock()
+	var results []*domain.Providers
+	for _, m := range r.store.providers {
+		if m.DeletedAt != nil {
+			continue
+		}
+		if filter.City != "" && m.City != filter.City {
+			continue
+		}
+		if filter.BusinessType != "" && m.BusinessType != filter.BusinessType {
+			continue
+		}
+		m := m
+		results = append(results, &m)
+	}
+	r.store.mu.RUnlock()
+
+	return pageByID(results, func(m *domain.Providers) string { return m.ID }, filter.ListOptions)
+}
+
+func (r *ProvidersRepository) ListByGeohash(ctx context.Context, prefix string) ([]*domain.Providers, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var results []*domain.Providers
+	for _, m := range r.store.providers {
+		if m.DeletedAt != nil || m.Geohash == "" || !strings.HasPrefix(m.Geohash, prefix) {
+			continue
+		}
+		m := m
+		results = append(results, &m)
+	}
+	return results, nil
+}
+
+func (r *ProvidersRepository) Create(ctx context.Context, model *domain.Providers) (string, error) {
+	now := utils.Now()
+	model.CreatedAt = now
+	model.UpdatedAt = now
+	id := docid.New()
+	r.save(id, model)
+	return id, nil
+}
+
+func (r *ProvidersRepository) Update(ctx context.Context, id string, m *domain.Providers) error {
+	m.UpdatedAt = utils.Now()
+	r.save(id, m)
+	return nil
+}
+
+func (r *ProvidersRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	delete(r.store.providers, id)
+	return nil
+}
+
+func (r *ProvidersRepository) save(id string, m *domain.Providers) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored := *m
+	stored.ID = id
+	r.store.providers[id] = stored
+}
+
+func (r *ProvidersRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return purge(r.store.providers, func(m domain.Providers) *time.Time { return m.DeletedAt }, deletedBefore), nil
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+)
+
+type rateLimitCount struct {
+	count   int
+	resetAt time.Time
+}
+
+// RateLimitStore counts in process memory, so with several instances each
+// one enforces the limits on its own.
+type RateLimitStore struct {
+	store *Store
+}
+
+func NewRateLimitStore(store *Store) *RateLimitStore {
+	return &RateLimitStore{store: store}
+}
+
+func (r *RateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	now := utils.Now()
+	resetAt := domain.WindowStart(now, window).Add(window)
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	// Drop finished windows so keys seen once, like most client IPs, do not
+	// pile up.
+	for k, c := range r.store.rateLimits {
+		if !c.resetAt.After(now) {
+			delete(r.store.rateLimits, k)
+		}
+	}
+	c := r.store.rateLimits[key]
+	if !c.resetAt.Equal(resetAt) {
+		c = rateLimitCount{resetAt: resetAt}
+	}
+	c.count++
+	r.store.rateLimits[key] = c
+	return c.count, resetAt, nil
+}
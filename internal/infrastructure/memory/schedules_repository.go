@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"maps"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/utils"
+)
+
+type SchedulesRepository struct {
+	store *Store
+}
+
+func NewSchedulesRepository(store *Store) *SchedulesRepository {
+	return &SchedulesRepository{store: store}
+}
+
+func scheduleKey(providerId string, scheduleType domain.ScheduleType) string {
+	return providerId + "/" + string(scheduleType)
+}
+
+func (r *SchedulesRepository) GetByProvider(ctx context.Context, providerID string, scheduleType domain.ScheduleType) (*domain.Schedule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	s, ok := r.store.schedules[scheduleKey(providerID, scheduleType)]
+	if !ok {
+		return nil, nil
+	}
+	s.Days = maps.Clone(s.Days)
+	return &s, nil
+}
+
+// Upsert keeps a single schedule per provider and type. The ID and creation
+// time of an existing schedule are preserved.
+func (r *SchedulesRepository) Upsert(ctx context.Context, schedule *domain.Schedule) error {
+	if schedule.ProviderId == "" {
+		return errors.New("provider_id is required")
+	}
+
+	now := utils.Now()
+	key := scheduleKey(schedule.ProviderId, schedule.Type)
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if existing, ok := r.store.schedules[key]; ok {
+		schedule.ID = existing.ID
+		schedule.CreatedAt = existing.CreatedAt
+	} else {
+		schedule.ID = docid.New()
+		schedule.CreatedAt = now
+	}
+	schedule.UpdatedAt = now
+	stored := *schedule
+	stored.Days = maps.Clone(schedule.Days)
+	r.store.schedules[key] = stored
+	return nil
+}
@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"ServiceBookingApp/internal/utils"
+)
+
+type ServicesRepository struct {
+	store *Store
+}
+
+func NewServicesRepository(store *Store) *ServicesRepository {
+	return &ServicesRepository{store: store}
+}
+
+func (r *ServicesRepository) List(ctx context.Context, opts domain.ListOptions, providerId string) ([]*domain.Services, string, error) {
+	r.store.mu.RLock()
+	var results []*domain.Services
+	for _, m := range r.store.services {
+		if providerId != "" && m.ProviderId != providerId {
+			continue
+		}
+		if !opts.IncludeDeleted && m.DeletedAt != nil {
+			continue
+		}
+		m := m
+		results = append(results, &m)
+	}
+	r.store.mu.RUnlock()
+
+	after, err := pagination.Decode(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if after != nil && after.SortOrder == nil {
+		return nil, "", domain.ErrInvalidCursor
+	}
+	sort.Slice(results, func(i, j int) bool { return servicesBefore(results[i], results[j]) })
+
+	start := 0
+	if after != nil {
+		last := &domain.Services{ID: after.ID, SortOrder: *after.SortOrder}
+		start = sort.Search(len(results), func(i int) bool { return servicesBefore(last, results[i]) })
+	}
+	results = results[start:]
+
+	limit := opts.PageSize()
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	last := results[limit-1]
+	return results, pagination.Encode(pagination.Cursor{SortOrder: &last.SortOrder, ID: last.ID}), nil
+}
+
+// servicesBefore is the order of listings: by SortOrder, then by ID.
+func servicesBefore(a, b *domain.Services) bool {
+	if a.SortOrder != b.SortOrder {
+		return a.SortOrder < b.SortOrder
+	}
+	return a.ID < b.ID
+}
+
+func (r *ServicesRepository) Get(ctx context.Context, id string) (*domain.Services, error) {
+	m, err := r.GetIncludingDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.DeletedAt != nil {
+		return nil, domain.ErrNotFound
+	}
+	return m, nil
+}
+
+func (r *ServicesRepository) GetIncludingDeleted(ctx context.Context, id string) (*domain.Services, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	m, ok := r.store.services[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &m, nil
+}
+
+func (r *ServicesRepository) Create(ctx context.Context, model *domain.Services) (string, error) {
+	now := utils.Now()
+	model.CreatedAt = now
+	model.UpdatedAt = now
+	id := docid.New()
+	r.save(id, model)
+	return id, nil
+}
+
+func (r *ServicesRepository) Update(ctx context.Context, id string, m *domain.Services) error {
+	m.UpdatedAt = utils.Now()
+	r.save(id, m)
+	return nil
+}
+
+func (r *ServicesRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	delete(r.store.services, id)
+	return nil
+}
+
+func (r *ServicesRepository) Reorder(ctx context.Context, providerId string, ids []string) ([]*domain.Services, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var services []*domain.Services
+	for _, m := range r.store.services {
+		if m.ProviderId == providerId && m.DeletedAt == nil {
+			m := m
+			services = append(services, &m)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool { return servicesBefore(services[i], services[j]) })
+
+	ordered, changed, err := domain.ReorderServices(services, ids)
+	if err != nil {
+		return nil, err
+	}
+	now := utils.Now()
+	for _, m := range changed {
+		m.UpdatedAt = now
+		r.store.services[m.ID] = *m
+	}
+	return ordered, nil
+}
+
+// Import upserts on (provider_id, external_id); rows already present keep
+// their ID.
+func (r *ServicesRepository) Import(ctx context.Context, models []*domain.Services) error {
+	now := utils.Now()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, m := range models {
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = now
+		}
+		m.UpdatedAt = now
+		m.ID = docid.New()
+		for id, existing := range r.store.services {
+			if existing.ProviderId == m.ProviderId && existing.ExternalId == m.ExternalId {
+				m.ID = id
+				break
+			}
+		}
+		r.store.services[m.ID] = *m
+	}
+	return nil
+}
+
+func (r *ServicesRepository) save(id string, m *domain.Services) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored := *m
+	stored.ID = id
+	r.store.services[id] = stored
+}
+
+func (r *ServicesRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return purge(r.store.services, func(m domain.Services) *time.Time { return m.DeletedAt }, deletedBefore), nil
+}
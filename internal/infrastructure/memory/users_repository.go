@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/utils"
+)
+
+type UsersRepository struct {
+	store *Store
+}
+
+func NewUsersRepository(store *Store) *UsersRepository {
+	return &UsersRepository{store: store}
+}
+
+func (r *UsersRepository) List(ctx context.Context, opts domain.ListOptions) ([]*domain.Users, string, error) {
+	r.store.mu.RLock()
+	results := make([]*domain.Users, 0, len(r.store.users))
+	for _, m := range r.store.users {
+		m := m
+		results = append(results, &m)
+	}
+	r.store.mu.RUnlock()
+
+	return pageByID(results, func(m *domain.Users) string { return m.ID }, opts)
+}
+
+func (r *UsersRepository) Get(ctx context.Context, id string) (*domain.Users, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	m, ok := r.store.users[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &m, nil
+}
+
+func (r *UsersRepository) Create(ctx context.Context, model *domain.Users) (string, error) {
+	now := utils.Now()
+	model.CreatedAt = now
+	model.UpdatedAt = now
+	id := docid.New()
+	r.save(id, model)
+	return id, nil
+}
+
+// Update creates the user when it does not exist yet; users are keyed by
+// their Firebase UID and first written on login.
+func (r *UsersRepository) Update(ctx context.Context, id string, m *domain.Users) error {
+	m.UpdatedAt = utils.Now()
+	r.save(id, m)
+	return nil
+}
+
+func (r *UsersRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	delete(r.store.users, id)
+	return nil
+}
+
+func (r *UsersRepository) save(id string, m *domain.Users) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored := *m
+	stored.ID = id
+	r.store.users[id] = stored
+}
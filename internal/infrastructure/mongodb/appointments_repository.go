@@ -0,0 +1,254 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"ServiceBookingApp/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// appointmentDoc adds the computed end of the appointment, which the
+// overlap check queries on.
+type appointmentDoc struct {
+	domain.Appointments `bson:",inline"`
+	EndsAt              time.Time `bson:"ends_at"`
+}
+
+func newAppointmentDoc(id string, m *domain.Appointments) *appointmentDoc {
+	doc := &appointmentDoc{Appointments: *m}
+	doc.ID = id
+	doc.EndsAt = m.ScheduledAt.Add(time.Duration(m.DurationMinutes) * time.Minute)
+	return doc
+}
+
+type AppointmentsRepository struct {
+	db *MongoDB
+}
+
+func NewAppointmentsRepository(db *MongoDB) *AppointmentsRepository {
+	return &AppointmentsRepository{db: db}
+}
+
+func (r *AppointmentsRepository) collection() *mongo.Collection {
+	return r.db.db.Collection("appointments")
+}
+
+func (r *AppointmentsRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*domain.Appointments, error) {
+	cur, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var results []*domain.Appointments
+	for cur.Next(ctx) {
+		var doc appointmentDoc
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		m := doc.Appointments
+		results = append(results, &m)
+	}
+	return results, cur.Err()
+}
+
+func (r *AppointmentsRepository) ListByDate(ctx context.Context, date time.Time, providerId string) ([]*domain.Appointments, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	filter := bson.M{"scheduled_at": bson.M{"$gte": startOfDay, "$lt": endOfDay}, "deleted_at": nil}
+	if providerId != "" {
+		filter["provider_id"] = providerId
+	}
+	return r.find(ctx, filter, options.Find().SetSort(bson.D{{Key: "scheduled_at", Value: 1}, {Key: "_id", Value: 1}}))
+}
+
+func (r *AppointmentsRepository) List(ctx context.Context, f domain.AppointmentsFilter) ([]*domain.Appointments, string, error) {
+	after, err := pagination.Decode(f.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var and []bson.M
+	if !f.IncludeDeleted {
+		and = append(and, bson.M{"deleted_at": nil})
+	}
+	if f.ProviderId != "" {
+		and = append(and, bson.M{"provider_id": f.ProviderId})
+	}
+	if f.Status != "" {
+		and = append(and, bson.M{"status": f.Status})
+	}
+	if f.ServiceId != "" {
+		and = append(and, bson.M{"service_id": f.ServiceId})
+	}
+	if f.Customer != "" {
+		and = append(and, bson.M{"customer_email": f.Customer})
+	}
+
+	now := utils.Now()
+	direction, cmp := -1, "$lt"
+	if f.Type == "upcoming" {
+		and = append(and, bson.M{"scheduled_at": bson.M{"$gte": now}})
+		direction, cmp = 1, "$gt"
+	} else if f.Type == "past" {
+		and = append(and, bson.M{"scheduled_at": bson.M{"$lt": now}})
+	}
+	if !f.From.IsZero() {
+		and = append(and, bson.M{"scheduled_at": bson.M{"$gte": f.From}})
+	}
+	if !f.To.IsZero() {
+		and = append(and, bson.M{"scheduled_at": bson.M{"$lt": f.To}})
+	}
+	if after != nil {
+		if after.ScheduledAt == nil {
+			return nil, "", domain.ErrInvalidCursor
+		}
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{"scheduled_at": bson.M{cmp: *after.ScheduledAt}},
+			bson.M{"scheduled_at": *after.ScheduledAt, "_id": bson.M{cmp: after.ID}},
+		}})
+	}
+
+	filter := bson.M{}
+	if len(and) > 0 {
+		filter["$and"] = and
+	}
+	limit := f.PageSize()
+	opts := options.Find().
+		SetSort(bson.D{{Key: "scheduled_at", Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(int64(limit + 1))
+
+	results, err := r.find(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	last := results[limit-1]
+	return results, pagination.Encode(pagination.Cursor{ScheduledAt: &last.ScheduledAt, ID: last.ID}), nil
+}
+
+func (r *AppointmentsRepository) Get(ctx context.Context, id string) (*domain.Appointments, error) {
+	return r.findOne(ctx, bson.M{"_id": id, "deleted_at": nil})
+}
+
+func (r *AppointmentsRepository) GetIncludingDeleted(ctx context.Context, id string) (*domain.Appointments, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *AppointmentsRepository) findOne(ctx context.Context, filter bson.M) (*domain.Appointments, error) {
+	var doc appointmentDoc
+	if err := r.collection().FindOne(ctx, filter).Decode(&doc); err != nil {
+		return nil, translateError(err)
+	}
+	return &doc.Appointments, nil
+}
+
+func (r *AppointmentsRepository) Create(ctx context.Context, model *domain.Appointments) (string, error) {
+	now := utils.Now()
+	model.CreatedAt = now
+	model.UpdatedAt = now
+	id := docid.New()
+	if err := r.save(ctx, id, model); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *AppointmentsRepository) Update(ctx context.Context, id string, m *domain.Appointments) error {
+	m.UpdatedAt = utils.Now()
+	return r.save(ctx, id, m)
+}
+
+func (r *AppointmentsRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// Import upserts imported appointments by an ID derived from
+// (provider_id, external_id). Imports are historical, so they skip the
+// overlap check.
+func (r *AppointmentsRepository) Import(ctx context.Context, models []*domain.Appointments) error {
+	if len(models) == 0 {
+		return nil
+	}
+	now := utils.Now()
+	writes := make([]mongo.WriteModel, 0, len(models))
+	for _, m := range models {
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = now
+		}
+		m.UpdatedAt = now
+		m.ID = docid.Import(m.ProviderId, m.ExternalId)
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": m.ID}).
+			SetReplacement(newAppointmentDoc(m.ID, m)).
+			SetUpsert(true))
+	}
+	_, err := r.collection().BulkWrite(ctx, writes)
+	return err
+}
+
+// save replaces the document as a whole, like Firestore's Set. It runs in a
+// transaction that first bumps a per-provider lock document, so concurrent
+// bookings of the same provider conflict and are retried one after the
+// other, and then rejects the write with domain.ErrSlotTaken when a live
+// appointment overlaps it.
+func (r *AppointmentsRepository) save(ctx context.Context, id string, m *domain.Appointments) error {
+	doc := newAppointmentDoc(id, m)
+
+	session, err := r.db.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if err := r.db.lockProvider(sc, m.ProviderId); err != nil {
+			return nil, err
+		}
+
+		if m.DeletedAt == nil && m.Status != domain.StatusCancelled {
+			overlapping, err := r.collection().CountDocuments(sc, bson.M{
+				"provider_id":  m.ProviderId,
+				"_id":          bson.M{"$ne": id},
+				"deleted_at":   nil,
+				"status":       bson.M{"$ne": domain.StatusCancelled},
+				"scheduled_at": bson.M{"$lt": doc.EndsAt},
+				"ends_at":      bson.M{"$gt": m.ScheduledAt},
+			}, options.Count().SetLimit(1))
+			if err != nil {
+				return nil, err
+			}
+			if overlapping > 0 {
+				return nil, domain.ErrSlotTaken
+			}
+		}
+
+		_, err = r.collection().ReplaceOne(sc, bson.M{"_id": id}, doc, upsert)
+		return nil, err
+	})
+	return err
+}
+
+// Purge hard-deletes the appointments soft-deleted before deletedBefore,
+// except cancelled ones: their DeletedAt marks the cancellation, which stats
+// and exports still report.
+func (r *AppointmentsRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	res, err := r.collection().DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$lt": deletedBefore}, "status": bson.M{"$ne": domain.StatusCancelled}})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.DeletedCount), nil
+}
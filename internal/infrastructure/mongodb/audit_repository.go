@@ -0,0 +1,117 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"ServiceBookingApp/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditRepository struct {
+	db *MongoDB
+}
+
+func NewAuditRepository(db *MongoDB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) collection() *mongo.Collection {
+	return r.db.db.Collection("audit")
+}
+
+func (r *AuditRepository) Create(ctx context.Context, entry *domain.AuditEntry) (string, error) {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = utils.Now()
+	}
+	m := *entry
+	m.ID = docid.New()
+	if _, err := r.collection().InsertOne(ctx, &m); err != nil {
+		return "", err
+	}
+	return m.ID, nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, f domain.AuditFilter) ([]*domain.AuditEntry, string, error) {
+	after, err := pagination.Decode(f.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var and []bson.M
+	if f.ProviderId != "" {
+		and = append(and, bson.M{"provider_id": f.ProviderId})
+	}
+	if f.EntityType != "" {
+		and = append(and, bson.M{"entity_type": f.EntityType})
+	}
+	if f.EntityId != "" {
+		and = append(and, bson.M{"entity_id": f.EntityId})
+	}
+	if f.ActorUID != "" {
+		and = append(and, bson.M{"actor_uid": f.ActorUID})
+	}
+	if !f.From.IsZero() {
+		and = append(and, bson.M{"created_at": bson.M{"$gte": f.From}})
+	}
+	if !f.To.IsZero() {
+		and = append(and, bson.M{"created_at": bson.M{"$lt": f.To}})
+	}
+	if after != nil {
+		if after.ScheduledAt == nil {
+			return nil, "", domain.ErrInvalidCursor
+		}
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{"created_at": bson.M{"$lt": *after.ScheduledAt}},
+			bson.M{"created_at": *after.ScheduledAt, "_id": bson.M{"$lt": after.ID}},
+		}})
+	}
+
+	filter := bson.M{}
+	if len(and) > 0 {
+		filter["$and"] = and
+	}
+	limit := f.PageSize()
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit + 1))
+
+	cur, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cur.Close(ctx)
+
+	var results []*domain.AuditEntry
+	for cur.Next(ctx) {
+		var m domain.AuditEntry
+		if err := cur.Decode(&m); err != nil {
+			return nil, "", err
+		}
+		results = append(results, &m)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	last := results[limit-1]
+	return results, pagination.Encode(pagination.Cursor{ScheduledAt: &last.CreatedAt, ID: last.ID}), nil
+}
+
+func (r *AuditRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	res, err := r.collection().DeleteMany(ctx, bson.M{"created_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.DeletedCount), nil
+}
@@ -0,0 +1,26 @@
+package mongodb
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxHoldDuration bounds how long before a slot an overlapping hold can
+// start. Holds store no end, so the overlap check looks back that far and
+// compares the ends in code.
+const maxHoldDuration = 24 * time.Hour
+
+// lockProvider bumps the provider's document in "booking_locks" within the
+// transaction of sc. Transactions that book for the same provider then
+// conflict on it and are retried one after the other, so two of them cannot
+// both find the same slot free.
+func (db *MongoDB) lockProvider(sc mongo.SessionContext, providerId string) error {
+	_, err := db.db.Collection("booking_locks").UpdateOne(sc,
+		bson.M{"_id": providerId},
+		bson.M{"$inc": bson.M{"version": 1}},
+		options.Update().SetUpsert(true))
+	return err
+}
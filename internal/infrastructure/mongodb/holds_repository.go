@@ -0,0 +1,126 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// HoldsRepository relies on a TTL index on expires_at as well as on Purge
+// to remove expired holds.
+type HoldsRepository struct {
+	db *MongoDB
+}
+
+func NewHoldsRepository(db *MongoDB) *HoldsRepository {
+	return &HoldsRepository{db: db}
+}
+
+func (r *HoldsRepository) collection() *mongo.Collection {
+	return r.db.db.Collection("holds")
+}
+
+// Create runs in a transaction that locks the provider like
+// AppointmentsRepository.save, so a live overlapping hold is seen by the
+// check rather than inserted next to it.
+func (r *HoldsRepository) Create(ctx context.Context, hold *domain.Hold) (string, error) {
+	hold.CreatedAt = utils.Now()
+	m := *hold
+	m.ID = docid.New()
+
+	session, err := r.db.client.StartSession()
+	if err != nil {
+		return "", err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if err := r.db.lockProvider(sc, m.ProviderId); err != nil {
+			return nil, err
+		}
+
+		end := m.ScheduledAt.Add(time.Duration(m.DurationMinutes) * time.Minute)
+		cur, err := r.collection().Find(sc, bson.M{
+			"provider_id":  m.ProviderId,
+			"expires_at":   bson.M{"$gt": m.CreatedAt},
+			"scheduled_at": bson.M{"$gt": m.ScheduledAt.Add(-maxHoldDuration), "$lt": end},
+		})
+		if err != nil {
+			return nil, err
+		}
+		defer cur.Close(sc)
+		for cur.Next(sc) {
+			var other domain.Hold
+			if err := cur.Decode(&other); err != nil {
+				return nil, err
+			}
+			if other.ScheduledAt.Add(time.Duration(other.DurationMinutes) * time.Minute).After(m.ScheduledAt) {
+				return nil, domain.ErrSlotTaken
+			}
+		}
+		if err := cur.Err(); err != nil {
+			return nil, err
+		}
+
+		_, err = r.collection().InsertOne(sc, &m)
+		return nil, err
+	})
+	if err != nil {
+		return "", err
+	}
+	return m.ID, nil
+}
+
+func (r *HoldsRepository) Get(ctx context.Context, id string) (*domain.Hold, error) {
+	var m domain.Hold
+	if err := r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&m); err != nil {
+		return nil, translateError(err)
+	}
+	return &m, nil
+}
+
+func (r *HoldsRepository) ListByDate(ctx context.Context, date time.Time, providerId string, now time.Time) ([]*domain.Hold, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	filter := bson.M{
+		"provider_id":  providerId,
+		"scheduled_at": bson.M{"$gte": startOfDay, "$lt": endOfDay},
+		"expires_at":   bson.M{"$gt": now},
+	}
+	cur, err := r.collection().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "scheduled_at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var results []*domain.Hold
+	for cur.Next(ctx) {
+		var m domain.Hold
+		if err := cur.Decode(&m); err != nil {
+			return nil, err
+		}
+		results = append(results, &m)
+	}
+	return results, cur.Err()
+}
+
+func (r *HoldsRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *HoldsRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	res, err := r.collection().DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.DeletedCount), nil
+}
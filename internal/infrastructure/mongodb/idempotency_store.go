@@ -0,0 +1,69 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IdempotencyStore struct {
+	db *MongoDB
+}
+
+func NewIdempotencyStore(db *MongoDB) *IdempotencyStore {
+	return &IdempotencyStore{db: db}
+}
+
+func (r *IdempotencyStore) collection() *mongo.Collection {
+	return r.db.db.Collection("idempotency_keys")
+}
+
+// Start replaces an expired record of the key or inserts a new one. When
+// the key is live the upsert collides with it on _id, and the live record
+// is read back.
+func (r *IdempotencyStore) Start(ctx context.Context, rec *domain.IdempotencyRecord, now time.Time) (*domain.IdempotencyRecord, error) {
+	_, err := r.collection().ReplaceOne(ctx,
+		bson.M{"_id": rec.Key, "expires_at": bson.M{"$lte": now}},
+		rec, options.Replace().SetUpsert(true))
+	if err == nil {
+		return nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+
+	var m domain.IdempotencyRecord
+	err = r.collection().FindOne(ctx, bson.M{"_id": rec.Key}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// Released in between: the key is free again.
+		return r.Start(ctx, rec, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *IdempotencyStore) Complete(ctx context.Context, rec *domain.IdempotencyRecord) error {
+	_, err := r.collection().ReplaceOne(ctx, bson.M{"_id": rec.Key}, rec, upsert)
+	return err
+}
+
+func (r *IdempotencyStore) Delete(ctx context.Context, key string) error {
+	_, err := r.collection().DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
+
+func (r *IdempotencyStore) Purge(ctx context.Context, before time.Time) (int, error) {
+	res, err := r.collection().DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.DeletedCount), nil
+}
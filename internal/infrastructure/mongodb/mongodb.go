@@ -0,0 +1,133 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"ServiceBookingApp/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+)
+
+const defaultDatabase = "servicebooking"
+
+// MongoDB holds the client and database shared by the repositories.
+type MongoDB struct {
+	client *mongo.Client
+	db     *mongo.Database
+}
+
+// NewMongoDB connects to uri, makes sure the indexes exist and normalizes
+// documents stored in an older shape. The database is the one named in the
+// URI path, or "servicebooking". Booking uses multi-document transactions,
+// so the server must be a replica set.
+func NewMongoDB(ctx context.Context, uri string) (*MongoDB, error) {
+	cs, err := connstring.ParseAndValidate(uri)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing mongo uri: %v", err)
+	}
+	dbName := cs.Database
+	if dbName == "" {
+		dbName = defaultDatabase
+	}
+	return connect(ctx, uri, dbName)
+}
+
+func connect(ctx context.Context, uri, dbName string) (*MongoDB, error) {
+	// Decode nested documents of free-form fields (audit snapshots, legacy
+	// notes and descriptions) as maps so they render as JSON objects
+	// instead of key/value arrays.
+	registry := bson.NewRegistry()
+	registry.RegisterTypeMapEntry(bsontype.EmbeddedDocument, reflect.TypeOf(map[string]interface{}{}))
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetRegistry(registry))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to mongo: %v", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(ctx)
+		return nil, fmt.Errorf("error connecting to mongo: %v", err)
+	}
+
+	m := &MongoDB{client: client, db: client.Database(dbName)}
+	if err := m.EnsureIndexes(ctx); err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+	if err := m.Normalize(ctx); err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *MongoDB) Close() {
+	m.client.Disconnect(context.Background())
+}
+
+func (m *MongoDB) Database() *mongo.Database {
+	return m.db
+}
+
+// EnsureIndexes creates the indexes every query of the repositories relies
+// on. CreateMany is a no-op for indexes that already exist.
+func (m *MongoDB) EnsureIndexes(ctx context.Context) error {
+	externalIdSet := options.Index().SetUnique(true).
+		SetPartialFilterExpression(bson.M{"external_id": bson.M{"$type": "string"}})
+
+	indexes := map[string][]mongo.IndexModel{
+		"appointments": {
+			{Keys: bson.D{{Key: "provider_id", Value: 1}, {Key: "scheduled_at", Value: 1}, {Key: "_id", Value: 1}}},
+			{Keys: bson.D{{Key: "provider_id", Value: 1}, {Key: "external_id", Value: 1}}, Options: externalIdSet},
+		},
+		"services": {
+			{Keys: bson.D{{Key: "provider_id", Value: 1}, {Key: "sort_order", Value: 1}, {Key: "_id", Value: 1}}},
+			{Keys: bson.D{{Key: "provider_id", Value: 1}, {Key: "external_id", Value: 1}}, Options: externalIdSet},
+		},
+		"providers": {
+			{Keys: bson.D{{Key: "user_id", Value: 1}}},
+			{Keys: bson.D{{Key: "slug", Value: 1}}},
+			{Keys: bson.D{{Key: "geohash", Value: 1}}},
+			{Keys: bson.D{{Key: "city", Value: 1}, {Key: "business_type", Value: 1}, {Key: "_id", Value: 1}}},
+		},
+		"schedules": {
+			{Keys: bson.D{{Key: "provider_id", Value: 1}, {Key: "type", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		"audit": {
+			{Keys: bson.D{{Key: "provider_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "created_at", Value: 1}}},
+		},
+		"holds": {
+			{Keys: bson.D{{Key: "provider_id", Value: 1}, {Key: "scheduled_at", Value: 1}}},
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"idempotency_keys": {
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}},
+		},
+		"rate_limits": {
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+	}
+
+	for collection, models := range indexes {
+		if _, err := m.db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return fmt.Errorf("error creating %s indexes: %v", collection, err)
+		}
+	}
+	return nil
+}
+
+var upsert = options.Replace().SetUpsert(true)
+
+func translateError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.ErrNotFound
+	}
+	return err
+}
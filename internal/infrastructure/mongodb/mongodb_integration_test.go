@@ -0,0 +1,202 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/domain/repotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// newTestDB connects to MONGO_TEST_URL and uses a fresh database that is
+// dropped when the test ends. Tests are skipped when the variable is unset,
+// e.g. MONGO_TEST_URL=mongodb://localhost:27017/?replicaSet=rs0
+func newTestDB(t *testing.T) *MongoDB {
+	t.Helper()
+	uri := os.Getenv("MONGO_TEST_URL")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URL not set")
+	}
+	ctx := context.Background()
+
+	db, err := connect(ctx, uri, fmt.Sprintf("test_%d", time.Now().UnixNano()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.db.Drop(context.Background())
+		db.Close()
+	})
+	return db
+}
+
+func TestAppointmentsRepositoryIntegration(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewAppointmentsRepository(db)
+
+	base := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+	first := &domain.Appointments{ProviderId: "p1", ServiceId: "s1", ScheduledAt: base, DurationMinutes: 30, Status: domain.StatusConfirmed, Notes: domain.Notes{Text: "hola"}}
+	id, err := repo.Create(ctx, first)
+	require.NoError(t, err)
+
+	got, err := repo.Get(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, id, got.ID)
+	assert.Equal(t, "hola", got.Notes.Text)
+	assert.True(t, got.ScheduledAt.Equal(base))
+
+	t.Run("transaction rejects overlaps", func(t *testing.T) {
+		overlap := &domain.Appointments{ProviderId: "p1", ServiceId: "s1", ScheduledAt: base.Add(15 * time.Minute), DurationMinutes: 30, Status: domain.StatusConfirmed}
+		_, err := repo.Create(ctx, overlap)
+		assert.ErrorIs(t, err, domain.ErrSlotTaken)
+
+		otherProvider := &domain.Appointments{ProviderId: "p2", ServiceId: "s1", ScheduledAt: base, DurationMinutes: 30, Status: domain.StatusConfirmed}
+		_, err = repo.Create(ctx, otherProvider)
+		assert.NoError(t, err)
+
+		adjacent := &domain.Appointments{ProviderId: "p1", ServiceId: "s1", ScheduledAt: base.Add(30 * time.Minute), DurationMinutes: 30, Status: domain.StatusConfirmed}
+		_, err = repo.Create(ctx, adjacent)
+		assert.NoError(t, err)
+
+		got.Notes.Text = "moved within its own slot"
+		assert.NoError(t, repo.Update(ctx, id, got))
+	})
+
+	t.Run("cancelled appointments free the slot", func(t *testing.T) {
+		got.Status = domain.StatusCancelled
+		require.NoError(t, repo.Update(ctx, id, got))
+
+		replacement := &domain.Appointments{ProviderId: "p1", ServiceId: "s1", ScheduledAt: base, DurationMinutes: 30, Status: domain.StatusConfirmed}
+		_, err := repo.Create(ctx, replacement)
+		assert.NoError(t, err)
+	})
+
+	t.Run("cursor pagination", func(t *testing.T) {
+		filter := domain.AppointmentsFilter{ListOptions: domain.ListOptions{Limit: 2}, ProviderId: "p1"}
+		var seen []string
+		for {
+			page, next, err := repo.List(ctx, filter)
+			require.NoError(t, err)
+			for _, a := range page {
+				seen = append(seen, a.ID)
+			}
+			if next == "" {
+				break
+			}
+			filter.Cursor = next
+		}
+		assert.Len(t, seen, 3)
+	})
+
+	t.Run("list by date", func(t *testing.T) {
+		results, err := repo.ListByDate(ctx, base, "p1")
+		require.NoError(t, err)
+		assert.Len(t, results, 3)
+	})
+
+	t.Run("import is idempotent", func(t *testing.T) {
+		models := func() []*domain.Appointments {
+			return []*domain.Appointments{{ProviderId: "p3", ServiceId: "s1", ExternalId: "ext-1", ScheduledAt: base.AddDate(-1, 0, 0), DurationMinutes: 30, Status: domain.StatusCompleted}}
+		}
+		require.NoError(t, repo.Import(ctx, models()))
+		require.NoError(t, repo.Import(ctx, models()))
+
+		page, _, err := repo.List(ctx, domain.AppointmentsFilter{ProviderId: "p3"})
+		require.NoError(t, err)
+		assert.Len(t, page, 1)
+	})
+}
+
+func TestServicesProvidersUsersIntegration(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	services := NewServicesRepository(db)
+	sid, err := services.Create(ctx, &domain.Services{ProviderId: "p1", Title: "Corte", DurationMinutes: 30, Price: 1500, Description: domain.Description{Markdown: "x", HTML: "<p>x</p>"}})
+	require.NoError(t, err)
+	s, err := services.Get(ctx, sid)
+	require.NoError(t, err)
+	assert.Equal(t, "x", s.Description.Markdown)
+
+	providers := NewProvidersRepository(db)
+	none, err := providers.GetByUserId(ctx, "nobody")
+	assert.NoError(t, err)
+	assert.Nil(t, none)
+	pid, err := providers.Create(ctx, &domain.Providers{UserId: "u1", EstablishmentName: "Barbería"})
+	require.NoError(t, err)
+	p, err := providers.GetByUserId(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, pid, p.ID)
+
+	users := NewUsersRepository(db)
+	active := true
+	require.NoError(t, users.Update(ctx, "uid-1", &domain.Users{Email: "a@b.c", IsActive: &active}))
+	u, err := users.Get(ctx, "uid-1")
+	require.NoError(t, err)
+	assert.Equal(t, "a@b.c", u.Email)
+	assert.True(t, *u.IsActive)
+}
+
+func TestSchedulesRepositoryIntegration(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewSchedulesRepository(db)
+
+	none, err := repo.GetByProvider(ctx, "p1", domain.ScheduleTypeGlobal)
+	assert.NoError(t, err)
+	assert.Nil(t, none)
+
+	schedule := &domain.Schedule{ProviderId: "p1", Type: domain.ScheduleTypeGlobal, Days: map[string]domain.DaySchedule{
+		"mon": {Enabled: true, Ranges: []domain.TimeRange{{Start: "09:00", End: "13:00"}}},
+	}}
+	require.NoError(t, repo.Upsert(ctx, schedule))
+	firstID := schedule.ID
+
+	again := &domain.Schedule{ProviderId: "p1", Type: domain.ScheduleTypeGlobal, Days: map[string]domain.DaySchedule{}}
+	require.NoError(t, repo.Upsert(ctx, again))
+	assert.Equal(t, firstID, again.ID)
+
+	got, err := repo.GetByProvider(ctx, "p1", domain.ScheduleTypeGlobal)
+	require.NoError(t, err)
+	assert.Empty(t, got.Days)
+}
+
+func TestRepositoryContractIntegration(t *testing.T) {
+	db := newTestDB(t)
+	repotest.Run(t, repotest.Factory{
+		Appointments: func(t *testing.T) domain.AppointmentsRepository { return NewAppointmentsRepository(db) },
+		Services:     func(t *testing.T) domain.ServicesRepository { return NewServicesRepository(db) },
+		Providers:    func(t *testing.T) domain.ProvidersRepository { return NewProvidersRepository(db) },
+		Schedules:    func(t *testing.T) domain.SchedulesRepository { return NewSchedulesRepository(db) },
+		Users:        func(t *testing.T) domain.UsersRepository { return NewUsersRepository(db) },
+		Audit:        func(t *testing.T) domain.AuditRepository { return NewAuditRepository(db) },
+		Holds:        func(t *testing.T) domain.HoldsRepository { return NewHoldsRepository(db) },
+		Idempotency:  func(t *testing.T) domain.IdempotencyStore { return NewIdempotencyStore(db) },
+		RateLimits:   func(t *testing.T) domain.RateLimitStore { return NewRateLimitStore(db) },
+	})
+}
+
+func TestNormalizeRichTextIntegration(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	_, err := db.db.Collection("services").InsertOne(ctx, bson.M{"_id": "s1", "provider_id": "p1", "title": "Corte", "description": "Corte **clásico**"})
+	require.NoError(t, err)
+	_, err = db.db.Collection("appointments").InsertOne(ctx, bson.M{"_id": "a1", "provider_id": "p1", "notes": bson.M{"text": "traer foto", "extra": 1}})
+	require.NoError(t, err)
+
+	require.NoError(t, db.NormalizeRichText(ctx))
+
+	s, err := NewServicesRepository(db).Get(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.Description{Markdown: "Corte **clásico**", HTML: "<p>Corte <strong>clásico</strong></p>\n"}, s.Description)
+	a, err := NewAppointmentsRepository(db).Get(ctx, "a1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.Notes{Text: "traer foto"}, a.Notes)
+}
@@ -0,0 +1,90 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"ServiceBookingApp/internal/richtext"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Normalize brings documents stored in an older shape up to date. Only
+// documents still in the old shape match its queries, so after the first
+// run it costs a few unindexed queries at startup.
+func (m *MongoDB) Normalize(ctx context.Context) error {
+	if err := m.NormalizeRichText(ctx); err != nil {
+		return err
+	}
+	// Services stored before they had a sort order would not match the
+	// listing's cursor filter.
+	_, err := m.db.Collection("services").UpdateMany(ctx,
+		bson.M{"sort_order": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"sort_order": 0}})
+	if err != nil {
+		return fmt.Errorf("error normalizing services sort_order: %v", err)
+	}
+	return m.backfillAppointmentPrice(ctx)
+}
+
+// backfillAppointmentPrice gives appointments stored before they kept their
+// price the current price of their service. Those whose service is gone get
+// 0, so they do not match again at the next startup.
+func (m *MongoDB) backfillAppointmentPrice(ctx context.Context) error {
+	appointments := m.db.Collection("appointments")
+	missing := bson.M{"price": bson.M{"$exists": false}}
+	serviceIds, err := appointments.Distinct(ctx, "service_id", missing)
+	if err != nil {
+		return fmt.Errorf("error normalizing appointments price: %v", err)
+	}
+	for _, id := range serviceIds {
+		var service struct {
+			Price float64 `bson:"price"`
+		}
+		err := m.db.Collection("services").FindOne(ctx, bson.M{"_id": id}).Decode(&service)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return fmt.Errorf("error normalizing appointments price: %v", err)
+		}
+		filter := bson.M{"service_id": id, "price": bson.M{"$exists": false}}
+		if _, err := appointments.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"price": service.Price}}); err != nil {
+			return fmt.Errorf("error normalizing appointments price: %v", err)
+		}
+	}
+	return nil
+}
+
+// NormalizeRichText rewrites the notes and descriptions stored as
+// free-form values before they were typed into domain.Notes and
+// domain.Description.
+func (m *MongoDB) NormalizeRichText(ctx context.Context) error {
+	err := m.normalizeField(ctx, "appointments", "notes", "notes.text",
+		func(v interface{}) interface{} { return richtext.LegacyNotes(v) })
+	if err != nil {
+		return err
+	}
+	return m.normalizeField(ctx, "services", "description", "description.html",
+		func(v interface{}) interface{} { return richtext.LegacyDescription(v) })
+}
+
+// normalizeField replaces the non-null values of field lacking the typed
+// marker subfield with their conversion.
+func (m *MongoDB) normalizeField(ctx context.Context, collection, field, marker string, convert func(interface{}) interface{}) error {
+	coll := m.db.Collection(collection)
+	filter := bson.M{field: bson.M{"$ne": nil}, marker: bson.M{"$exists": false}}
+	cursor, err := coll.Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("error normalizing %s %s: %v", collection, field, err)
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		update := bson.M{"$set": bson.M{field: convert(doc[field])}}
+		if _, err := coll.UpdateByID(ctx, doc["_id"], update); err != nil {
+			return fmt.Errorf("error normalizing %s %s: %v", collection, field, err)
+		}
+	}
+	return cursor.Err()
+}
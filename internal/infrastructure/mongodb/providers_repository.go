@@ -0,0 +1,167 @@
+package mongodb
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"ServiceBookingApp/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ProvidersRepository struct {
+	db *MongoDB
+}
+
+func NewProvidersRepository(db *MongoDB) *ProvidersRepository {
+	return &ProvidersRepository{db: db}
+}
+
+func (r *ProvidersRepository) collection() *mongo.Collection {
+	return r.db.db.Collection("providers")
+}
+
+func (r *ProvidersRepository) List(ctx context.Context, opts domain.ListOptions) ([]*domain.Providers, string, error) {
+	after, err := pagination.Decode(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filter := bson.M{}
+	if !opts.IncludeDeleted {
+		filter["deleted_at"] = nil
+	}
+	if after != nil {
+		filter["_id"] = bson.M{"$gt": after.ID}
+	}
+	limit := opts.PageSize()
+	cur, err := r.collection().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit+1)))
+	if err != nil {
+		return nil, "", err
+	}
+	var results []*domain.Providers
+	if err := cur.All(ctx, &results); err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	return results, pagination.Encode(pagination.Cursor{ID: results[limit-1].ID}), nil
+}
+
+func (r *ProvidersRepository) Get(ctx context.Context, id string) (*domain.Providers, error) {
+	return r.findOne(ctx, bson.M{"_id": id, "deleted_at": nil})
+}
+
+func (r *ProvidersRepository) GetIncludingDeleted(ctx context.Context, id string) (*domain.Providers, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *ProvidersRepository) findOne(ctx context.Context, filter bson.M) (*domain.Providers, error) {
+	var doc domain.Providers
+	if err := r.collection().FindOne(ctx, filter).Decode(&doc); err != nil {
+		return nil, translateError(err)
+	}
+	return &doc, nil
+}
+
+func (r *ProvidersRepository) GetByUserId(ctx context.Context, userId string) (*domain.Providers, error) {
+	var m domain.Providers
+	err := r.collection().FindOne(ctx, bson.M{"user_id": userId, "deleted_at": nil}, options.FindOne().SetSort(bson.D{{Key: "created_at", Value: 1}})).Decode(&m)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *ProvidersRepository) GetBySlug(ctx context.Context, slug string) (*domain.Providers, error) {
+	return r.findOne(ctx, bson.M{"slug": slug, "deleted_at": nil})
+}
+
+func (r *ProvidersRepository) Search(ctx context.Context, f domain.ProviderFilter) ([]*domain.Providers, string, error) {
+	after, err := pagination.Decode(f.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filter := bson.M{"deleted_at": nil}
+	if f.City != "" {
+		filter["city"] = f.City
+	}
+	if f.BusinessType != "" {
+		filter["business_type"] = f.BusinessType
+	}
+	if after != nil {
+		filter["_id"] = bson.M{"$gt": after.ID}
+	}
+	limit := f.PageSize()
+	cur, err := r.collection().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit+1)))
+	if err != nil {
+		return nil, "", err
+	}
+	var results []*domain.Providers
+	if err := cur.All(ctx, &results); err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	return results, pagination.Encode(pagination.Cursor{ID: results[limit-1].ID}), nil
+}
+
+func (r *ProvidersRepository) ListByGeohash(ctx context.Context, prefix string) ([]*domain.Providers, error) {
+	filter := bson.M{"deleted_at": nil, "geohash": bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}}
+	cur, err := r.collection().Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var results []*domain.Providers
+	if err := cur.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *ProvidersRepository) Create(ctx context.Context, model *domain.Providers) (string, error) {
+	now := utils.Now()
+	model.CreatedAt = now
+	model.UpdatedAt = now
+	model.ID = docid.New()
+	if _, err := r.collection().InsertOne(ctx, model); err != nil {
+		return "", err
+	}
+	return model.ID, nil
+}
+
+func (r *ProvidersRepository) Update(ctx context.Context, id string, m *domain.Providers) error {
+	m.UpdatedAt = utils.Now()
+	m.ID = id
+	_, err := r.collection().ReplaceOne(ctx, bson.M{"_id": id}, m, upsert)
+	return err
+}
+
+func (r *ProvidersRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *ProvidersRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	res, err := r.collection().DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$lt": deletedBefore}})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.DeletedCount), nil
+}
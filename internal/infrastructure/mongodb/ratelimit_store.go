@@ -0,0 +1,41 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RateLimitStore keeps one document per key and window; the TTL index on
+// expires_at removes finished windows.
+type RateLimitStore struct {
+	db *MongoDB
+}
+
+func NewRateLimitStore(db *MongoDB) *RateLimitStore {
+	return &RateLimitStore{db: db}
+}
+
+func (r *RateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	resetAt := domain.WindowStart(utils.Now(), window).Add(window)
+	id := fmt.Sprintf("%s@%d", key, resetAt.Unix())
+
+	var d struct {
+		Count int `bson:"count"`
+	}
+	err := r.db.db.Collection("rate_limits").FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$inc": bson.M{"count": 1}, "$setOnInsert": bson.M{"expires_at": resetAt}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&d)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return d.Count, resetAt, nil
+}
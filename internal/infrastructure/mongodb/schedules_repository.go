@@ -0,0 +1,71 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SchedulesRepository struct {
+	db *MongoDB
+}
+
+func NewSchedulesRepository(db *MongoDB) *SchedulesRepository {
+	return &SchedulesRepository{db: db}
+}
+
+func (r *SchedulesRepository) collection() *mongo.Collection {
+	return r.db.db.Collection("schedules")
+}
+
+func (r *SchedulesRepository) GetByProvider(ctx context.Context, providerID string, scheduleType domain.ScheduleType) (*domain.Schedule, error) {
+	var s domain.Schedule
+	err := r.collection().FindOne(ctx, bson.M{"provider_id": providerID, "type": scheduleType}).Decode(&s)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Upsert keeps a single schedule per provider and type, relying on the
+// (provider_id, type) unique index. The ID and creation time of an existing
+// schedule are preserved.
+func (r *SchedulesRepository) Upsert(ctx context.Context, schedule *domain.Schedule) error {
+	if schedule.ProviderId == "" {
+		return errors.New("provider_id is required")
+	}
+
+	now := utils.Now()
+	schedule.UpdatedAt = now
+
+	set := bson.M{
+		"days":       schedule.Days,
+		"valid_from": schedule.ValidFrom,
+		"valid_to":   schedule.ValidTo,
+		"updated_at": now,
+		"deleted_at": schedule.DeletedAt,
+	}
+	setOnInsert := bson.M{"_id": docid.New(), "created_at": now}
+
+	var saved domain.Schedule
+	err := r.collection().FindOneAndUpdate(ctx,
+		bson.M{"provider_id": schedule.ProviderId, "type": schedule.Type},
+		bson.M{"$set": set, "$setOnInsert": setOnInsert},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&saved)
+	if err != nil {
+		return err
+	}
+	schedule.ID = saved.ID
+	schedule.CreatedAt = saved.CreatedAt
+	return nil
+}
@@ -0,0 +1,188 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"ServiceBookingApp/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ServicesRepository struct {
+	db *MongoDB
+}
+
+func NewServicesRepository(db *MongoDB) *ServicesRepository {
+	return &ServicesRepository{db: db}
+}
+
+func (r *ServicesRepository) collection() *mongo.Collection {
+	return r.db.db.Collection("services")
+}
+
+func (r *ServicesRepository) List(ctx context.Context, opts domain.ListOptions, providerId string) ([]*domain.Services, string, error) {
+	after, err := pagination.Decode(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filter := bson.M{}
+	if providerId != "" {
+		filter["provider_id"] = providerId
+	}
+	if !opts.IncludeDeleted {
+		filter["deleted_at"] = nil
+	}
+	if after != nil {
+		if after.SortOrder == nil {
+			return nil, "", domain.ErrInvalidCursor
+		}
+		filter["$or"] = bson.A{
+			bson.M{"sort_order": bson.M{"$gt": *after.SortOrder}},
+			bson.M{"sort_order": *after.SortOrder, "_id": bson.M{"$gt": after.ID}},
+		}
+	}
+	limit := opts.PageSize()
+	cur, err := r.collection().Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "sort_order", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit+1)))
+	if err != nil {
+		return nil, "", err
+	}
+	var results []*domain.Services
+	if err := cur.All(ctx, &results); err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	last := results[limit-1]
+	return results, pagination.Encode(pagination.Cursor{SortOrder: &last.SortOrder, ID: last.ID}), nil
+}
+
+func (r *ServicesRepository) Get(ctx context.Context, id string) (*domain.Services, error) {
+	return r.findOne(ctx, bson.M{"_id": id, "deleted_at": nil})
+}
+
+func (r *ServicesRepository) GetIncludingDeleted(ctx context.Context, id string) (*domain.Services, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *ServicesRepository) findOne(ctx context.Context, filter bson.M) (*domain.Services, error) {
+	var doc domain.Services
+	if err := r.collection().FindOne(ctx, filter).Decode(&doc); err != nil {
+		return nil, translateError(err)
+	}
+	return &doc, nil
+}
+
+func (r *ServicesRepository) Create(ctx context.Context, model *domain.Services) (string, error) {
+	now := utils.Now()
+	model.CreatedAt = now
+	model.UpdatedAt = now
+	model.ID = docid.New()
+	if _, err := r.collection().InsertOne(ctx, model); err != nil {
+		return "", err
+	}
+	return model.ID, nil
+}
+
+func (r *ServicesRepository) Update(ctx context.Context, id string, m *domain.Services) error {
+	m.UpdatedAt = utils.Now()
+	m.ID = id
+	_, err := r.collection().ReplaceOne(ctx, bson.M{"_id": id}, m, upsert)
+	return err
+}
+
+func (r *ServicesRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// Reorder runs in a transaction that locks the provider like bookings do, so
+// two reorders of the same provider are applied one after the other.
+func (r *ServicesRepository) Reorder(ctx context.Context, providerId string, ids []string) ([]*domain.Services, error) {
+	session, err := r.db.client.StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	ordered, err := session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if err := r.db.lockProvider(sc, providerId); err != nil {
+			return nil, err
+		}
+		cur, err := r.collection().Find(sc, bson.M{"provider_id": providerId, "deleted_at": nil},
+			options.Find().SetSort(bson.D{{Key: "sort_order", Value: 1}, {Key: "_id", Value: 1}}))
+		if err != nil {
+			return nil, err
+		}
+		var services []*domain.Services
+		if err := cur.All(sc, &services); err != nil {
+			return nil, err
+		}
+
+		ordered, changed, err := domain.ReorderServices(services, ids)
+		if err != nil {
+			return nil, err
+		}
+		if len(changed) == 0 {
+			return ordered, nil
+		}
+		now := utils.Now()
+		writes := make([]mongo.WriteModel, 0, len(changed))
+		for _, m := range changed {
+			m.UpdatedAt = now
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": m.ID}).
+				SetUpdate(bson.M{"$set": bson.M{"sort_order": m.SortOrder, "updated_at": m.UpdatedAt}}))
+		}
+		if _, err := r.collection().BulkWrite(sc, writes); err != nil {
+			return nil, err
+		}
+		return ordered, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ordered.([]*domain.Services), nil
+}
+
+// Import upserts services by an ID derived from (provider_id, external_id),
+// so importing the same file twice leaves a single copy.
+func (r *ServicesRepository) Import(ctx context.Context, models []*domain.Services) error {
+	if len(models) == 0 {
+		return nil
+	}
+	now := utils.Now()
+	writes := make([]mongo.WriteModel, 0, len(models))
+	for _, m := range models {
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = now
+		}
+		m.UpdatedAt = now
+		m.ID = docid.Import(m.ProviderId, m.ExternalId)
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": m.ID}).
+			SetReplacement(m).
+			SetUpsert(true))
+	}
+	_, err := r.collection().BulkWrite(ctx, writes)
+	return err
+}
+
+func (r *ServicesRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	res, err := r.collection().DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$lt": deletedBefore}})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.DeletedCount), nil
+}
@@ -0,0 +1,86 @@
+package mongodb
+
+import (
+	"context"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"ServiceBookingApp/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type UsersRepository struct {
+	db *MongoDB
+}
+
+func NewUsersRepository(db *MongoDB) *UsersRepository {
+	return &UsersRepository{db: db}
+}
+
+func (r *UsersRepository) collection() *mongo.Collection {
+	return r.db.db.Collection("users")
+}
+
+func (r *UsersRepository) List(ctx context.Context, opts domain.ListOptions) ([]*domain.Users, string, error) {
+	after, err := pagination.Decode(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filter := bson.M{}
+	if after != nil {
+		filter["_id"] = bson.M{"$gt": after.ID}
+	}
+	limit := opts.PageSize()
+	cur, err := r.collection().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit+1)))
+	if err != nil {
+		return nil, "", err
+	}
+	var results []*domain.Users
+	if err := cur.All(ctx, &results); err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	return results, pagination.Encode(pagination.Cursor{ID: results[limit-1].ID}), nil
+}
+
+func (r *UsersRepository) Get(ctx context.Context, id string) (*domain.Users, error) {
+	var m domain.Users
+	if err := r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&m); err != nil {
+		return nil, translateError(err)
+	}
+	return &m, nil
+}
+
+func (r *UsersRepository) Create(ctx context.Context, model *domain.Users) (string, error) {
+	now := utils.Now()
+	model.CreatedAt = now
+	model.UpdatedAt = now
+	model.ID = docid.New()
+	if _, err := r.collection().InsertOne(ctx, model); err != nil {
+		return "", err
+	}
+	return model.ID, nil
+}
+
+// Update creates the user when it does not exist yet; users are keyed by
+// their Firebase UID and first written on login.
+func (r *UsersRepository) Update(ctx context.Context, id string, m *domain.Users) error {
+	m.UpdatedAt = utils.Now()
+	m.ID = id
+	_, err := r.collection().ReplaceOne(ctx, bson.M{"_id": id}, m, upsert)
+	return err
+}
+
+func (r *UsersRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
@@ -0,0 +1,41 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+)
+
+// Cursor is the decoded form of the opaque next_cursor handed to clients.
+// Every storage adapter uses the same encoding. ID is the ID of the last item
+// of the previous page and ScheduledAt or SortOrder its ordering field, when
+// the listing is ordered by one.
+type Cursor struct {
+	ScheduledAt *time.Time `json:"t,omitempty"`
+	SortOrder   *int       `json:"o,omitempty"`
+	ID          string     `json:"id"`
+}
+
+func Encode(c Cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode returns nil for an empty string and domain.ErrInvalidCursor for
+// anything that was not produced by Encode.
+func Decode(s string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, domain.ErrInvalidCursor
+	}
+	return &c, nil
+}
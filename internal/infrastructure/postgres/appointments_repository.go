@@ -0,0 +1,245 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const appointmentColumns = `id, service_id, provider_id, notes, scheduled_at, duration_minutes, service_name, price, status,
+	customer_name, customer_email, customer_phone, external_id, created_at, updated_at, deleted_at`
+
+type AppointmentsRepository struct {
+	db *PostgresDB
+}
+
+func NewAppointmentsRepository(db *PostgresDB) *AppointmentsRepository {
+	return &AppointmentsRepository{db: db}
+}
+
+func scanAppointment(row pgx.Row) (*domain.Appointments, error) {
+	var m domain.Appointments
+	var notes []byte
+	var externalId *string
+	err := row.Scan(&m.ID, &m.ServiceId, &m.ProviderId, &notes, &m.ScheduledAt, &m.DurationMinutes, &m.ServiceName, &m.Price, &m.Status,
+		&m.CustomerName, &m.CustomerEmail, &m.CustomerPhone, &externalId, &m.CreatedAt, &m.UpdatedAt, &m.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := fromJSONB(notes, &m.Notes); err != nil {
+		return nil, err
+	}
+	m.ExternalId = stringOrEmpty(externalId)
+	return &m, nil
+}
+
+func collectAppointments(rows pgx.Rows) ([]*domain.Appointments, error) {
+	defer rows.Close()
+	var results []*domain.Appointments
+	for rows.Next() {
+		m, err := scanAppointment(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, m)
+	}
+	return results, rows.Err()
+}
+
+func (r *AppointmentsRepository) ListByDate(ctx context.Context, date time.Time, providerId string) ([]*domain.Appointments, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	query := `SELECT ` + appointmentColumns + ` FROM appointments WHERE scheduled_at >= $1 AND scheduled_at < $2 AND deleted_at IS NULL`
+	args := []interface{}{startOfDay, endOfDay}
+	if providerId != "" {
+		query += ` AND provider_id = $3`
+		args = append(args, providerId)
+	}
+	query += ` ORDER BY scheduled_at, id`
+
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return collectAppointments(rows)
+}
+
+func (r *AppointmentsRepository) List(ctx context.Context, filter domain.AppointmentsFilter) ([]*domain.Appointments, string, error) {
+	after, err := pagination.Decode(filter.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var where []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if !filter.IncludeDeleted {
+		where = append(where, "deleted_at IS NULL")
+	}
+	if filter.ProviderId != "" {
+		where = append(where, "provider_id = "+arg(filter.ProviderId))
+	}
+	if filter.Status != "" {
+		where = append(where, "status = "+arg(filter.Status))
+	}
+	if filter.ServiceId != "" {
+		where = append(where, "service_id = "+arg(filter.ServiceId))
+	}
+	if filter.Customer != "" {
+		where = append(where, "customer_email = "+arg(filter.Customer))
+	}
+
+	now := utils.Now()
+	direction, cmp := "DESC", "<"
+	if filter.Type == "upcoming" {
+		where = append(where, "scheduled_at >= "+arg(now))
+		direction, cmp = "ASC", ">"
+	} else if filter.Type == "past" {
+		where = append(where, "scheduled_at < "+arg(now))
+	}
+	if !filter.From.IsZero() {
+		where = append(where, "scheduled_at >= "+arg(filter.From))
+	}
+	if !filter.To.IsZero() {
+		where = append(where, "scheduled_at < "+arg(filter.To))
+	}
+	if after != nil {
+		if after.ScheduledAt == nil {
+			return nil, "", domain.ErrInvalidCursor
+		}
+		where = append(where, fmt.Sprintf("(scheduled_at, id) %s (%s, %s)", cmp, arg(*after.ScheduledAt), arg(after.ID)))
+	}
+
+	query := `SELECT ` + appointmentColumns + ` FROM appointments`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	limit := filter.PageSize()
+	query += fmt.Sprintf(` ORDER BY scheduled_at %s, id %s LIMIT %s`, direction, direction, arg(limit+1))
+
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	results, err := collectAppointments(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	last := results[limit-1]
+	return results, pagination.Encode(pagination.Cursor{ScheduledAt: &last.ScheduledAt, ID: last.ID}), nil
+}
+
+func (r *AppointmentsRepository) Get(ctx context.Context, id string) (*domain.Appointments, error) {
+	row := r.db.pool.QueryRow(ctx, `SELECT `+appointmentColumns+` FROM appointments WHERE id = $1 AND deleted_at IS NULL`, id)
+	m, err := scanAppointment(row)
+	return m, translateError(err)
+}
+
+func (r *AppointmentsRepository) GetIncludingDeleted(ctx context.Context, id string) (*domain.Appointments, error) {
+	row := r.db.pool.QueryRow(ctx, `SELECT `+appointmentColumns+` FROM appointments WHERE id = $1`, id)
+	m, err := scanAppointment(row)
+	return m, translateError(err)
+}
+
+func (r *AppointmentsRepository) Create(ctx context.Context, model *domain.Appointments) (string, error) {
+	now := utils.Now()
+	model.CreatedAt = now
+	model.UpdatedAt = now
+	id := docid.New()
+	if err := r.upsert(ctx, r.db.pool, id, model, false); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *AppointmentsRepository) Update(ctx context.Context, id string, m *domain.Appointments) error {
+	m.UpdatedAt = utils.Now()
+	return r.upsert(ctx, r.db.pool, id, m, false)
+}
+
+func (r *AppointmentsRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.pool.Exec(ctx, `DELETE FROM appointments WHERE id = $1`, id)
+	return err
+}
+
+// Import upserts on (provider_id, external_id) inside a single transaction.
+// Rows that are already present keep their ID. Imported rows are marked so
+// that appointments_no_overlap leaves them out: history may overlap.
+func (r *AppointmentsRepository) Import(ctx context.Context, models []*domain.Appointments) error {
+	now := utils.Now()
+	return pgx.BeginFunc(ctx, r.db.pool, func(tx pgx.Tx) error {
+		for _, m := range models {
+			if m.CreatedAt.IsZero() {
+				m.CreatedAt = now
+			}
+			m.UpdatedAt = now
+
+			var id string
+			err := tx.QueryRow(ctx, `SELECT id FROM appointments WHERE provider_id = $1 AND external_id = $2`, m.ProviderId, m.ExternalId).Scan(&id)
+			if err == pgx.ErrNoRows {
+				id = docid.New()
+			} else if err != nil {
+				return err
+			}
+			if err := r.upsert(ctx, tx, id, m, true); err != nil {
+				return err
+			}
+			m.ID = id
+		}
+		return nil
+	})
+}
+
+// upsert mirrors Firestore's Set: the row is replaced as a whole, and
+// created when it does not exist. Only imported rows skip the overlap
+// constraint, so an imported appointment that is later moved is checked.
+func (r *AppointmentsRepository) upsert(ctx context.Context, q execer, id string, m *domain.Appointments, imported bool) error {
+	notes, err := toJSONB(m.Notes)
+	if err != nil {
+		return err
+	}
+	endsAt := m.ScheduledAt.Add(time.Duration(m.DurationMinutes) * time.Minute)
+
+	_, err = q.Exec(ctx, `INSERT INTO appointments (id, service_id, provider_id, notes, scheduled_at, ends_at, duration_minutes, service_name, price, status,
+			customer_name, customer_email, customer_phone, external_id, created_at, updated_at, deleted_at, imported)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		ON CONFLICT (id) DO UPDATE SET
+			service_id = EXCLUDED.service_id, provider_id = EXCLUDED.provider_id, notes = EXCLUDED.notes,
+			scheduled_at = EXCLUDED.scheduled_at, ends_at = EXCLUDED.ends_at, duration_minutes = EXCLUDED.duration_minutes,
+			service_name = EXCLUDED.service_name, price = EXCLUDED.price, status = EXCLUDED.status, customer_name = EXCLUDED.customer_name,
+			customer_email = EXCLUDED.customer_email, customer_phone = EXCLUDED.customer_phone, external_id = EXCLUDED.external_id,
+			created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at, deleted_at = EXCLUDED.deleted_at,
+			imported = EXCLUDED.imported`,
+		id, m.ServiceId, m.ProviderId, notes, m.ScheduledAt, endsAt, m.DurationMinutes, m.ServiceName, m.Price, m.Status,
+		m.CustomerName, m.CustomerEmail, m.CustomerPhone, nullIfEmpty(m.ExternalId), m.CreatedAt, m.UpdatedAt, m.DeletedAt, imported)
+	return translateError(err)
+}
+
+// Purge hard-deletes the appointments soft-deleted before deletedBefore,
+// except cancelled ones: their DeletedAt marks the cancellation, which stats
+// and exports still report.
+func (r *AppointmentsRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	tag, err := r.db.pool.Exec(ctx, `DELETE FROM appointments WHERE deleted_at < $1 AND status <> 'cancelled'`, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"ServiceBookingApp/internal/utils"
+)
+
+const auditColumns = `id, provider_id, actor_uid, action, entity_type, entity_id, diff, ip, user_agent, created_at`
+
+type AuditRepository struct {
+	db *PostgresDB
+}
+
+func NewAuditRepository(db *PostgresDB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, entry *domain.AuditEntry) (string, error) {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = utils.Now()
+	}
+	id := docid.New()
+	_, err := r.db.pool.Exec(ctx, `INSERT INTO audit (`+auditColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		id, entry.ProviderId, entry.ActorUID, entry.Action, entry.EntityType, entry.EntityId, entry.Diff, entry.IP, entry.UserAgent, entry.CreatedAt)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, string, error) {
+	after, err := pagination.Decode(filter.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var where []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.ProviderId != "" {
+		where = append(where, "provider_id = "+arg(filter.ProviderId))
+	}
+	if filter.EntityType != "" {
+		where = append(where, "entity_type = "+arg(filter.EntityType))
+	}
+	if filter.EntityId != "" {
+		where = append(where, "entity_id = "+arg(filter.EntityId))
+	}
+	if filter.ActorUID != "" {
+		where = append(where, "actor_uid = "+arg(filter.ActorUID))
+	}
+	if !filter.From.IsZero() {
+		where = append(where, "created_at >= "+arg(filter.From))
+	}
+	if !filter.To.IsZero() {
+		where = append(where, "created_at < "+arg(filter.To))
+	}
+	if after != nil {
+		if after.ScheduledAt == nil {
+			return nil, "", domain.ErrInvalidCursor
+		}
+		where = append(where, fmt.Sprintf("(created_at, id) < (%s, %s)", arg(*after.ScheduledAt), arg(after.ID)))
+	}
+
+	query := `SELECT ` + auditColumns + ` FROM audit`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	limit := filter.PageSize()
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ` + arg(limit+1)
+
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var results []*domain.AuditEntry
+	for rows.Next() {
+		var m domain.AuditEntry
+		if err := rows.Scan(&m.ID, &m.ProviderId, &m.ActorUID, &m.Action, &m.EntityType, &m.EntityId, &m.Diff, &m.IP, &m.UserAgent, &m.CreatedAt); err != nil {
+			return nil, "", err
+		}
+		results = append(results, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	last := results[limit-1]
+	return results, pagination.Encode(pagination.Cursor{ScheduledAt: &last.CreatedAt, ID: last.ID}), nil
+}
+
+func (r *AuditRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	tag, err := r.db.pool.Exec(ctx, `DELETE FROM audit WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const holdColumns = `id, provider_id, service_id, scheduled_at, duration_minutes, expires_at, created_at`
+
+// holdsLock is the first key of the per-provider advisory locks Create
+// takes. Expiry depends on the time of the check, so an exclusion
+// constraint cannot keep live holds apart the way it does appointments.
+const holdsLock = 0x401d5
+
+type HoldsRepository struct {
+	db *PostgresDB
+}
+
+func NewHoldsRepository(db *PostgresDB) *HoldsRepository {
+	return &HoldsRepository{db: db}
+}
+
+func scanHold(row pgx.Row) (*domain.Hold, error) {
+	var m domain.Hold
+	if err := row.Scan(&m.ID, &m.ProviderId, &m.ServiceId, &m.ScheduledAt, &m.DurationMinutes, &m.ExpiresAt, &m.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Create serializes the holds of a provider on an advisory lock held until
+// the transaction ends, then checks for a live overlapping hold.
+func (r *HoldsRepository) Create(ctx context.Context, hold *domain.Hold) (string, error) {
+	hold.CreatedAt = utils.Now()
+	id := docid.New()
+	end := hold.ScheduledAt.Add(time.Duration(hold.DurationMinutes) * time.Minute)
+	err := pgx.BeginFunc(ctx, r.db.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1, hashtext($2))`, holdsLock, hold.ProviderId); err != nil {
+			return err
+		}
+		var taken bool
+		err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM holds
+			WHERE provider_id = $1 AND expires_at > $2 AND scheduled_at < $3
+				AND scheduled_at + make_interval(mins => duration_minutes) > $4)`,
+			hold.ProviderId, hold.CreatedAt, end, hold.ScheduledAt).Scan(&taken)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return domain.ErrSlotTaken
+		}
+		_, err = tx.Exec(ctx, `INSERT INTO holds (`+holdColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			id, hold.ProviderId, hold.ServiceId, hold.ScheduledAt, hold.DurationMinutes, hold.ExpiresAt, hold.CreatedAt)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *HoldsRepository) Get(ctx context.Context, id string) (*domain.Hold, error) {
+	m, err := scanHold(r.db.pool.QueryRow(ctx, `SELECT `+holdColumns+` FROM holds WHERE id = $1`, id))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return m, nil
+}
+
+func (r *HoldsRepository) ListByDate(ctx context.Context, date time.Time, providerId string, now time.Time) ([]*domain.Hold, error) {
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	rows, err := r.db.pool.Query(ctx, `SELECT `+holdColumns+` FROM holds
+		WHERE provider_id = $1 AND scheduled_at >= $2 AND scheduled_at < $3 AND expires_at > $4
+		ORDER BY scheduled_at`, providerId, startOfDay, endOfDay, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*domain.Hold
+	for rows.Next() {
+		m, err := scanHold(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, m)
+	}
+	return results, rows.Err()
+}
+
+func (r *HoldsRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.pool.Exec(ctx, `DELETE FROM holds WHERE id = $1`, id)
+	return err
+}
+
+func (r *HoldsRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	tag, err := r.db.pool.Exec(ctx, `DELETE FROM holds WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
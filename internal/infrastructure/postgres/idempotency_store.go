@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type IdempotencyStore struct {
+	db *PostgresDB
+}
+
+func NewIdempotencyStore(db *PostgresDB) *IdempotencyStore {
+	return &IdempotencyStore{db: db}
+}
+
+// Start inserts rec, or takes over an expired record, in one statement.
+// When it changes no row the key is taken, and the live record is read
+// back.
+func (r *IdempotencyStore) Start(ctx context.Context, rec *domain.IdempotencyRecord, now time.Time) (*domain.IdempotencyRecord, error) {
+	tag, err := r.db.pool.Exec(ctx, `INSERT INTO idempotency_keys (key, request_hash, status_code, content_type, body, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash, status_code = EXCLUDED.status_code,
+			content_type = EXCLUDED.content_type, body = EXCLUDED.body, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= $7`,
+		rec.Key, rec.RequestHash, rec.StatusCode, rec.ContentType, rec.Body, rec.ExpiresAt, now)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 1 {
+		return nil, nil
+	}
+
+	m := domain.IdempotencyRecord{Key: rec.Key}
+	err = r.db.pool.QueryRow(ctx, `SELECT request_hash, status_code, content_type, body, expires_at FROM idempotency_keys WHERE key = $1`, rec.Key).
+		Scan(&m.RequestHash, &m.StatusCode, &m.ContentType, &m.Body, &m.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Released in between: the key is free again.
+		return r.Start(ctx, rec, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *IdempotencyStore) Complete(ctx context.Context, rec *domain.IdempotencyRecord) error {
+	_, err := r.db.pool.Exec(ctx, `UPDATE idempotency_keys SET request_hash = $2, status_code = $3, content_type = $4, body = $5, expires_at = $6 WHERE key = $1`,
+		rec.Key, rec.RequestHash, rec.StatusCode, rec.ContentType, rec.Body, rec.ExpiresAt)
+	return err
+}
+
+func (r *IdempotencyStore) Delete(ctx context.Context, key string) error {
+	_, err := r.db.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE key = $1`, key)
+	return err
+}
+
+func (r *IdempotencyStore) Purge(ctx context.Context, before time.Time) (int, error) {
+	tag, err := r.db.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,86 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE users (
+    id          text PRIMARY KEY,
+    email       text NOT NULL DEFAULT '',
+    name        text NOT NULL DEFAULT '',
+    picture     text NOT NULL DEFAULT '',
+    role_id     text NOT NULL DEFAULT '',
+    is_active   boolean,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    updated_at  timestamptz NOT NULL DEFAULT now(),
+    deleted_at  timestamptz
+);
+
+CREATE TABLE providers (
+    id                  text PRIMARY KEY,
+    user_id             text NOT NULL,
+    address             text NOT NULL DEFAULT '',
+    avatar_url          text NOT NULL DEFAULT '',
+    establishment_name  text NOT NULL DEFAULT '',
+    phone               text NOT NULL DEFAULT '',
+    created_at          timestamptz NOT NULL DEFAULT now(),
+    updated_at          timestamptz NOT NULL DEFAULT now(),
+    deleted_at          timestamptz
+);
+
+CREATE INDEX providers_user_id_idx ON providers (user_id);
+
+CREATE TABLE services (
+    id                text PRIMARY KEY,
+    provider_id       text NOT NULL,
+    description       jsonb,
+    duration_minutes  integer NOT NULL DEFAULT 0,
+    icon_url          text NOT NULL DEFAULT '',
+    price             double precision NOT NULL DEFAULT 0,
+    color             text NOT NULL DEFAULT '',
+    title             text NOT NULL DEFAULT '',
+    external_id       text,
+    created_at        timestamptz NOT NULL DEFAULT now(),
+    updated_at        timestamptz NOT NULL DEFAULT now(),
+    deleted_at        timestamptz,
+    UNIQUE (provider_id, external_id)
+);
+
+CREATE INDEX services_provider_id_idx ON services (provider_id, id);
+
+CREATE TABLE appointments (
+    id                text PRIMARY KEY,
+    service_id        text NOT NULL,
+    provider_id       text NOT NULL,
+    notes             jsonb,
+    scheduled_at      timestamptz NOT NULL,
+    -- ends_at is maintained by the repository: timestamptz + interval is not
+    -- immutable, so it cannot be computed inside the exclusion constraint.
+    ends_at           timestamptz NOT NULL,
+    duration_minutes  integer NOT NULL DEFAULT 0,
+    service_name      text NOT NULL DEFAULT '',
+    status            text NOT NULL DEFAULT '',
+    customer_name     text NOT NULL DEFAULT '',
+    customer_email    text NOT NULL DEFAULT '',
+    customer_phone    text NOT NULL DEFAULT '',
+    external_id       text,
+    created_at        timestamptz NOT NULL DEFAULT now(),
+    updated_at        timestamptz NOT NULL DEFAULT now(),
+    deleted_at        timestamptz,
+    UNIQUE (provider_id, external_id),
+    CONSTRAINT appointments_no_overlap EXCLUDE USING gist (
+        provider_id WITH =,
+        tstzrange(scheduled_at, ends_at) WITH &&
+    ) WHERE (deleted_at IS NULL AND status <> 'cancelled')
+);
+
+CREATE INDEX appointments_provider_scheduled_idx ON appointments (provider_id, scheduled_at, id);
+
+CREATE TABLE schedules (
+    id           text PRIMARY KEY,
+    provider_id  text NOT NULL,
+    type         text NOT NULL,
+    days         jsonb NOT NULL DEFAULT '{}',
+    valid_from   timestamptz,
+    valid_to     timestamptz,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    updated_at   timestamptz NOT NULL DEFAULT now(),
+    deleted_at   timestamptz,
+    UNIQUE (provider_id, type)
+);
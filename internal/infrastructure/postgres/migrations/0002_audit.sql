@@ -0,0 +1,15 @@
+CREATE TABLE audit (
+    id           text PRIMARY KEY,
+    provider_id  text NOT NULL DEFAULT '',
+    actor_uid    text NOT NULL DEFAULT '',
+    action       text NOT NULL,
+    entity_type  text NOT NULL,
+    entity_id    text NOT NULL DEFAULT '',
+    diff         jsonb,
+    ip           text NOT NULL DEFAULT '',
+    user_agent   text NOT NULL DEFAULT '',
+    created_at   timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_provider_created_idx ON audit (provider_id, created_at DESC, id DESC);
+CREATE INDEX audit_created_idx ON audit (created_at);
@@ -0,0 +1 @@
+ALTER TABLE providers ADD COLUMN business_type text NOT NULL DEFAULT '';
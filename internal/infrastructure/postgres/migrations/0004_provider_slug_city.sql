@@ -0,0 +1,8 @@
+ALTER TABLE providers ADD COLUMN slug text NOT NULL DEFAULT '';
+ALTER TABLE providers ADD COLUMN city text NOT NULL DEFAULT '';
+
+-- Providers created before slugs existed are reachable by their ID.
+UPDATE providers SET slug = id WHERE slug = '';
+
+CREATE INDEX providers_slug_idx ON providers (slug);
+CREATE INDEX providers_city_business_type_idx ON providers (city, business_type, id);
@@ -0,0 +1,6 @@
+ALTER TABLE providers ADD COLUMN latitude double precision NOT NULL DEFAULT 0;
+ALTER TABLE providers ADD COLUMN longitude double precision NOT NULL DEFAULT 0;
+ALTER TABLE providers ADD COLUMN geohash text NOT NULL DEFAULT '';
+
+-- text_pattern_ops lets LIKE 'prefix%' use the index whatever the collation.
+CREATE INDEX providers_geohash_idx ON providers (geohash text_pattern_ops);
@@ -0,0 +1,7 @@
+-- One row per key, holding the count of its current window. Rows of keys
+-- not seen since are overwritten on their next hit rather than deleted.
+CREATE TABLE rate_limits (
+    key       text PRIMARY KEY,
+    count     integer NOT NULL,
+    reset_at  timestamptz NOT NULL
+);
@@ -0,0 +1,12 @@
+CREATE TABLE holds (
+    id                text PRIMARY KEY,
+    provider_id       text NOT NULL,
+    service_id        text NOT NULL,
+    scheduled_at      timestamptz NOT NULL,
+    duration_minutes  integer NOT NULL DEFAULT 0,
+    expires_at        timestamptz NOT NULL,
+    created_at        timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX holds_provider_scheduled_idx ON holds (provider_id, scheduled_at);
+CREATE INDEX holds_expires_idx ON holds (expires_at);
@@ -0,0 +1,10 @@
+CREATE TABLE idempotency_keys (
+    key           text PRIMARY KEY,
+    request_hash  text NOT NULL,
+    status_code   integer NOT NULL DEFAULT 0,
+    content_type  text NOT NULL DEFAULT '',
+    body          bytea,
+    expires_at    timestamptz NOT NULL
+);
+
+CREATE INDEX idempotency_keys_expires_idx ON idempotency_keys (expires_at);
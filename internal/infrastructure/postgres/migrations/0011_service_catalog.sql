@@ -0,0 +1,9 @@
+ALTER TABLE services
+    ADD COLUMN category    text NOT NULL DEFAULT '',
+    ADD COLUMN sort_order  integer NOT NULL DEFAULT 0,
+    ADD COLUMN private     boolean NOT NULL DEFAULT false,
+    ADD COLUMN active_from timestamptz,
+    ADD COLUMN active_to   timestamptz;
+
+DROP INDEX services_provider_id_idx;
+CREATE INDEX services_provider_id_idx ON services (provider_id, sort_order, id);
@@ -0,0 +1,10 @@
+-- Import keeps history as it was, overlaps included, so imported rows are
+-- left out of the overlap constraint until they are next saved by Update.
+ALTER TABLE appointments ADD COLUMN imported boolean NOT NULL DEFAULT false;
+UPDATE appointments SET imported = true WHERE external_id IS NOT NULL;
+
+ALTER TABLE appointments DROP CONSTRAINT appointments_no_overlap;
+ALTER TABLE appointments ADD CONSTRAINT appointments_no_overlap EXCLUDE USING gist (
+    provider_id WITH =,
+    tstzrange(scheduled_at, ends_at) WITH &&
+) WHERE (deleted_at IS NULL AND status <> 'cancelled' AND NOT imported);
@@ -0,0 +1,5 @@
+-- Appointments keep the price they were booked at. Existing ones take their
+-- service's current price, the best record there is of it.
+ALTER TABLE appointments ADD COLUMN price double precision NOT NULL DEFAULT 0;
+
+UPDATE appointments a SET price = s.price FROM services s WHERE s.id = a.service_id;
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// goMigrations are the migrations that need more than SQL, keyed by a
+// version that sorts among the SQL files. They run like the SQL ones.
+var goMigrations = map[string]func(ctx context.Context, tx pgx.Tx) error{
+	"migrations/0009_typed_notes_description": normalizeRichText,
+}
+
+// PostgresDB wraps the connection pool shared by the repositories.
+type PostgresDB struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresDB connects to databaseURL and applies pending migrations.
+func NewPostgresDB(ctx context.Context, databaseURL string) (*PostgresDB, error) {
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to postgres: %v", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("error connecting to postgres: %v", err)
+	}
+
+	db := &PostgresDB{pool: pool}
+	if err := db.Migrate(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func (db *PostgresDB) Close() {
+	db.pool.Close()
+}
+
+func (db *PostgresDB) Pool() *pgxpool.Pool {
+	return db.pool
+}
+
+// migrationLock is the key of the advisory lock Migrate holds, so instances
+// starting together apply the migrations one at a time.
+const migrationLock = 0x5eb1ce
+
+// Migrate applies every embedded and Go migration not yet recorded in
+// schema_migrations, each in its own transaction, in version order. It
+// holds an advisory lock for the whole run; an instance that waited for it
+// finds the migrations applied and skips them.
+func (db *PostgresDB) Migrate(ctx context.Context) error {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring connection for migrations: %v", err)
+	}
+	defer conn.Release()
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLock); err != nil {
+		return fmt.Errorf("error locking migrations: %v", err)
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLock)
+
+	if _, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version text PRIMARY KEY,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("error creating schema_migrations: %v", err)
+	}
+
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	for name := range goMigrations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var applied bool
+		if err := conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, name).Scan(&applied); err != nil {
+			return err
+		}
+		if applied {
+			continue
+		}
+
+		up, ok := goMigrations[name]
+		if !ok {
+			sql, err := migrations.ReadFile(name)
+			if err != nil {
+				return err
+			}
+			up = func(ctx context.Context, tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, string(sql))
+				return err
+			}
+		}
+		err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if err := up(ctx, tx); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, name)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("error applying migration %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// execer is satisfied by both the pool and a transaction.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+// translateError maps constraint violations onto domain errors.
+func translateError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23P01" && pgErr.ConstraintName == "appointments_no_overlap" {
+		return domain.ErrSlotTaken
+	}
+	return err
+}
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// toJSONB encodes structured values, such as notes, for jsonb columns.
+func toJSONB(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// fromJSONB decodes a jsonb column into v, leaving v as it is for NULL.
+func fromJSONB(data []byte, v interface{}) error {
+	if data == nil {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
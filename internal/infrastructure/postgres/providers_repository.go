@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const providerColumns = `id, user_id, slug, address, city, avatar_url, establishment_name, business_type, phone, latitude, longitude, geohash, created_at, updated_at, deleted_at`
+
+type ProvidersRepository struct {
+	db *PostgresDB
+}
+
+func NewProvidersRepository(db *PostgresDB) *ProvidersRepository {
+	return &ProvidersRepository{db: db}
+}
+
+func scanProvider(row pgx.Row) (*domain.Providers, error) {
+	var m domain.Providers
+	err := row.Scan(&m.ID, &m.UserId, &m.Slug, &m.Address, &m.City, &m.AvatarUrl, &m.EstablishmentName, &m.BusinessType, &m.Phone, &m.Latitude, &m.Longitude, &m.Geohash, &m.CreatedAt, &m.UpdatedAt, &m.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *ProvidersRepository) List(ctx context.Context, opts domain.ListOptions) ([]*domain.Providers, string, error) {
+	after, err := pagination.Decode(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	afterID := ""
+	if after != nil {
+		afterID = after.ID
+	}
+	limit := opts.PageSize()
+	rows, err := r.db.pool.Query(ctx, `SELECT `+providerColumns+` FROM providers
+		WHERE id > $1 AND ($2 OR deleted_at IS NULL) ORDER BY id LIMIT $3`, afterID, opts.IncludeDeleted, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var results []*domain.Providers
+	for rows.Next() {
+		m, err := scanProvider(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		results = append(results, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	return results, pagination.Encode(pagination.Cursor{ID: results[limit-1].ID}), nil
+}
+
+func (r *ProvidersRepository) Get(ctx context.Context, id string) (*domain.Providers, error) {
+	row := r.db.pool.QueryRow(ctx, `SELECT `+providerColumns+` FROM providers WHERE id = $1 AND deleted_at IS NULL`, id)
+	m, err := scanProvider(row)
+	return m, translateError(err)
+}
+
+func (r *ProvidersRepository) GetIncludingDeleted(ctx context.Context, id string) (*domain.Providers, error) {
+	row := r.db.pool.QueryRow(ctx, `SELECT `+providerColumns+` FROM providers WHERE id = $1`, id)
+	m, err := scanProvider(row)
+	return m, translateError(err)
+}
+
+func (r *ProvidersRepository) GetByUserId(ctx context.Context, userId string) (*domain.Providers, error) {
+	row := r.db.pool.QueryRow(ctx, `SELECT `+providerColumns+` FROM providers WHERE user_id = $1 AND deleted_at IS NULL ORDER BY created_at LIMIT 1`, userId)
+	m, err := scanProvider(row)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return m, err
+}
+
+func (r *ProvidersRepository) GetBySlug(ctx context.Context, slug string) (*domain.Providers, error) {
+	row := r.db.pool.QueryRow(ctx, `SELECT `+providerColumns+` FROM providers WHERE slug = $1 AND deleted_at IS NULL`, slug)
+	m, err := scanProvider(row)
+	return m, translateError(err)
+}
+
+func (r *ProvidersRepository) Search(ctx context.Context, filter domain.ProviderFilter) ([]*domain.Providers, string, error) {
+	after, err := pagination.Decode(filter.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	afterID := ""
+	if after != nil {
+		afterID = after.ID
+	}
+	limit := filter.PageSize()
+	rows, err := r.db.pool.Query(ctx, `SELECT `+providerColumns+` FROM providers
+		WHERE id > $1 AND deleted_at IS NULL AND ($2 = '' OR city = $2) AND ($3 = '' OR business_type = $3)
+		ORDER BY id LIMIT $4`, afterID, filter.City, filter.BusinessType, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var results []*domain.Providers
+	for rows.Next() {
+		m, err := scanProvider(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		results = append(results, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	return results, pagination.Encode(pagination.Cursor{ID: results[limit-1].ID}), nil
+}
+
+func (r *ProvidersRepository) ListByGeohash(ctx context.Context, prefix string) ([]*domain.Providers, error) {
+	rows, err := r.db.pool.Query(ctx, `SELECT `+providerColumns+` FROM providers
+		WHERE geohash LIKE $1 || '%' AND geohash <> '' AND deleted_at IS NULL`, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*domain.Providers
+	for rows.Next() {
+		m, err := scanProvider(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, m)
+	}
+	return results, rows.Err()
+}
+
+func (r *ProvidersRepository) Create(ctx context.Context, model *domain.Providers) (string, error) {
+	now := utils.Now()
+	model.CreatedAt = now
+	model.UpdatedAt = now
+	id := docid.New()
+	if err := r.upsert(ctx, id, model); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *ProvidersRepository) Update(ctx context.Context, id string, m *domain.Providers) error {
+	m.UpdatedAt = utils.Now()
+	return r.upsert(ctx, id, m)
+}
+
+func (r *ProvidersRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.pool.Exec(ctx, `DELETE FROM providers WHERE id = $1`, id)
+	return err
+}
+
+func (r *ProvidersRepository) upsert(ctx context.Context, id string, m *domain.Providers) error {
+	_, err := r.db.pool.Exec(ctx, `INSERT INTO providers (id, user_id, slug, address, city, avatar_url, establishment_name, business_type, phone, latitude, longitude, geohash, created_at, updated_at, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (id) DO UPDATE SET
+			user_id = EXCLUDED.user_id, slug = EXCLUDED.slug, address = EXCLUDED.address, city = EXCLUDED.city, avatar_url = EXCLUDED.avatar_url,
+			establishment_name = EXCLUDED.establishment_name, business_type = EXCLUDED.business_type, phone = EXCLUDED.phone,
+			latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude, geohash = EXCLUDED.geohash,
+			created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at, deleted_at = EXCLUDED.deleted_at`,
+		id, m.UserId, m.Slug, m.Address, m.City, m.AvatarUrl, m.EstablishmentName, m.BusinessType, m.Phone, m.Latitude, m.Longitude, m.Geohash, m.CreatedAt, m.UpdatedAt, m.DeletedAt)
+	return err
+}
+
+func (r *ProvidersRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	tag, err := r.db.pool.Exec(ctx, `DELETE FROM providers WHERE deleted_at < $1`, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
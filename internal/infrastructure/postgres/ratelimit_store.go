@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+)
+
+type RateLimitStore struct {
+	db *PostgresDB
+}
+
+func NewRateLimitStore(db *PostgresDB) *RateLimitStore {
+	return &RateLimitStore{db: db}
+}
+
+// Hit counts in a single upsert, which restarts the count when the stored
+// row belongs to an earlier window.
+func (r *RateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	resetAt := domain.WindowStart(utils.Now(), window).Add(window)
+	var count int
+	err := r.db.pool.QueryRow(ctx, `INSERT INTO rate_limits (key, count, reset_at) VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			count = CASE WHEN rate_limits.reset_at = EXCLUDED.reset_at THEN rate_limits.count + 1 ELSE 1 END,
+			reset_at = EXCLUDED.reset_at
+		RETURNING count`, key, resetAt).Scan(&count)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return count, resetAt, nil
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"ServiceBookingApp/internal/richtext"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// normalizeRichText rewrites the notes and descriptions stored as free-form
+// JSON before they were typed into domain.Notes and domain.Description.
+// Descriptions need their Markdown rendered, which SQL cannot do.
+func normalizeRichText(ctx context.Context, tx pgx.Tx) error {
+	err := normalizeColumn(ctx, tx, "appointments", "notes",
+		`jsonb_typeof(notes) <> 'object' OR NOT notes ? 'text'`,
+		func(v interface{}) interface{} { return richtext.LegacyNotes(v) })
+	if err != nil {
+		return err
+	}
+	return normalizeColumn(ctx, tx, "services", "description",
+		`jsonb_typeof(description) <> 'object' OR NOT description ?& array['markdown', 'html']`,
+		func(v interface{}) interface{} { return richtext.LegacyDescription(v) })
+}
+
+// normalizeColumn replaces the non-null values of table.column matching
+// where with their conversion.
+func normalizeColumn(ctx context.Context, tx pgx.Tx, table, column, where string, convert func(interface{}) interface{}) error {
+	rows, err := tx.Query(ctx, `SELECT id, `+column+` FROM `+table+` WHERE `+column+` IS NOT NULL AND (`+where+`)`)
+	if err != nil {
+		return err
+	}
+	values := map[string][]byte{}
+	for rows.Next() {
+		var id string
+		var data []byte
+		if err := rows.Scan(&id, &data); err != nil {
+			rows.Close()
+			return err
+		}
+		values[id] = data
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, data := range values {
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		converted, err := toJSONB(convert(v))
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `UPDATE `+table+` SET `+column+` = $1 WHERE id = $2`, converted, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
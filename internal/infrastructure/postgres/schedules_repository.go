@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type SchedulesRepository struct {
+	db *PostgresDB
+}
+
+func NewSchedulesRepository(db *PostgresDB) *SchedulesRepository {
+	return &SchedulesRepository{db: db}
+}
+
+func (r *SchedulesRepository) GetByProvider(ctx context.Context, providerID string, scheduleType domain.ScheduleType) (*domain.Schedule, error) {
+	var s domain.Schedule
+	var days []byte
+	err := r.db.pool.QueryRow(ctx, `SELECT id, provider_id, type, days, valid_from, valid_to, created_at, updated_at, deleted_at
+		FROM schedules WHERE provider_id = $1 AND type = $2`, providerID, scheduleType).
+		Scan(&s.ID, &s.ProviderId, &s.Type, &days, &s.ValidFrom, &s.ValidTo, &s.CreatedAt, &s.UpdatedAt, &s.DeletedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(days, &s.Days); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Upsert keeps a single schedule per provider and type, as the Firestore
+// adapter does, relying on the (provider_id, type) unique constraint.
+func (r *SchedulesRepository) Upsert(ctx context.Context, schedule *domain.Schedule) error {
+	if schedule.ProviderId == "" {
+		return errors.New("provider_id is required")
+	}
+
+	days, err := json.Marshal(schedule.Days)
+	if err != nil {
+		return err
+	}
+	if schedule.ID == "" {
+		schedule.ID = docid.New()
+	}
+	now := utils.Now()
+	schedule.UpdatedAt = now
+
+	return r.db.pool.QueryRow(ctx, `INSERT INTO schedules (id, provider_id, type, days, valid_from, valid_to, created_at, updated_at, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $8)
+		ON CONFLICT (provider_id, type) DO UPDATE SET
+			days = EXCLUDED.days, valid_from = EXCLUDED.valid_from, valid_to = EXCLUDED.valid_to,
+			updated_at = EXCLUDED.updated_at, deleted_at = EXCLUDED.deleted_at
+		RETURNING id, created_at`,
+		schedule.ID, schedule.ProviderId, schedule.Type, days, schedule.ValidFrom, schedule.ValidTo, now, schedule.DeletedAt).
+		Scan(&schedule.ID, &schedule.CreatedAt)
+}
@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const serviceColumns = `id, provider_id, description, duration_minutes, icon_url, price, color, title, intake_form,
+	category, sort_order, private, active_from, active_to, external_id, created_at, updated_at, deleted_at`
+
+type ServicesRepository struct {
+	db *PostgresDB
+}
+
+func NewServicesRepository(db *PostgresDB) *ServicesRepository {
+	return &ServicesRepository{db: db}
+}
+
+func scanService(row pgx.Row) (*domain.Services, error) {
+	var m domain.Services
+	var description, intakeForm []byte
+	var externalId *string
+	err := row.Scan(&m.ID, &m.ProviderId, &description, &m.DurationMinutes, &m.IconUrl, &m.Price, &m.Color, &m.Title, &intakeForm,
+		&m.Category, &m.SortOrder, &m.Private, &m.ActiveFrom, &m.ActiveTo, &externalId, &m.CreatedAt, &m.UpdatedAt, &m.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := fromJSONB(description, &m.Description); err != nil {
+		return nil, err
+	}
+	if err := fromJSONB(intakeForm, &m.IntakeForm); err != nil {
+		return nil, err
+	}
+	m.ExternalId = stringOrEmpty(externalId)
+	return &m, nil
+}
+
+func (r *ServicesRepository) List(ctx context.Context, opts domain.ListOptions, providerId string) ([]*domain.Services, string, error) {
+	after, err := pagination.Decode(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := `SELECT ` + serviceColumns + ` FROM services WHERE ($1 = '' OR provider_id = $1)`
+	args := []interface{}{providerId}
+	if !opts.IncludeDeleted {
+		query += ` AND deleted_at IS NULL`
+	}
+	if after != nil {
+		if after.SortOrder == nil {
+			return nil, "", domain.ErrInvalidCursor
+		}
+		args = append(args, *after.SortOrder, after.ID)
+		query += fmt.Sprintf(` AND (sort_order, id) > ($%d, $%d)`, len(args)-1, len(args))
+	}
+	limit := opts.PageSize()
+	args = append(args, limit+1)
+	query += fmt.Sprintf(` ORDER BY sort_order, id LIMIT $%d`, len(args))
+
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var results []*domain.Services
+	for rows.Next() {
+		m, err := scanService(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		results = append(results, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	last := results[limit-1]
+	return results, pagination.Encode(pagination.Cursor{SortOrder: &last.SortOrder, ID: last.ID}), nil
+}
+
+func (r *ServicesRepository) Get(ctx context.Context, id string) (*domain.Services, error) {
+	row := r.db.pool.QueryRow(ctx, `SELECT `+serviceColumns+` FROM services WHERE id = $1 AND deleted_at IS NULL`, id)
+	m, err := scanService(row)
+	return m, translateError(err)
+}
+
+func (r *ServicesRepository) GetIncludingDeleted(ctx context.Context, id string) (*domain.Services, error) {
+	row := r.db.pool.QueryRow(ctx, `SELECT `+serviceColumns+` FROM services WHERE id = $1`, id)
+	m, err := scanService(row)
+	return m, translateError(err)
+}
+
+func (r *ServicesRepository) Create(ctx context.Context, model *domain.Services) (string, error) {
+	now := utils.Now()
+	model.CreatedAt = now
+	model.UpdatedAt = now
+	id := docid.New()
+	if err := r.upsert(ctx, r.db.pool, id, model); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *ServicesRepository) Update(ctx context.Context, id string, m *domain.Services) error {
+	m.UpdatedAt = utils.Now()
+	return r.upsert(ctx, r.db.pool, id, m)
+}
+
+func (r *ServicesRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.pool.Exec(ctx, `DELETE FROM services WHERE id = $1`, id)
+	return err
+}
+
+// Reorder locks the provider's live services for the transaction, so a
+// concurrent reorder waits and then renumbers from this one's result.
+func (r *ServicesRepository) Reorder(ctx context.Context, providerId string, ids []string) ([]*domain.Services, error) {
+	var ordered []*domain.Services
+	err := pgx.BeginFunc(ctx, r.db.pool, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `SELECT `+serviceColumns+` FROM services
+			WHERE provider_id = $1 AND deleted_at IS NULL ORDER BY sort_order, id FOR UPDATE`, providerId)
+		if err != nil {
+			return err
+		}
+		var services []*domain.Services
+		for rows.Next() {
+			m, err := scanService(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			services = append(services, m)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		var changed []*domain.Services
+		ordered, changed, err = domain.ReorderServices(services, ids)
+		if err != nil {
+			return err
+		}
+		now := utils.Now()
+		batch := &pgx.Batch{}
+		for _, m := range changed {
+			m.UpdatedAt = now
+			batch.Queue(`UPDATE services SET sort_order = $2, updated_at = $3 WHERE id = $1`, m.ID, m.SortOrder, m.UpdatedAt)
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ordered, nil
+}
+
+// Import upserts on (provider_id, external_id) inside a single transaction.
+// Rows that are already present keep their ID.
+func (r *ServicesRepository) Import(ctx context.Context, models []*domain.Services) error {
+	now := utils.Now()
+	return pgx.BeginFunc(ctx, r.db.pool, func(tx pgx.Tx) error {
+		for _, m := range models {
+			if m.CreatedAt.IsZero() {
+				m.CreatedAt = now
+			}
+			m.UpdatedAt = now
+
+			var id string
+			err := tx.QueryRow(ctx, `SELECT id FROM services WHERE provider_id = $1 AND external_id = $2`, m.ProviderId, m.ExternalId).Scan(&id)
+			if err == pgx.ErrNoRows {
+				id = docid.New()
+			} else if err != nil {
+				return err
+			}
+			if err := r.upsert(ctx, tx, id, m); err != nil {
+				return err
+			}
+			m.ID = id
+		}
+		return nil
+	})
+}
+
+func (r *ServicesRepository) upsert(ctx context.Context, q execer, id string, m *domain.Services) error {
+	description, err := toJSONB(m.Description)
+	if err != nil {
+		return err
+	}
+	intakeForm, err := toJSONB(m.IntakeForm)
+	if err != nil {
+		return err
+	}
+	_, err = q.Exec(ctx, `INSERT INTO services (id, provider_id, description, duration_minutes, icon_url, price, color, title, intake_form,
+			category, sort_order, private, active_from, active_to, external_id, created_at, updated_at, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		ON CONFLICT (id) DO UPDATE SET
+			provider_id = EXCLUDED.provider_id, description = EXCLUDED.description, duration_minutes = EXCLUDED.duration_minutes,
+			icon_url = EXCLUDED.icon_url, price = EXCLUDED.price, color = EXCLUDED.color, title = EXCLUDED.title,
+			intake_form = EXCLUDED.intake_form, category = EXCLUDED.category, sort_order = EXCLUDED.sort_order,
+			private = EXCLUDED.private, active_from = EXCLUDED.active_from, active_to = EXCLUDED.active_to,
+			external_id = EXCLUDED.external_id, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at,
+			deleted_at = EXCLUDED.deleted_at`,
+		id, m.ProviderId, description, m.DurationMinutes, m.IconUrl, m.Price, m.Color, m.Title, intakeForm,
+		m.Category, m.SortOrder, m.Private, m.ActiveFrom, m.ActiveTo, nullIfEmpty(m.ExternalId),
+		m.CreatedAt, m.UpdatedAt, m.DeletedAt)
+	return err
+}
+
+func (r *ServicesRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	tag, err := r.db.pool.Exec(ctx, `DELETE FROM services WHERE deleted_at < $1`, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
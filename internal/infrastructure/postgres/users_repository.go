@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/docid"
+	"ServiceBookingApp/internal/infrastructure/pagination"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const userColumns = `id, email, name, picture, role_id, is_active, created_at, updated_at, deleted_at`
+
+type UsersRepository struct {
+	db *PostgresDB
+}
+
+func NewUsersRepository(db *PostgresDB) *UsersRepository {
+	return &UsersRepository{db: db}
+}
+
+func scanUser(row pgx.Row) (*domain.Users, error) {
+	var m domain.Users
+	err := row.Scan(&m.ID, &m.Email, &m.Name, &m.Picture, &m.RoleId, &m.IsActive, &m.CreatedAt, &m.UpdatedAt, &m.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *UsersRepository) List(ctx context.Context, opts domain.ListOptions) ([]*domain.Users, string, error) {
+	after, err := pagination.Decode(opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	afterID := ""
+	if after != nil {
+		afterID = after.ID
+	}
+	limit := opts.PageSize()
+	rows, err := r.db.pool.Query(ctx, `SELECT `+userColumns+` FROM users WHERE id > $1 ORDER BY id LIMIT $2`, afterID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var results []*domain.Users
+	for rows.Next() {
+		m, err := scanUser(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		results = append(results, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	return results, pagination.Encode(pagination.Cursor{ID: results[limit-1].ID}), nil
+}
+
+func (r *UsersRepository) Get(ctx context.Context, id string) (*domain.Users, error) {
+	row := r.db.pool.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id)
+	m, err := scanUser(row)
+	return m, translateError(err)
+}
+
+func (r *UsersRepository) Create(ctx context.Context, model *domain.Users) (string, error) {
+	id := model.ID
+	if id == "" {
+		id = docid.New()
+	}
+	if err := r.Update(ctx, id, model); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Update creates the user when missing: Login relies on it to register new
+// Firebase users under their UID.
+func (r *UsersRepository) Update(ctx context.Context, id string, m *domain.Users) error {
+	_, err := r.db.pool.Exec(ctx, `INSERT INTO users (id, email, name, picture, role_id, is_active, created_at, updated_at, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO UPDATE SET
+			email = EXCLUDED.email, name = EXCLUDED.name, picture = EXCLUDED.picture, role_id = EXCLUDED.role_id,
+			is_active = EXCLUDED.is_active, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at,
+			deleted_at = EXCLUDED.deleted_at`,
+		id, m.Email, m.Name, m.Picture, m.RoleId, m.IsActive, m.CreatedAt, m.UpdatedAt, m.DeletedAt)
+	return err
+}
+
+func (r *UsersRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	return err
+}
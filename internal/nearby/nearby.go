@@ -0,0 +1,157 @@
+package nearby
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"ServiceBookingApp/internal/booking"
+	"ServiceBookingApp/internal/catalog"
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/geo"
+	"ServiceBookingApp/internal/utils"
+)
+
+const (
+	DefaultRadiusKm = 10
+	MaxRadiusKm     = 100
+)
+
+// Query describes a radius search. Service and Date are optional.
+type Query struct {
+	Lat, Lng     float64
+	RadiusKm     float64
+	BusinessType string
+	// Service keeps providers offering a service whose title contains it,
+	// case-insensitively.
+	Service string
+	// Date keeps providers with at least one free slot that day, for the
+	// matching services if Service is set. It is read in its own location.
+	Date  *time.Time
+	Limit int
+}
+
+type Result struct {
+	Provider   *domain.Providers
+	DistanceKm float64
+}
+
+// Finder answers "providers near me". Firestore cannot query by distance,
+// so candidates come from the geohash cells covering the circle and are then
+// filtered by their actual distance.
+type Finder struct {
+	providers domain.ProvidersRepository
+	services  domain.ServicesRepository
+	schedules domain.SchedulesRepository
+	booker    *booking.Booker
+}
+
+func NewFinder(providers domain.ProvidersRepository, services domain.ServicesRepository, schedules domain.SchedulesRepository, appointments domain.AppointmentsRepository, holds domain.HoldsRepository) *Finder {
+	return &Finder{
+		providers: providers,
+		services:  services,
+		schedules: schedules,
+		booker:    booking.NewBooker(appointments, services, holds, nil).PublicOnly(),
+	}
+}
+
+// Find returns the providers within q.RadiusKm of q.Lat/q.Lng, nearest
+// first.
+func (f *Finder) Find(ctx context.Context, q Query) ([]Result, error) {
+	if q.RadiusKm <= 0 {
+		q.RadiusKm = DefaultRadiusKm
+	}
+	if q.RadiusKm > MaxRadiusKm {
+		q.RadiusKm = MaxRadiusKm
+	}
+	limit := (domain.ListOptions{Limit: q.Limit}).PageSize()
+
+	seen := map[string]bool{}
+	var candidates []Result
+	for _, prefix := range geo.CoveringPrefixes(q.Lat, q.Lng, q.RadiusKm) {
+		providers, err := f.providers.ListByGeohash(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range providers {
+			if seen[p.ID] {
+				continue
+			}
+			seen[p.ID] = true
+			if q.BusinessType != "" && p.BusinessType != q.BusinessType {
+				continue
+			}
+			d := geo.DistanceKm(q.Lat, q.Lng, p.Latitude, p.Longitude)
+			if d > q.RadiusKm {
+				continue
+			}
+			candidates = append(candidates, Result{Provider: p, DistanceKm: d})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].DistanceKm != candidates[j].DistanceKm {
+			return candidates[i].DistanceKm < candidates[j].DistanceKm
+		}
+		return candidates[i].Provider.ID < candidates[j].Provider.ID
+	})
+
+	// The service and availability checks cost queries per provider, so
+	// they run nearest first and stop once the page is full.
+	results := []Result{}
+	for _, c := range candidates {
+		if len(results) == limit {
+			break
+		}
+		ok, err := f.matches(ctx, c.Provider, q)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			results = append(results, c)
+		}
+	}
+	return results, nil
+}
+
+func (f *Finder) matches(ctx context.Context, p *domain.Providers, q Query) (bool, error) {
+	if q.Service == "" && q.Date == nil {
+		return true, nil
+	}
+
+	services, err := catalog.Listed(ctx, f.services, p.ID, utils.Now())
+	if err != nil {
+		return false, err
+	}
+	if q.Service != "" {
+		needle := strings.ToLower(q.Service)
+		var matching []*domain.Services
+		for _, s := range services {
+			if strings.Contains(strings.ToLower(s.Title), needle) {
+				matching = append(matching, s)
+			}
+		}
+		services = matching
+	}
+	if len(services) == 0 {
+		return false, nil
+	}
+	if q.Date == nil {
+		return true, nil
+	}
+
+	schedule, err := f.schedules.GetByProvider(ctx, p.ID, domain.ScheduleTypeGlobal)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range services {
+		slots, err := f.booker.AvailableSlots(ctx, s, schedule, *q.Date)
+		if err != nil {
+			return false, err
+		}
+		if len(slots) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
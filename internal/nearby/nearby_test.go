@@ -0,0 +1,80 @@
+package nearby
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/geo"
+	"ServiceBookingApp/internal/infrastructure/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Points around the Obelisco in Buenos Aires.
+var geocoder = geo.StaticGeocoder{
+	"Obelisco": {Lat: -34.6037, Lng: -58.3816},
+	"Congreso": {Lat: -34.6096, Lng: -58.3926}, // ~1.2 km
+	"Palermo":  {Lat: -34.5711, Lng: -58.4233}, // ~5.2 km
+	"La Plata": {Lat: -34.9205, Lng: -57.9536}, // ~52 km
+	"Recoleta": {Lat: -34.5875, Lng: -58.3974}, // ~2.3 km
+}
+
+func TestFind(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	providers := geo.NewProvidersRepository(memory.NewProvidersRepository(store), geocoder)
+	services := memory.NewServicesRepository(store)
+	schedules := memory.NewSchedulesRepository(store)
+	appointments := memory.NewAppointmentsRepository(store)
+
+	create := func(address, businessType, service string, openOn string) string {
+		id, err := providers.Create(ctx, &domain.Providers{Address: address, BusinessType: businessType})
+		require.NoError(t, err)
+		_, err = services.Create(ctx, &domain.Services{ProviderId: id, Title: service, DurationMinutes: 30})
+		require.NoError(t, err)
+		require.NoError(t, schedules.Upsert(ctx, &domain.Schedule{ProviderId: id, Type: domain.ScheduleTypeGlobal, Days: map[string]domain.DaySchedule{
+			openOn: {Enabled: true, Ranges: []domain.TimeRange{{Start: "09:00", End: "10:00"}}},
+		}}))
+		return id
+	}
+	congreso := create("Congreso", "barbershop", "Haircut", "mon")
+	palermo := create("Palermo", "barbershop", "Beard trim", "tue")
+	create("La Plata", "barbershop", "Haircut", "mon")
+	recoleta := create("Recoleta", "clinic", "First visit", "mon")
+	_, err := providers.Create(ctx, &domain.Providers{Address: "Unknown street"})
+	require.NoError(t, err)
+
+	finder := NewFinder(providers, services, schedules, appointments, memory.NewHoldsRepository(store))
+	origin := geocoder["Obelisco"]
+	ids := func(q Query) []string {
+		q.Lat, q.Lng = origin.Lat, origin.Lng
+		results, err := finder.Find(ctx, q)
+		require.NoError(t, err)
+		var ids []string
+		for i, r := range results {
+			if i > 0 {
+				assert.GreaterOrEqual(t, r.DistanceKm, results[i-1].DistanceKm)
+			}
+			ids = append(ids, r.Provider.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{congreso, recoleta, palermo}, ids(Query{}), "default radius, nearest first")
+	assert.Equal(t, []string{congreso, recoleta}, ids(Query{RadiusKm: 3}))
+	assert.Equal(t, []string{congreso, palermo}, ids(Query{BusinessType: "barbershop"}))
+	assert.Equal(t, []string{palermo}, ids(Query{Service: "beard"}))
+	assert.Equal(t, []string{congreso}, ids(Query{Limit: 1}))
+	assert.Len(t, ids(Query{RadiusKm: 60}), 4)
+
+	monday := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{congreso, recoleta}, ids(Query{Date: &monday}))
+
+	// Once its only slot that Monday is taken, Congreso drops out.
+	_, err = appointments.Create(ctx, &domain.Appointments{ProviderId: congreso, ScheduledAt: monday.Add(9 * time.Hour), DurationMinutes: 60, Status: domain.StatusConfirmed})
+	require.NoError(t, err)
+	assert.Equal(t, []string{recoleta}, ids(Query{Date: &monday}))
+}
@@ -0,0 +1,206 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"maps"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/profile"
+)
+
+var (
+	ErrAlreadyOnboarded    = errors.New("user already has a provider")
+	ErrNoEstablishment     = errors.New("create the establishment first")
+	ErrUnknownBusinessType = errors.New("unknown business type")
+)
+
+// Steps, in the order the frontend should walk them.
+const (
+	StepEstablishment = "establishment"
+	StepSchedule      = "schedule"
+	StepServices      = "services"
+)
+
+type Step struct {
+	Name string `json:"name"`
+	Done bool   `json:"done"`
+}
+
+// Status tells the frontend which onboarding steps are left.
+type Status struct {
+	ProviderId string   `json:"provider_id,omitempty"`
+	Steps      []Step   `json:"steps"`
+	Remaining  []string `json:"remaining"`
+	Complete   bool     `json:"complete"`
+}
+
+// Establishment is the body of the first onboarding step; its `binding`
+// tags are checked by requests.Bind.
+type Establishment struct {
+	EstablishmentName string `json:"establishment_name" binding:"required,max=200"`
+	BusinessType      string `json:"business_type" binding:"max=50"`
+	Address           string `json:"address" binding:"max=500"`
+	City              string `json:"city" binding:"max=100"`
+	Phone             string `json:"phone" binding:"max=50"`
+}
+
+// Service walks a new user through becoming a provider: the establishment
+// bound to their UID, then a schedule and starter services taken from the
+// template of its business type. Every step can be repeated safely.
+type Service struct {
+	providers domain.ProvidersRepository
+	schedules domain.SchedulesRepository
+	services  domain.ServicesRepository
+}
+
+func NewService(providers domain.ProvidersRepository, schedules domain.SchedulesRepository, services domain.ServicesRepository) *Service {
+	return &Service{providers: providers, schedules: schedules, services: services}
+}
+
+// Status reports the progress of uid. Steps are derived from the stored
+// data, so a provider set up by hand counts as onboarded too.
+func (s *Service) Status(ctx context.Context, uid string) (*Status, error) {
+	provider, err := s.providers.GetByUserId(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	done := map[string]bool{StepEstablishment: provider != nil}
+	status := &Status{}
+	if provider != nil {
+		status.ProviderId = provider.ID
+		schedule, err := s.schedules.GetByProvider(ctx, provider.ID, domain.ScheduleTypeGlobal)
+		if err != nil {
+			return nil, err
+		}
+		done[StepSchedule] = schedule != nil
+		services, _, err := s.services.List(ctx, domain.ListOptions{Limit: 1}, provider.ID)
+		if err != nil {
+			return nil, err
+		}
+		done[StepServices] = len(services) > 0
+	}
+
+	status.Remaining = []string{}
+	for _, name := range []string{StepEstablishment, StepSchedule, StepServices} {
+		status.Steps = append(status.Steps, Step{Name: name, Done: done[name]})
+		if !done[name] {
+			status.Remaining = append(status.Remaining, name)
+		}
+	}
+	status.Complete = len(status.Remaining) == 0
+	return status, nil
+}
+
+// CreateEstablishment creates the provider of uid.
+func (s *Service) CreateEstablishment(ctx context.Context, uid string, req Establishment) (*domain.Providers, error) {
+	template, ok := TemplateFor(req.BusinessType)
+	if !ok {
+		return nil, ErrUnknownBusinessType
+	}
+	existing, err := s.providers.GetByUserId(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrAlreadyOnboarded
+	}
+
+	provider := &domain.Providers{
+		UserId:            uid,
+		EstablishmentName: req.EstablishmentName,
+		BusinessType:      template.BusinessType,
+		Address:           req.Address,
+		City:              req.City,
+		Phone:             req.Phone,
+	}
+	if err := profile.AssignSlug(ctx, s.providers, provider); err != nil {
+		return nil, err
+	}
+	id, err := s.providers.Create(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+	provider.ID = id
+	return provider, nil
+}
+
+// ApplySchedule gives the provider of uid the global schedule of its
+// business type. A schedule that already exists is returned untouched.
+func (s *Service) ApplySchedule(ctx context.Context, uid string) (*domain.Schedule, error) {
+	provider, template, err := s.providerTemplate(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.schedules.GetByProvider(ctx, provider.ID, domain.ScheduleTypeGlobal)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	schedule := &domain.Schedule{
+		ProviderId: provider.ID,
+		Type:       domain.ScheduleTypeGlobal,
+		Days:       maps.Clone(template.Days),
+	}
+	if err := s.schedules.Upsert(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// ApplyServices creates the starter services of the provider's business
+// type. It does nothing when the provider already has services.
+func (s *Service) ApplyServices(ctx context.Context, uid string) ([]*domain.Services, error) {
+	provider, template, err := s.providerTemplate(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, _, err := s.services.List(ctx, domain.ListOptions{Limit: 1}, provider.ID)
+	if err != nil {
+		return nil, err
+	}
+	created := []*domain.Services{}
+	if len(existing) > 0 {
+		return created, nil
+	}
+
+	for _, st := range template.Services {
+		m := &domain.Services{
+			ProviderId:      provider.ID,
+			Title:           st.Title,
+			DurationMinutes: st.DurationMinutes,
+			Price:           st.Price,
+			Color:           st.Color,
+		}
+		id, err := s.services.Create(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		m.ID = id
+		created = append(created, m)
+	}
+	return created, nil
+}
+
+func (s *Service) providerTemplate(ctx context.Context, uid string) (*domain.Providers, Template, error) {
+	provider, err := s.providers.GetByUserId(ctx, uid)
+	if err != nil {
+		return nil, Template{}, err
+	}
+	if provider == nil {
+		return nil, Template{}, ErrNoEstablishment
+	}
+	template, ok := TemplateFor(provider.BusinessType)
+	if !ok {
+		// Providers created outside onboarding may carry a type we have no
+		// template for; they still get the generic one.
+		template, _ = TemplateFor(DefaultBusinessType)
+	}
+	return provider, template, nil
+}
@@ -0,0 +1,91 @@
+package onboarding
+
+import (
+	"context"
+	"testing"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService() (*Service, *memory.Store) {
+	store := memory.NewStore()
+	return NewService(
+		memory.NewProvidersRepository(store),
+		memory.NewSchedulesRepository(store),
+		memory.NewServicesRepository(store),
+	), store
+}
+
+func TestOnboarding(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestService()
+
+	status, err := s.Status(ctx, "user-1")
+	require.NoError(t, err)
+	assert.False(t, status.Complete)
+	assert.Equal(t, []string{StepEstablishment, StepSchedule, StepServices}, status.Remaining)
+
+	_, err = s.ApplySchedule(ctx, "user-1")
+	assert.ErrorIs(t, err, ErrNoEstablishment)
+
+	provider, err := s.CreateEstablishment(ctx, "user-1", Establishment{EstablishmentName: "Barbería", BusinessType: "barbershop"})
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", provider.UserId)
+	assert.Equal(t, "barbershop", provider.BusinessType)
+
+	_, err = s.CreateEstablishment(ctx, "user-1", Establishment{EstablishmentName: "Otra"})
+	assert.ErrorIs(t, err, ErrAlreadyOnboarded)
+
+	status, err = s.Status(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, provider.ID, status.ProviderId)
+	assert.Equal(t, []string{StepSchedule, StepServices}, status.Remaining)
+
+	schedule, err := s.ApplySchedule(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ScheduleTypeGlobal, schedule.Type)
+	assert.True(t, schedule.Days["sat"].Enabled)
+	assert.False(t, schedule.Days["sun"].Enabled)
+
+	created, err := s.ApplyServices(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Len(t, created, 3)
+
+	// Repeating a step changes nothing.
+	again, err := s.ApplyServices(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	status, err = s.Status(ctx, "user-1")
+	require.NoError(t, err)
+	assert.True(t, status.Complete)
+	assert.Empty(t, status.Remaining)
+}
+
+func TestOnboardingKeepsExistingSchedule(t *testing.T) {
+	ctx := context.Background()
+	s, store := newTestService()
+
+	provider, err := s.CreateEstablishment(ctx, "user-1", Establishment{EstablishmentName: "Clínica", BusinessType: "clinic"})
+	require.NoError(t, err)
+
+	custom := &domain.Schedule{ProviderId: provider.ID, Type: domain.ScheduleTypeGlobal, Days: map[string]domain.DaySchedule{
+		"sun": {Enabled: true, Ranges: []domain.TimeRange{{Start: "10:00", End: "12:00"}}},
+	}}
+	require.NoError(t, memory.NewSchedulesRepository(store).Upsert(ctx, custom))
+
+	schedule, err := s.ApplySchedule(ctx, "user-1")
+	require.NoError(t, err)
+	assert.True(t, schedule.Days["sun"].Enabled)
+	assert.False(t, schedule.Days["mon"].Enabled)
+}
+
+func TestCreateEstablishmentRejectsUnknownBusinessType(t *testing.T) {
+	s, _ := newTestService()
+	_, err := s.CreateEstablishment(context.Background(), "user-1", Establishment{EstablishmentName: "X", BusinessType: "spaceport"})
+	assert.ErrorIs(t, err, ErrUnknownBusinessType)
+}
@@ -0,0 +1,92 @@
+package onboarding
+
+import "ServiceBookingApp/internal/domain"
+
+// Template is the starting point for a business type: the weekly schedule
+// and the services a new provider of that type usually offers.
+type Template struct {
+	BusinessType string                        `json:"business_type"`
+	Name         string                        `json:"name"`
+	Days         map[string]domain.DaySchedule `json:"days"`
+	Services     []ServiceTemplate             `json:"services"`
+}
+
+type ServiceTemplate struct {
+	Title           string  `json:"title"`
+	DurationMinutes int     `json:"duration_minutes"`
+	Price           float64 `json:"price"`
+	Color           string  `json:"color"`
+}
+
+// DefaultBusinessType is used when the caller does not pick one.
+const DefaultBusinessType = "generic"
+
+func weekdays(ranges ...domain.TimeRange) map[string]domain.DaySchedule {
+	days := make(map[string]domain.DaySchedule)
+	for _, day := range []string{"mon", "tue", "wed", "thu", "fri"} {
+		days[day] = domain.DaySchedule{Enabled: true, Ranges: ranges}
+	}
+	return days
+}
+
+func withDay(days map[string]domain.DaySchedule, day string, ranges ...domain.TimeRange) map[string]domain.DaySchedule {
+	days[day] = domain.DaySchedule{Enabled: true, Ranges: ranges}
+	return days
+}
+
+var splitShift = []domain.TimeRange{{Start: "09:00", End: "13:00"}, {Start: "14:00", End: "19:00"}}
+
+// Templates lists the business types offered during onboarding.
+var Templates = []Template{
+	{
+		BusinessType: "barbershop",
+		Name:         "Barbershop",
+		Days:         withDay(weekdays(splitShift...), "sat", domain.TimeRange{Start: "10:00", End: "14:00"}),
+		Services: []ServiceTemplate{
+			{Title: "Haircut", DurationMinutes: 30, Price: 15, Color: "#1E88E5"},
+			{Title: "Beard trim", DurationMinutes: 20, Price: 10, Color: "#43A047"},
+			{Title: "Haircut and beard", DurationMinutes: 45, Price: 22, Color: "#8E24AA"},
+		},
+	},
+	{
+		BusinessType: "beauty_salon",
+		Name:         "Beauty salon",
+		Days:         withDay(weekdays(domain.TimeRange{Start: "10:00", End: "20:00"}), "sat", domain.TimeRange{Start: "10:00", End: "15:00"}),
+		Services: []ServiceTemplate{
+			{Title: "Manicure", DurationMinutes: 45, Price: 20, Color: "#D81B60"},
+			{Title: "Pedicure", DurationMinutes: 60, Price: 25, Color: "#F4511E"},
+			{Title: "Hair colouring", DurationMinutes: 120, Price: 60, Color: "#6D4C41"},
+		},
+	},
+	{
+		BusinessType: "clinic",
+		Name:         "Clinic",
+		Days:         weekdays(splitShift...),
+		Services: []ServiceTemplate{
+			{Title: "First visit", DurationMinutes: 60, Price: 50, Color: "#00897B"},
+			{Title: "Follow-up", DurationMinutes: 30, Price: 30, Color: "#3949AB"},
+		},
+	},
+	{
+		BusinessType: DefaultBusinessType,
+		Name:         "Other",
+		Days:         weekdays(splitShift...),
+		Services: []ServiceTemplate{
+			{Title: "Appointment", DurationMinutes: 60, Price: 0, Color: "#546E7A"},
+		},
+	},
+}
+
+// TemplateFor returns the template of businessType, falling back to the
+// generic one when businessType is empty.
+func TemplateFor(businessType string) (Template, bool) {
+	if businessType == "" {
+		businessType = DefaultBusinessType
+	}
+	for _, t := range Templates {
+		if t.BusinessType == businessType {
+			return t, true
+		}
+	}
+	return Template{}, false
+}
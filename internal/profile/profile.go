@@ -0,0 +1,122 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+)
+
+var (
+	// ErrSlugTaken is returned when a slug chosen by hand is already in use.
+	ErrSlugTaken   = errors.New("slug is already taken")
+	ErrInvalidSlug = errors.New("slug must contain letters or digits")
+)
+
+// Profile is what the public booking page may see of a provider. It leaves
+// out the owner's UID and bookkeeping fields.
+type Profile struct {
+	ID                string     `json:"id"`
+	Slug              string     `json:"slug"`
+	EstablishmentName string     `json:"establishment_name"`
+	BusinessType      string     `json:"business_type"`
+	Address           string     `json:"address"`
+	City              string     `json:"city"`
+	Phone             string     `json:"phone"`
+	AvatarUrl         string     `json:"avatar_url"`
+	Latitude          float64    `json:"latitude,omitempty"`
+	Longitude         float64    `json:"longitude,omitempty"`
+	Hours             []DayHours `json:"hours,omitempty"`
+	// DistanceKm is set by radius searches.
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+}
+
+// DayHours are the opening hours of one weekday.
+type DayHours struct {
+	Day    string             `json:"day"`
+	Open   bool               `json:"open"`
+	Ranges []domain.TimeRange `json:"ranges"`
+}
+
+// weekdays orders Hours the way a week is shown, Monday first.
+var weekdays = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+// New builds the profile of p. Hours come from schedule, which may be nil
+// when the provider has none yet.
+func New(p *domain.Providers, schedule *domain.Schedule) Profile {
+	profile := Profile{
+		ID:                p.ID,
+		Slug:              p.Slug,
+		EstablishmentName: p.EstablishmentName,
+		BusinessType:      p.BusinessType,
+		Address:           p.Address,
+		City:              p.City,
+		Phone:             p.Phone,
+		AvatarUrl:         p.AvatarUrl,
+		Latitude:          p.Latitude,
+		Longitude:         p.Longitude,
+	}
+	if schedule == nil {
+		return profile
+	}
+	for _, day := range weekdays {
+		ds := schedule.Days[day]
+		hours := DayHours{Day: day, Open: ds.Enabled && len(ds.Ranges) > 0, Ranges: []domain.TimeRange{}}
+		if hours.Open {
+			hours.Ranges = ds.Ranges
+		}
+		profile.Hours = append(profile.Hours, hours)
+	}
+	return profile
+}
+
+// AssignSlug sets a unique slug on p. An explicit p.Slug is normalized and
+// must be free; otherwise one is derived from the establishment name, with
+// a numeric suffix when the name is taken.
+func AssignSlug(ctx context.Context, repo domain.ProvidersRepository, p *domain.Providers) error {
+	if p.Slug != "" {
+		slug := utils.Slugify(p.Slug)
+		if slug == "" {
+			return ErrInvalidSlug
+		}
+		free, err := slugFree(ctx, repo, slug, p.ID)
+		if err != nil {
+			return err
+		}
+		if !free {
+			return ErrSlugTaken
+		}
+		p.Slug = slug
+		return nil
+	}
+
+	base := utils.Slugify(p.EstablishmentName)
+	if base == "" {
+		base = "provider"
+	}
+	slug := base
+	for n := 2; ; n++ {
+		free, err := slugFree(ctx, repo, slug, p.ID)
+		if err != nil {
+			return err
+		}
+		if free {
+			p.Slug = slug
+			return nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+func slugFree(ctx context.Context, repo domain.ProvidersRepository, slug, selfID string) (bool, error) {
+	existing, err := repo.GetBySlug(ctx, slug)
+	if errors.Is(err, domain.ErrNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return existing.ID == selfID, nil
+}
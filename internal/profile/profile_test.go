@@ -0,0 +1,59 @@
+package profile
+
+import (
+	"context"
+	"testing"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignSlug(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewProvidersRepository(memory.NewStore())
+
+	create := func(p *domain.Providers) *domain.Providers {
+		require.NoError(t, AssignSlug(ctx, repo, p))
+		id, err := repo.Create(ctx, p)
+		require.NoError(t, err)
+		p.ID = id
+		return p
+	}
+
+	first := create(&domain.Providers{EstablishmentName: "Barbería El Tano"})
+	assert.Equal(t, "barberia-el-tano", first.Slug)
+
+	second := create(&domain.Providers{EstablishmentName: "Barbería  el tano!"})
+	assert.Equal(t, "barberia-el-tano-2", second.Slug)
+
+	assert.Equal(t, "provider", create(&domain.Providers{}).Slug)
+
+	explicit := &domain.Providers{Slug: "Barbería El Tano"}
+	assert.ErrorIs(t, AssignSlug(ctx, repo, explicit), ErrSlugTaken)
+	assert.ErrorIs(t, AssignSlug(ctx, repo, &domain.Providers{Slug: "¡!"}), ErrInvalidSlug)
+
+	// A provider keeps its own slug when it is saved again.
+	first.Slug = "barberia-el-tano"
+	assert.NoError(t, AssignSlug(ctx, repo, first))
+}
+
+func TestNewHidesPrivateFieldsAndListsHours(t *testing.T) {
+	p := &domain.Providers{ID: "p1", UserId: "secret-uid", Slug: "tano", EstablishmentName: "Tano"}
+	schedule := &domain.Schedule{Days: map[string]domain.DaySchedule{
+		"mon": {Enabled: true, Ranges: []domain.TimeRange{{Start: "09:00", End: "13:00"}}},
+		"tue": {Enabled: false, Ranges: []domain.TimeRange{{Start: "09:00", End: "13:00"}}},
+	}}
+
+	profile := New(p, schedule)
+	assert.Equal(t, "tano", profile.Slug)
+	require.Len(t, profile.Hours, 7)
+	assert.Equal(t, DayHours{Day: "mon", Open: true, Ranges: []domain.TimeRange{{Start: "09:00", End: "13:00"}}}, profile.Hours[0])
+	assert.False(t, profile.Hours[1].Open)
+	assert.Empty(t, profile.Hours[1].Ranges)
+	assert.Equal(t, "sun", profile.Hours[6].Day)
+
+	assert.Empty(t, New(p, nil).Hours)
+}
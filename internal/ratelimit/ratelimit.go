@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ServiceBookingApp/internal/apperrors"
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rule allows Limit requests per Window for each value of Key. A rule with
+// a Limit of 0 is off.
+type Rule struct {
+	// Name prefixes the store keys, so rules keyed on the same value keep
+	// separate counts.
+	Name   string
+	Limit  int
+	Window time.Duration
+	Key    func(c *gin.Context) string
+}
+
+// ByIP keys a rule on the client address.
+func ByIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// Middleware answers 429 with Retry-After once a request goes over any of
+// rules. When the store fails the request goes through: losing the counters
+// should not take the endpoint down with them.
+func Middleware(store domain.RateLimitStore, rules ...Rule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, rule := range rules {
+			if rule.Limit <= 0 {
+				continue
+			}
+			count, resetAt, err := store.Hit(c.Request.Context(), rule.Name+":"+rule.Key(c), rule.Window)
+			if err != nil {
+				log.Printf("rate limit %s: %v", rule.Name, err)
+				continue
+			}
+			if count > rule.Limit {
+				retryAfter := math.Ceil(resetAt.Sub(utils.Now()).Seconds())
+				c.Header("Retry-After", strconv.Itoa(int(math.Max(retryAfter, 1))))
+				apperrors.Abort(c, apperrors.New(http.StatusTooManyRequests, apperrors.CodeRateLimited, "too many requests"))
+				return
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/apperrors"
+	"ServiceBookingApp/internal/infrastructure/memory"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := memory.NewRateLimitStore(memory.NewStore())
+	r := gin.New()
+	r.Use(apperrors.Middleware())
+	r.POST("/book/:provider", Middleware(store,
+		Rule{Name: "ip", Limit: 2, Window: time.Hour, Key: ByIP},
+		Rule{Name: "provider", Limit: 3, Window: time.Hour, Key: func(c *gin.Context) string { return c.Param("provider") }},
+		Rule{Name: "off", Limit: 0, Window: time.Hour, Key: ByIP},
+	), func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	post := func(ip, provider string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/book/"+provider, nil)
+		req.RemoteAddr = ip + ":1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusCreated, post("10.0.0.1", "a").Code)
+	assert.Equal(t, http.StatusCreated, post("10.0.0.1", "a").Code)
+	w := post("10.0.0.1", "a")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// The request rejected by the IP rule never reached the provider's.
+	assert.Equal(t, http.StatusCreated, post("10.0.0.2", "a").Code)
+	assert.Equal(t, http.StatusTooManyRequests, post("10.0.0.3", "a").Code)
+	assert.Equal(t, http.StatusCreated, post("10.0.0.2", "b").Code)
+}
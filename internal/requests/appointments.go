@@ -0,0 +1,70 @@
+package requests
+
+import (
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+)
+
+// CreateAppointment is the body of POST /api/appointments. The provider,
+// service name and duration come from the service.
+type CreateAppointment struct {
+	ServiceId     string    `json:"service_id" binding:"required"`
+	ScheduledAt   time.Time `json:"scheduled_at" binding:"required"`
+	Notes         *Notes    `json:"notes"`
+	Status        string    `json:"status" binding:"omitempty,oneof=confirmed completed cancelled no_show"`
+	CustomerName  string    `json:"customer_name" binding:"max=200"`
+	CustomerEmail string    `json:"customer_email" binding:"omitempty,email,max=254"`
+	CustomerPhone string    `json:"customer_phone" binding:"max=50"`
+}
+
+func (r *CreateAppointment) ToDomain() *domain.Appointments {
+	return &domain.Appointments{
+		ServiceId:     r.ServiceId,
+		ScheduledAt:   r.ScheduledAt,
+		Notes:         r.Notes.ToDomain(),
+		Status:        r.Status,
+		CustomerName:  r.CustomerName,
+		CustomerEmail: r.CustomerEmail,
+		CustomerPhone: r.CustomerPhone,
+	}
+}
+
+// UpdateAppointment is the body of PUT /api/appointments/:id.
+type UpdateAppointment struct {
+	Status string `json:"status" binding:"omitempty,oneof=confirmed completed cancelled no_show"`
+}
+
+// PublicAppointment is the body of a booking from the public widget. With
+// hold_id the service and time are those of the hold. The customer's email
+// is trimmed and lowercased before use, so it is not checked strictly here.
+type PublicAppointment struct {
+	HoldId        string    `json:"hold_id"`
+	ServiceId     string    `json:"service_id" binding:"required_without=HoldId"`
+	ScheduledAt   time.Time `json:"scheduled_at" binding:"required_without=HoldId"`
+	Notes         *Notes    `json:"notes"`
+	CustomerName  string    `json:"customer_name" binding:"max=200"`
+	CustomerEmail string    `json:"customer_email" binding:"max=254"`
+	CustomerPhone string    `json:"customer_phone" binding:"max=50"`
+}
+
+func (r *PublicAppointment) ToDomain() *domain.Appointments {
+	return &domain.Appointments{
+		ServiceId:     r.ServiceId,
+		ScheduledAt:   r.ScheduledAt,
+		Notes:         r.Notes.ToDomain(),
+		CustomerName:  r.CustomerName,
+		CustomerEmail: r.CustomerEmail,
+		CustomerPhone: r.CustomerPhone,
+	}
+}
+
+// Hold is the body of POST /public/providers/:provider_id/holds.
+type Hold struct {
+	ServiceId   string    `json:"service_id" binding:"required"`
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+}
+
+func (r *Hold) ToDomain() *domain.Appointments {
+	return &domain.Appointments{ServiceId: r.ServiceId, ScheduledAt: r.ScheduledAt}
+}
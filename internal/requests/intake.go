@@ -0,0 +1,75 @@
+package requests
+
+import (
+	"fmt"
+	"regexp"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// identifierPattern matches the ids of intake fields, which clients send
+// back as field_id and may use as HTML names.
+var identifierPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// IntakeField is a question of a service's intake form. Choice fields need
+// their options; the other types take none.
+type IntakeField struct {
+	Id       string                 `json:"id" binding:"required,max=50,identifier"`
+	Label    string                 `json:"label" binding:"required,max=200"`
+	Type     domain.IntakeFieldType `json:"type" binding:"required,oneof=text choice checkbox date"`
+	Required bool                   `json:"required"`
+	Options  []string               `json:"options" binding:"max=50,dive,required,max=200"`
+}
+
+// IntakeForm is the list of fields of a service, asked in order.
+type IntakeForm []IntakeField
+
+func (f IntakeForm) ToDomain() []domain.IntakeField {
+	if len(f) == 0 {
+		return nil
+	}
+	fields := make([]domain.IntakeField, 0, len(f))
+	for _, field := range f {
+		fields = append(fields, domain.IntakeField{
+			Id:       field.Id,
+			Label:    field.Label,
+			Type:     field.Type,
+			Required: field.Required,
+			Options:  field.Options,
+		})
+	}
+	return fields
+}
+
+func validateIntakeField(sl validator.StructLevel) {
+	f := sl.Current().Interface().(IntakeField)
+	switch {
+	case f.Type == domain.IntakeChoice && len(f.Options) == 0:
+		sl.ReportError(f.Options, "options", "Options", "required", "")
+	case f.Type != domain.IntakeChoice && len(f.Options) > 0:
+		sl.ReportError(f.Options, "options", "Options", "choice_only", "")
+	}
+	seen := make(map[string]bool, len(f.Options))
+	for i, o := range f.Options {
+		if seen[o] {
+			sl.ReportError(o, fmt.Sprintf("options[%d]", i), "Options", "duplicate", "")
+		}
+		seen[o] = true
+	}
+}
+
+// validateIntakeForm checks that the ids of form are unique.
+func validateIntakeForm(sl validator.StructLevel, form IntakeForm) {
+	seen := make(map[string]bool, len(form))
+	for i, f := range form {
+		if f.Id == "" {
+			continue
+		}
+		if seen[f.Id] {
+			sl.ReportError(f.Id, fmt.Sprintf("intake_form[%d].id", i), "Id", "duplicate", "")
+		}
+		seen[f.Id] = true
+	}
+}
@@ -0,0 +1,62 @@
+package requests
+
+import "ServiceBookingApp/internal/domain"
+
+// CreateProvider is the body of POST /api/providers. Without a slug one is
+// derived from the establishment name.
+type CreateProvider struct {
+	EstablishmentName string `json:"establishment_name" binding:"required,max=200"`
+	Slug              string `json:"slug" binding:"max=100"`
+	BusinessType      string `json:"business_type" binding:"max=50"`
+	Address           string `json:"address" binding:"max=500"`
+	City              string `json:"city" binding:"max=100"`
+	Phone             string `json:"phone" binding:"max=50"`
+	AvatarUrl         string `json:"avatar_url" binding:"omitempty,url"`
+}
+
+func (r *CreateProvider) ToDomain(userId string) *domain.Providers {
+	return &domain.Providers{
+		UserId:            userId,
+		EstablishmentName: r.EstablishmentName,
+		Slug:              r.Slug,
+		BusinessType:      r.BusinessType,
+		Address:           r.Address,
+		City:              r.City,
+		Phone:             r.Phone,
+		AvatarUrl:         r.AvatarUrl,
+	}
+}
+
+// UpdateProvider is the body of PUT /api/providers/:id. Empty fields leave
+// the field as it is; a new slug is applied by the handler, which has to
+// check that it is free.
+type UpdateProvider struct {
+	EstablishmentName string `json:"establishment_name" binding:"max=200"`
+	Slug              string `json:"slug" binding:"max=100"`
+	BusinessType      string `json:"business_type" binding:"max=50"`
+	Address           string `json:"address" binding:"max=500"`
+	City              string `json:"city" binding:"max=100"`
+	Phone             string `json:"phone" binding:"max=50"`
+	AvatarUrl         string `json:"avatar_url" binding:"omitempty,url"`
+}
+
+func (r *UpdateProvider) Apply(m *domain.Providers) {
+	if r.Phone != "" {
+		m.Phone = r.Phone
+	}
+	if r.Address != "" {
+		m.Address = r.Address
+	}
+	if r.AvatarUrl != "" {
+		m.AvatarUrl = r.AvatarUrl
+	}
+	if r.EstablishmentName != "" {
+		m.EstablishmentName = r.EstablishmentName
+	}
+	if r.BusinessType != "" {
+		m.BusinessType = r.BusinessType
+	}
+	if r.City != "" {
+		m.City = r.City
+	}
+}
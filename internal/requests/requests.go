@@ -0,0 +1,127 @@
+// Package requests holds the payloads the API accepts, apart from the domain
+// structs they are stored as. Each request declares its rules in `binding`
+// tags, which gin checks with go-playground/validator while binding; this
+// package adds the rules of the domain (times of day, weekday keys, ordered
+// and disjoint ranges, intake forms) and reports every broken rule by its
+// JSON path.
+package requests
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"ServiceBookingApp/internal/apperrors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// clockPattern matches a time of day, HH:MM. 24:00 closes a range at
+// midnight.
+var clockPattern = regexp.MustCompile(`^(([01][0-9]|2[0-3]):[0-5][0-9]|24:00)$`)
+
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.RegisterValidation("clock", func(fl validator.FieldLevel) bool {
+		return clockPattern.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("identifier", func(fl validator.FieldLevel) bool {
+		return identifierPattern.MatchString(fl.Field().String())
+	})
+	v.RegisterStructValidation(validateSchedule, Schedule{})
+	v.RegisterStructValidation(validateDay, Day{})
+	v.RegisterStructValidation(validateIntakeField, IntakeField{})
+	v.RegisterStructValidation(validateCreateService, CreateService{})
+	v.RegisterStructValidation(validateUpdateService, UpdateService{})
+}
+
+// Bind decodes the JSON body of the request into req and checks its rules.
+// A body that cannot be decoded is an INVALID_BODY error, and one that
+// breaks rules a VALIDATION_FAILED error listing them.
+func Bind(c *gin.Context, req interface{}) error {
+	err := c.ShouldBindJSON(req)
+	if err == nil {
+		return nil
+	}
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return apperrors.InvalidBody(err)
+	}
+	fields := make([]apperrors.FieldError, 0, len(invalid))
+	for _, fe := range invalid {
+		fields = append(fields, apperrors.FieldError{Field: fieldPath(fe), Message: message(fe)})
+	}
+	return apperrors.Invalid(fields)
+}
+
+// fieldPath drops the request type from the namespace of fe, leaving its
+// JSON path.
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_without":
+		return "is required"
+	case "email":
+		return "must be an email address"
+	case "url":
+		return "must be a URL"
+	case "hexcolor":
+		return "must be a hex color such as #1a2b3c"
+	case "clock":
+		return "must be a time of day as HH:MM"
+	case "identifier":
+		return "must be lowercase letters, digits, _ or -"
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	case "min", "gte":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters long", fe.Param())
+		}
+		return "must be at least " + fe.Param()
+	case "max", "lte":
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be at most %s characters long", fe.Param())
+		case reflect.Slice:
+			return fmt.Sprintf("must have at most %s items", fe.Param())
+		}
+		return "must be at most " + fe.Param()
+	case "end_after_start":
+		return "must be after start"
+	case "overlap":
+		return "overlaps another range of the day"
+	case "valid_to_after_from":
+		return "must be after valid_from"
+	case "active_to_after_from":
+		return "must be after active_from"
+	case "unique":
+		return "must not repeat items"
+	case "duplicate":
+		return "is repeated"
+	case "choice_only":
+		return "only choice fields take options"
+	default:
+		return "is invalid"
+	}
+}
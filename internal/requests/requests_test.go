@@ -0,0 +1,176 @@
+package requests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/apperrors"
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bind runs Bind on body and returns the invalid fields, by path, with
+// their messages.
+func bind(t *testing.T, req interface{}, body string) (map[string]string, error) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	err := Bind(c, req)
+	var e *apperrors.Error
+	if !errors.As(err, &e) || e.Code != apperrors.CodeValidationFailed {
+		return nil, err
+	}
+	fields := map[string]string{}
+	for _, f := range e.Fields {
+		fields[f.Field] = f.Message
+	}
+	return fields, nil
+}
+
+func TestBindSchedule(t *testing.T) {
+	fields, err := bind(t, &Schedule{}, `{
+		"days": {
+			"mon": {"enabled": true, "ranges": [{"start": "09:00", "end": "13:00"}, {"start": "14:00", "end": "24:00"}]},
+			"tue": {"enabled": true, "ranges": [{"start": "14:00", "end": "18:00"}, {"start": "09:00", "end": "12:00"}]}
+		}
+	}`)
+	require.NoError(t, err)
+	assert.Empty(t, fields, "unordered but disjoint ranges are fine")
+
+	fields, err = bind(t, &Schedule{}, `{
+		"type": "weekly",
+		"days": {
+			"funday": {"enabled": true},
+			"mon": {"enabled": true, "ranges": [{"start": "25:99", "end": "13:00"}, {"start": "18:00", "end": "17:00"}]},
+			"tue": {"enabled": true, "ranges": [{"start": "09:00", "end": "17:00"}, {"start": "10:00", "end": "11:00"}, {"start": "12:00", "end": "13:00"}]}
+		},
+		"valid_from": "2030-02-01T00:00:00Z",
+		"valid_to": "2030-01-01T00:00:00Z"
+	}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"type":                      "must be one of: global custom",
+		"days[funday]":              "must be one of: sun mon tue wed thu fri sat",
+		"days[mon].ranges[0].start": "must be a time of day as HH:MM",
+		"days[mon].ranges[1].end":   "must be after start",
+		"days[tue].ranges[1]":       "overlaps another range of the day",
+		"days[tue].ranges[2]":       "overlaps another range of the day",
+		"valid_to":                  "must be after valid_from",
+	}, fields)
+}
+
+func TestBindService(t *testing.T) {
+	fields, err := bind(t, &CreateService{}, `{"title": "Corte", "duration_minutes": 30, "price": 10, "color": "#1a2b3c"}`)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	fields, err = bind(t, &CreateService{}, `{"duration_minutes": 100000, "price": -1, "color": "red"}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"title":            "is required",
+		"duration_minutes": "must be at most 1440",
+		"price":            "must be at least 0",
+		"color":            "must be a hex color such as #1a2b3c",
+	}, fields)
+
+	_, err = bind(t, &CreateService{}, `{"title": `)
+	var e *apperrors.Error
+	require.ErrorAs(t, err, &e)
+	assert.Equal(t, apperrors.CodeInvalidBody, e.Code)
+}
+
+func TestBindServiceCatalog(t *testing.T) {
+	fields, err := bind(t, &CreateService{}, `{"title": "Peinado", "duration_minutes": 60, "sort_order": -1,
+		"active_from": "2030-12-01T00:00:00Z", "active_to": "2030-11-01T00:00:00Z"}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"sort_order": "must be at least 0",
+		"active_to":  "must be after active_from",
+	}, fields)
+
+	var req UpdateService
+	fields, err = bind(t, &req, `{"category": "", "price": 0, "private": false, "active_from": null, "active_to": "2031-01-01T00:00:00Z"}`)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	from := time.Date(2030, 12, 1, 0, 0, 0, 0, time.UTC)
+	m := &domain.Services{Category: "Eventos", Price: 1500, SortOrder: 3, Private: true, ActiveFrom: &from}
+	req.Apply(m)
+	assert.Equal(t, "", m.Category)
+	assert.Zero(t, m.Price, "a price may be set back to 0")
+	assert.Equal(t, 3, m.SortOrder)
+	assert.False(t, m.Private)
+	assert.Nil(t, m.ActiveFrom)
+	require.NotNil(t, m.ActiveTo)
+	assert.Equal(t, 2031, m.ActiveTo.Year())
+
+	fields, err = bind(t, &ReorderServices{}, `{"service_ids": ["a", "b", "a"]}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"service_ids": "must not repeat items"}, fields)
+
+	fields, err = bind(t, &ReorderServices{}, `{"service_ids": ["a", ""]}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"service_ids[1]": "is required"}, fields)
+}
+
+func TestBindIntakeForm(t *testing.T) {
+	fields, err := bind(t, &CreateService{}, `{"title": "Tintura", "duration_minutes": 60, "intake_form": [
+		{"id": "allergies", "label": "¿Alergias?", "type": "text", "required": true},
+		{"id": "length", "label": "Largo", "type": "choice", "options": ["corto", "largo"]}
+	]}`)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	fields, err = bind(t, &UpdateService{}, `{"intake_form": [
+		{"id": "Allergies!", "label": "¿Alergias?", "type": "text", "options": ["sí"]},
+		{"id": "length", "label": "Largo", "type": "choice"},
+		{"id": "length", "label": "Color", "type": "color"},
+		{"id": "size", "label": "Talle", "type": "choice", "options": ["s", "s"]}
+	]}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"intake_form[0].id":         "must be lowercase letters, digits, _ or -",
+		"intake_form[0].options":    "only choice fields take options",
+		"intake_form[1].options":    "is required",
+		"intake_form[2].type":       "must be one of: text choice checkbox date",
+		"intake_form[2].id":         "is repeated",
+		"intake_form[3].options[1]": "is repeated",
+	}, fields)
+}
+
+func TestBindPublicAppointment(t *testing.T) {
+	fields, err := bind(t, &PublicAppointment{}, `{"hold_id": "h1"}`)
+	require.NoError(t, err)
+	assert.Empty(t, fields, "a hold carries the service and time")
+
+	fields, err = bind(t, &PublicAppointment{}, `{}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"service_id": "is required", "scheduled_at": "is required"}, fields)
+
+	fields, err = bind(t, &PublicAppointment{}, `{"hold_id": "h1", "notes": {"text": "hola", "answers": [{"answer": "sí"}]}}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"notes.answers[0].question": "is required"}, fields)
+
+	var req PublicAppointment
+	fields, err = bind(t, &req, `{"hold_id": "h1", "notes": {"answers": [{"field_id": "consent", "answer": true}]}}`)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+	assert.Equal(t, "true", req.Notes.Answers[0].Answer, "checkboxes may be answered with booleans")
+}
+
+func TestNotesAcceptsText(t *testing.T) {
+	var req CreateAppointment
+	fields, err := bind(t, &req, `{"service_id": "s1", "scheduled_at": "2030-01-07T10:00:00Z", "notes": "traer foto"}`)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+	assert.Equal(t, "traer foto", req.ToDomain().Notes.Text)
+}
@@ -0,0 +1,65 @@
+package requests
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"ServiceBookingApp/internal/domain"
+)
+
+// Notes are the notes of an appointment. A bare JSON string is accepted as
+// the text, the shape clients sent before notes were typed.
+type Notes struct {
+	Text    string         `json:"text" binding:"max=2000"`
+	Answers []IntakeAnswer `json:"answers" binding:"max=50,dive"`
+}
+
+// IntakeAnswer answers a field of the service's intake form, by field_id,
+// or a free question. Checkbox answers may be sent as JSON booleans.
+type IntakeAnswer struct {
+	FieldId  string `json:"field_id" binding:"max=50"`
+	Question string `json:"question" binding:"required_without=FieldId,max=200"`
+	Answer   string `json:"answer" binding:"max=2000"`
+}
+
+func (a *IntakeAnswer) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		FieldId  string          `json:"field_id"`
+		Question string          `json:"question"`
+		Answer   json.RawMessage `json:"answer"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*a = IntakeAnswer{FieldId: raw.FieldId, Question: raw.Question}
+	var checked bool
+	if err := json.Unmarshal(raw.Answer, &checked); err == nil {
+		a.Answer = strconv.FormatBool(checked)
+		return nil
+	}
+	if len(raw.Answer) == 0 || string(raw.Answer) == "null" {
+		return nil
+	}
+	return json.Unmarshal(raw.Answer, &a.Answer)
+}
+
+func (n *Notes) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		*n = Notes{}
+		return json.Unmarshal(data, &n.Text)
+	}
+	type notes Notes
+	return json.Unmarshal(data, (*notes)(n))
+}
+
+func (n *Notes) ToDomain() domain.Notes {
+	if n == nil {
+		return domain.Notes{}
+	}
+	m := domain.Notes{Text: n.Text}
+	for _, a := range n.Answers {
+		m.Answers = append(m.Answers, domain.IntakeAnswer{FieldId: a.FieldId, Question: a.Question, Answer: a.Answer})
+	}
+	return m
+}
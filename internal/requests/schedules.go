@@ -0,0 +1,94 @@
+package requests
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Schedule is the body of PUT /api/schedules. Days are keyed by the
+// weekdays of booking: sun, mon, tue, wed, thu, fri and sat. The validator
+// only descends into map values that have a tag after endkeys, hence the
+// omitempty.
+type Schedule struct {
+	Type      domain.ScheduleType `json:"type" binding:"omitempty,oneof=global custom"`
+	Days      map[string]Day      `json:"days" binding:"dive,keys,oneof=sun mon tue wed thu fri sat,endkeys,omitempty"`
+	ValidFrom *time.Time          `json:"valid_from"`
+	ValidTo   *time.Time          `json:"valid_to"`
+}
+
+// Day is the opening hours of one weekday. Its ranges must each start
+// before they end and must not overlap.
+type Day struct {
+	Enabled bool        `json:"enabled"`
+	Ranges  []TimeRange `json:"ranges" binding:"dive"`
+}
+
+type TimeRange struct {
+	Start string `json:"start" binding:"required,clock"`
+	End   string `json:"end" binding:"required,clock"`
+}
+
+// ToDomain returns the schedule to store for providerId. A schedule
+// without a type is the global one, the same default reads use.
+func (r *Schedule) ToDomain(providerId string) *domain.Schedule {
+	s := &domain.Schedule{
+		ProviderId: providerId,
+		Type:       r.Type,
+		Days:       make(map[string]domain.DaySchedule, len(r.Days)),
+		ValidFrom:  r.ValidFrom,
+		ValidTo:    r.ValidTo,
+	}
+	if s.Type == "" {
+		s.Type = domain.ScheduleTypeGlobal
+	}
+	for key, day := range r.Days {
+		ranges := make([]domain.TimeRange, 0, len(day.Ranges))
+		for _, tr := range day.Ranges {
+			ranges = append(ranges, domain.TimeRange{Start: tr.Start, End: tr.End})
+		}
+		s.Days[key] = domain.DaySchedule{Enabled: day.Enabled, Ranges: ranges}
+	}
+	return s
+}
+
+func validateSchedule(sl validator.StructLevel) {
+	s := sl.Current().Interface().(Schedule)
+	if s.ValidFrom != nil && s.ValidTo != nil && !s.ValidTo.After(*s.ValidFrom) {
+		sl.ReportError(s.ValidTo, "valid_to", "ValidTo", "valid_to_after_from", "")
+	}
+}
+
+// validateDay checks the ranges whose times are well formed; the others
+// already fail the clock rule. Zero-padded times compare as strings.
+func validateDay(sl validator.StructLevel) {
+	day := sl.Current().Interface().(Day)
+
+	var valid []int
+	for i, tr := range day.Ranges {
+		if !clockPattern.MatchString(tr.Start) || !clockPattern.MatchString(tr.End) {
+			continue
+		}
+		if tr.End <= tr.Start {
+			sl.ReportError(tr.End, fmt.Sprintf("ranges[%d].end", i), "End", "end_after_start", "")
+			continue
+		}
+		valid = append(valid, i)
+	}
+
+	sort.Slice(valid, func(a, b int) bool { return day.Ranges[valid[a]].Start < day.Ranges[valid[b]].Start })
+	end := ""
+	for _, i := range valid {
+		tr := day.Ranges[i]
+		if tr.Start < end {
+			sl.ReportError(tr, fmt.Sprintf("ranges[%d]", i), "Ranges", "overlap", "")
+		}
+		if tr.End > end {
+			end = tr.End
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package requests
+
+import (
+	"encoding/json"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/richtext"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// CreateService is the body of POST /api/services. The description is
+// Markdown; its HTML rendering is stored with it.
+type CreateService struct {
+	Title           string     `json:"title" binding:"required,max=200"`
+	Description     string     `json:"description" binding:"max=10000"`
+	DurationMinutes int        `json:"duration_minutes" binding:"required,min=5,max=1440"`
+	Price           float64    `json:"price" binding:"gte=0"`
+	IconUrl         string     `json:"icon_url" binding:"omitempty,url"`
+	Color           string     `json:"color" binding:"omitempty,hexcolor"`
+	IntakeForm      IntakeForm `json:"intake_form" binding:"max=30,dive"`
+	Category        string     `json:"category" binding:"max=100"`
+	SortOrder       int        `json:"sort_order" binding:"gte=0"`
+	Private         bool       `json:"private"`
+	ActiveFrom      *time.Time `json:"active_from"`
+	ActiveTo        *time.Time `json:"active_to"`
+}
+
+func (r *CreateService) ToDomain(providerId string) *domain.Services {
+	return &domain.Services{
+		ProviderId:      providerId,
+		Title:           r.Title,
+		Description:     richtext.NewDescription(r.Description),
+		DurationMinutes: r.DurationMinutes,
+		Price:           r.Price,
+		IconUrl:         r.IconUrl,
+		Color:           r.Color,
+		IntakeForm:      r.IntakeForm.ToDomain(),
+		Category:        r.Category,
+		SortOrder:       r.SortOrder,
+		Private:         r.Private,
+		ActiveFrom:      r.ActiveFrom,
+		ActiveTo:        r.ActiveTo,
+	}
+}
+
+// UpdateService is the body of PUT /api/services/:id. Zero values leave
+// the field as it is, except for the fields that may be cleared: the
+// description and category by an empty string, the price by 0, the intake
+// form by an empty list and the bounds of the active range by null.
+type UpdateService struct {
+	Title           string       `json:"title" binding:"max=200"`
+	Description     *string      `json:"description" binding:"omitempty,max=10000"`
+	DurationMinutes int          `json:"duration_minutes" binding:"omitempty,min=5,max=1440"`
+	Price           *float64     `json:"price" binding:"omitempty,gte=0"`
+	IconUrl         string       `json:"icon_url" binding:"omitempty,url"`
+	Color           string       `json:"color" binding:"omitempty,hexcolor"`
+	IntakeForm      *IntakeForm  `json:"intake_form" binding:"omitempty,max=30,dive"`
+	Category        *string      `json:"category" binding:"omitempty,max=100"`
+	SortOrder       *int         `json:"sort_order" binding:"omitempty,gte=0"`
+	Private         *bool        `json:"private"`
+	ActiveFrom      OptionalTime `json:"active_from" swaggertype:"string" format:"date-time"`
+	ActiveTo        OptionalTime `json:"active_to" swaggertype:"string" format:"date-time"`
+}
+
+func (r *UpdateService) Apply(m *domain.Services) {
+	if r.Title != "" {
+		m.Title = r.Title
+	}
+	if r.Description != nil {
+		m.Description = richtext.NewDescription(*r.Description)
+	}
+	if r.DurationMinutes != 0 {
+		m.DurationMinutes = r.DurationMinutes
+	}
+	if r.Price != nil {
+		m.Price = *r.Price
+	}
+	if r.IconUrl != "" {
+		m.IconUrl = r.IconUrl
+	}
+	if r.Color != "" {
+		m.Color = r.Color
+	}
+	if r.IntakeForm != nil {
+		m.IntakeForm = r.IntakeForm.ToDomain()
+	}
+	if r.Category != nil {
+		m.Category = *r.Category
+	}
+	if r.SortOrder != nil {
+		m.SortOrder = *r.SortOrder
+	}
+	if r.Private != nil {
+		m.Private = *r.Private
+	}
+	if r.ActiveFrom.Set {
+		m.ActiveFrom = r.ActiveFrom.Time
+	}
+	if r.ActiveTo.Set {
+		m.ActiveTo = r.ActiveTo.Time
+	}
+}
+
+// OptionalTime is a time a PUT may clear: null clears it, and leaving the
+// field out keeps it.
+type OptionalTime struct {
+	Set  bool
+	Time *time.Time
+}
+
+func (t *OptionalTime) UnmarshalJSON(data []byte) error {
+	t.Set = true
+	return json.Unmarshal(data, &t.Time)
+}
+
+// ReorderServices is the body of PUT /api/services/order: the IDs of the
+// caller's services in the order to list them. Services left out follow
+// them in their current order.
+type ReorderServices struct {
+	ServiceIds []string `json:"service_ids" binding:"required,min=1,max=500,unique,dive,required"`
+}
+
+func validateCreateService(sl validator.StructLevel) {
+	r := sl.Current().Interface().(CreateService)
+	validateIntakeForm(sl, r.IntakeForm)
+	validateActiveRange(sl, r.ActiveFrom, r.ActiveTo)
+}
+
+func validateUpdateService(sl validator.StructLevel) {
+	r := sl.Current().Interface().(UpdateService)
+	if r.IntakeForm != nil {
+		validateIntakeForm(sl, *r.IntakeForm)
+	}
+	validateActiveRange(sl, r.ActiveFrom.Time, r.ActiveTo.Time)
+}
+
+// validateActiveRange checks the bounds sent together; the handler checks
+// the range a PUT leaves once applied.
+func validateActiveRange(sl validator.StructLevel, from, to *time.Time) {
+	if from != nil && to != nil && !to.After(*from) {
+		sl.ReportError(to, "active_to", "ActiveTo", "active_to_after_from", "")
+	}
+}
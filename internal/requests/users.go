@@ -0,0 +1,38 @@
+package requests
+
+import "ServiceBookingApp/internal/domain"
+
+// User is the body of POST /api/users and PUT /api/users/:id. On update,
+// empty fields leave the field as it is.
+type User struct {
+	Email    string `json:"email" binding:"omitempty,email,max=254"`
+	Name     string `json:"name" binding:"max=200"`
+	Picture  string `json:"picture" binding:"omitempty,url"`
+	RoleId   string `json:"role_id" binding:"omitempty,oneof=admin user"`
+	IsActive *bool  `json:"is_active"`
+}
+
+func (r *User) ToDomain() *domain.Users {
+	return &domain.Users{
+		Email:    r.Email,
+		Name:     r.Name,
+		Picture:  r.Picture,
+		RoleId:   r.RoleId,
+		IsActive: r.IsActive,
+	}
+}
+
+func (r *User) Apply(m *domain.Users) {
+	if r.Name != "" {
+		m.Name = r.Name
+	}
+	if r.Email != "" {
+		m.Email = r.Email
+	}
+	if r.Picture != "" {
+		m.Picture = r.Picture
+	}
+	if r.RoleId != "" {
+		m.RoleId = r.RoleId
+	}
+}
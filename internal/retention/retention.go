@@ -0,0 +1,66 @@
+package retention
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+)
+
+// Job hard-deletes documents that have been soft-deleted for longer than the
+// retention period.
+type Job struct {
+	retention time.Duration
+	purgers   map[string]domain.Purger
+}
+
+func NewJob(retention time.Duration, purgers map[string]domain.Purger) *Job {
+	return &Job{retention: retention, purgers: purgers}
+}
+
+// PurgeOnce runs every purger once and returns how many documents each one
+// removed. It keeps going after a failure and returns the first error.
+func (j *Job) PurgeOnce(ctx context.Context) (map[string]int, error) {
+	cutoff := utils.Now().Add(-j.retention)
+
+	names := make([]string, 0, len(j.purgers))
+	for name := range j.purgers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	counts := make(map[string]int, len(names))
+	var firstErr error
+	for _, name := range names {
+		n, err := j.purgers[name].Purge(ctx, cutoff)
+		if err != nil {
+			log.Printf("retention: purging %s: %v", name, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		counts[name] = n
+		if n > 0 {
+			log.Printf("retention: purged %d %s deleted before %s", n, name, cutoff.Format(time.RFC3339))
+		}
+	}
+	return counts, firstErr
+}
+
+// Run calls PurgeOnce right away and then on every tick until ctx is done.
+func (j *Job) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		j.PurgeOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
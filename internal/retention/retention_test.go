@@ -0,0 +1,41 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/infrastructure/memory"
+	"ServiceBookingApp/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeOnce(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewServicesRepository(memory.NewStore())
+
+	old := utils.Now().Add(-100 * 24 * time.Hour)
+	recent := utils.Now().Add(-time.Hour)
+	for _, s := range []*domain.Services{
+		{ID: "live", ProviderId: "p"},
+		{ID: "old", ProviderId: "p", DeletedAt: &old},
+		{ID: "recent", ProviderId: "p", DeletedAt: &recent},
+	} {
+		require.NoError(t, repo.Update(ctx, s.ID, s))
+	}
+
+	job := NewJob(90*24*time.Hour, map[string]domain.Purger{"services": repo})
+	counts, err := job.PurgeOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"services": 1}, counts)
+
+	_, err = repo.GetIncludingDeleted(ctx, "old")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = repo.GetIncludingDeleted(ctx, "recent")
+	assert.NoError(t, err)
+	_, err = repo.Get(ctx, "live")
+	assert.NoError(t, err)
+}
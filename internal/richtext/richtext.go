@@ -0,0 +1,76 @@
+// Package richtext renders the Markdown of service descriptions to HTML
+// that is safe to embed in the provider's pages and the booking widget,
+// and converts the free-form notes and descriptions stored before they
+// were typed.
+package richtext
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	// policy allows the formatting Markdown produces and drops scripts,
+	// styles, event handlers and raw HTML that is not on the list. Links
+	// get rel="nofollow noopener" and open in a new tab.
+	policy = bluemonday.UGCPolicy().AddTargetBlankToFullyQualifiedLinks(true)
+)
+
+// Render converts md to sanitized HTML.
+func Render(md string) string {
+	if md == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(md), &buf); err != nil {
+		// goldmark only fails when writing to buf fails, which it does not.
+		return policy.Sanitize(md)
+	}
+	return policy.Sanitize(buf.String())
+}
+
+// NewDescription returns the description with md as source.
+func NewDescription(md string) domain.Description {
+	return domain.Description{Markdown: md, HTML: Render(md)}
+}
+
+// LegacyNotes converts notes stored as an arbitrary JSON value. Strings
+// become the text, objects with a "text" string keep it, and anything else
+// is kept as its JSON encoding so nothing is lost.
+func LegacyNotes(v interface{}) domain.Notes {
+	return domain.Notes{Text: legacyText(v, "text")}
+}
+
+// LegacyDescription converts a description stored as an arbitrary JSON
+// value the same way as LegacyNotes, reading objects' "markdown" or "text"
+// string as the Markdown source.
+func LegacyDescription(v interface{}) domain.Description {
+	return NewDescription(legacyText(v, "markdown", "text"))
+}
+
+func legacyText(v interface{}, keys ...string) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}:
+		for _, key := range keys {
+			if s, ok := v[key].(string); ok {
+				return s
+			}
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
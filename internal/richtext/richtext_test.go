@@ -0,0 +1,31 @@
+package richtext
+
+import (
+	"testing"
+
+	"ServiceBookingApp/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	assert.Equal(t, "<p>Corte <strong>clásico</strong></p>\n", Render("Corte **clásico**"))
+	assert.Equal(t, "", Render(""))
+
+	html := Render("Hola <script>alert(1)</script> [link](javascript:alert(1)) <img src=x onerror=alert(1)>")
+	assert.NotContains(t, html, "<script")
+	assert.NotContains(t, html, "javascript:")
+	assert.NotContains(t, html, "onerror")
+
+	assert.Contains(t, Render("[web](https://example.com)"), `rel="nofollow noopener" target="_blank"`)
+}
+
+func TestLegacy(t *testing.T) {
+	assert.Equal(t, domain.Notes{Text: "first visit"}, LegacyNotes("first visit"))
+	assert.Equal(t, domain.Notes{Text: "x"}, LegacyNotes(map[string]interface{}{"text": "x"}))
+	assert.Equal(t, domain.Notes{Text: `["a","b"]`}, LegacyNotes([]interface{}{"a", "b"}))
+	assert.Equal(t, domain.Notes{}, LegacyNotes(nil))
+
+	assert.Equal(t, domain.Description{Markdown: "Corte", HTML: "<p>Corte</p>\n"}, LegacyDescription(map[string]interface{}{"text": "Corte"}))
+	assert.Equal(t, domain.Description{}, LegacyDescription(nil))
+}
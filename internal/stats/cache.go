@@ -0,0 +1,51 @@
+package stats
+
+import (
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	summary   *Summary
+	expiresAt time.Time
+}
+
+// cache is a TTL map of computed summaries. Expired entries are dropped on
+// access and whenever a new summary is stored.
+type cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+func newCache(ttl time.Duration) *cache {
+	return &cache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+func (c *cache) get(key string) (*Summary, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.summary, true
+}
+
+func (c *cache) set(key string, summary *Summary) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{summary: summary, expiresAt: now.Add(c.ttl)}
+}
@@ -0,0 +1,245 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+)
+
+// Summary is the dashboard view of a provider over [From, To).
+type Summary struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	Total          int            `json:"total"`
+	CountsByStatus map[string]int `json:"counts_by_status"`
+
+	Revenue float64 `json:"revenue"`
+
+	BookedMinutes    int     `json:"booked_minutes"`
+	ScheduledMinutes int     `json:"scheduled_minutes"`
+	Utilization      float64 `json:"utilization"`
+
+	ByWeekday map[string]int `json:"by_weekday"`
+	ByHour    map[int]int    `json:"by_hour"`
+
+	TopServices []ServiceStat `json:"top_services"`
+
+	NoShowRate float64 `json:"no_show_rate"`
+
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+type ServiceStat struct {
+	ServiceId   string  `json:"service_id"`
+	ServiceName string  `json:"service_name"`
+	Count       int     `json:"count"`
+	Revenue     float64 `json:"revenue"`
+}
+
+const topServicesLimit = 5
+
+var weekdayKeys = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Service computes summaries from the repositories and keeps them in a
+// short-lived cache so the dashboard does not rescan appointments on every
+// page load.
+type Service struct {
+	appointmentsRepo domain.AppointmentsRepository
+	servicesRepo     domain.ServicesRepository
+	schedulesRepo    domain.SchedulesRepository
+	cache            *cache
+}
+
+func NewService(appointmentsRepo domain.AppointmentsRepository, servicesRepo domain.ServicesRepository, schedulesRepo domain.SchedulesRepository, ttl time.Duration) *Service {
+	return &Service{
+		appointmentsRepo: appointmentsRepo,
+		servicesRepo:     servicesRepo,
+		schedulesRepo:    schedulesRepo,
+		cache:            newCache(ttl),
+	}
+}
+
+func (s *Service) Summary(ctx context.Context, providerId string, from, to time.Time) (*Summary, error) {
+	key := fmt.Sprintf("%s|%d|%d", providerId, from.Unix(), to.Unix())
+	if summary, ok := s.cache.get(key); ok {
+		return summary, nil
+	}
+
+	appointments, err := s.listAppointments(ctx, providerId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	services, err := s.listServices(ctx, providerId)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := s.schedulesRepo.GetByProvider(ctx, providerId, domain.ScheduleTypeGlobal)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := summarize(appointments, services, schedule, from, to, utils.Now())
+	s.cache.set(key, summary)
+	return summary, nil
+}
+
+func (s *Service) listAppointments(ctx context.Context, providerId string, from, to time.Time) ([]*domain.Appointments, error) {
+	filter := domain.AppointmentsFilter{
+		ListOptions: domain.ListOptions{Limit: domain.MaxPageSize, IncludeDeleted: true},
+		ProviderId:  providerId,
+		From:        from,
+		To:          to,
+	}
+	var results []*domain.Appointments
+	for {
+		page, nextCursor, err := s.appointmentsRepo.List(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, page...)
+		if nextCursor == "" {
+			return results, nil
+		}
+		filter.Cursor = nextCursor
+	}
+}
+
+func (s *Service) listServices(ctx context.Context, providerId string) ([]*domain.Services, error) {
+	opts := domain.ListOptions{Limit: domain.MaxPageSize, IncludeDeleted: true}
+	var results []*domain.Services
+	for {
+		page, nextCursor, err := s.servicesRepo.List(ctx, opts, providerId)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, page...)
+		if nextCursor == "" {
+			return results, nil
+		}
+		opts.Cursor = nextCursor
+	}
+}
+
+// summarize is the pure part of Summary. Cancelled and deleted appointments
+// are counted by status but excluded from revenue, utilization and the
+// busiest-time breakdowns. The no-show rate is taken over appointments that
+// already happened.
+func summarize(appointments []*domain.Appointments, services []*domain.Services, schedule *domain.Schedule, from, to, now time.Time) *Summary {
+	summary := &Summary{
+		From:           from,
+		To:             to,
+		CountsByStatus: make(map[string]int),
+		ByWeekday:      make(map[string]int),
+		ByHour:         make(map[int]int),
+		TopServices:    []ServiceStat{},
+		GeneratedAt:    now,
+	}
+
+	servicesByID := make(map[string]*domain.Services, len(services))
+	for _, s := range services {
+		servicesByID[s.ID] = s
+	}
+
+	perService := make(map[string]*ServiceStat)
+	var pastCount, noShowCount int
+
+	for _, appt := range appointments {
+		status := appt.Status
+		if appt.DeletedAt != nil && status != domain.StatusCancelled {
+			status = domain.StatusCancelled
+		}
+		summary.Total++
+		summary.CountsByStatus[status]++
+
+		if status == domain.StatusCancelled {
+			continue
+		}
+
+		if status != domain.StatusNoShow {
+			summary.Revenue += appt.Price
+		}
+
+		dur := appt.DurationMinutes
+		if dur == 0 {
+			if s, ok := servicesByID[appt.ServiceId]; ok {
+				dur = s.DurationMinutes
+			}
+		}
+		summary.BookedMinutes += dur
+
+		local := appt.ScheduledAt.In(utils.ArgentinaLocation)
+		summary.ByWeekday[weekdayKeys[local.Weekday()]]++
+		summary.ByHour[local.Hour()]++
+
+		stat, ok := perService[appt.ServiceId]
+		if !ok {
+			stat = &ServiceStat{ServiceId: appt.ServiceId, ServiceName: appt.ServiceName}
+			perService[appt.ServiceId] = stat
+		}
+		stat.Count++
+		if status != domain.StatusNoShow {
+			stat.Revenue += appt.Price
+		}
+
+		if appt.ScheduledAt.Before(now) {
+			pastCount++
+			if status == domain.StatusNoShow {
+				noShowCount++
+			}
+		}
+	}
+
+	summary.ScheduledMinutes = scheduledMinutes(schedule, from, to)
+	if summary.ScheduledMinutes > 0 {
+		summary.Utilization = float64(summary.BookedMinutes) / float64(summary.ScheduledMinutes)
+	}
+	if pastCount > 0 {
+		summary.NoShowRate = float64(noShowCount) / float64(pastCount)
+	}
+
+	for _, stat := range perService {
+		summary.TopServices = append(summary.TopServices, *stat)
+	}
+	sort.Slice(summary.TopServices, func(i, j int) bool {
+		a, b := summary.TopServices[i], summary.TopServices[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.ServiceId < b.ServiceId
+	})
+	if len(summary.TopServices) > topServicesLimit {
+		summary.TopServices = summary.TopServices[:topServicesLimit]
+	}
+
+	return summary
+}
+
+// scheduledMinutes adds up the enabled working ranges of every day in
+// [from, to).
+func scheduledMinutes(schedule *domain.Schedule, from, to time.Time) int {
+	if schedule == nil || len(schedule.Days) == 0 {
+		return 0
+	}
+
+	total := 0
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for day.Before(to) {
+		if ds, ok := schedule.Days[weekdayKeys[day.Weekday()]]; ok && ds.Enabled {
+			for _, r := range ds.Ranges {
+				var startH, startM, endH, endM int
+				fmt.Sscanf(r.Start, "%d:%d", &startH, &startM)
+				fmt.Sscanf(r.End, "%d:%d", &endH, &endM)
+				if mins := (endH*60 + endM) - (startH*60 + startM); mins > 0 {
+					total += mins
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return total
+}
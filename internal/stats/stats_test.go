@@ -0,0 +1,69 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"ServiceBookingApp/internal/domain"
+	"ServiceBookingApp/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	loc := utils.ArgentinaLocation
+	// Monday 2024-05-06 to Monday 2024-05-13.
+	from := time.Date(2024, 5, 6, 0, 0, 0, 0, loc)
+	to := from.AddDate(0, 0, 7)
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, loc)
+
+	// Revenue counts the price each appointment was booked at, not the
+	// service's current one.
+	services := []*domain.Services{
+		{ID: "cut", Title: "Corte", Price: 1200, DurationMinutes: 30},
+		{ID: "dye", Title: "Tintura", Price: 5000, DurationMinutes: 90},
+	}
+	schedule := &domain.Schedule{
+		Days: map[string]domain.DaySchedule{
+			"mon": {Enabled: true, Ranges: []domain.TimeRange{{Start: "09:00", End: "13:00"}}},
+			"tue": {Enabled: true, Ranges: []domain.TimeRange{{Start: "09:00", End: "13:00"}}},
+			"wed": {Enabled: false, Ranges: []domain.TimeRange{{Start: "09:00", End: "13:00"}}},
+		},
+	}
+	deleted := now
+	appointments := []*domain.Appointments{
+		{ServiceId: "cut", ServiceName: "Corte", Price: 1000, Status: domain.StatusConfirmed, ScheduledAt: time.Date(2024, 5, 6, 9, 0, 0, 0, loc), DurationMinutes: 30},
+		{ServiceId: "cut", ServiceName: "Corte", Price: 1000, Status: domain.StatusNoShow, ScheduledAt: time.Date(2024, 5, 6, 10, 0, 0, 0, loc), DurationMinutes: 30},
+		{ServiceId: "dye", ServiceName: "Tintura", Price: 5000, Status: domain.StatusConfirmed, ScheduledAt: time.Date(2024, 5, 7, 9, 0, 0, 0, loc)},
+		{ServiceId: "cut", ServiceName: "Corte", Price: 1000, Status: domain.StatusCancelled, ScheduledAt: time.Date(2024, 5, 7, 11, 0, 0, 0, loc), DurationMinutes: 30},
+		{ServiceId: "cut", ServiceName: "Corte", Price: 1000, Status: domain.StatusConfirmed, ScheduledAt: time.Date(2024, 5, 11, 9, 0, 0, 0, loc), DurationMinutes: 30, DeletedAt: &deleted},
+	}
+
+	s := summarize(appointments, services, schedule, from, to, now)
+
+	assert.Equal(t, 5, s.Total)
+	assert.Equal(t, map[string]int{domain.StatusConfirmed: 2, domain.StatusNoShow: 1, domain.StatusCancelled: 2}, s.CountsByStatus)
+	assert.Equal(t, 6000.0, s.Revenue)
+	assert.Equal(t, 150, s.BookedMinutes)
+	assert.Equal(t, 480, s.ScheduledMinutes)
+	assert.InDelta(t, 150.0/480.0, s.Utilization, 1e-9)
+	assert.Equal(t, map[string]int{"mon": 2, "tue": 1}, s.ByWeekday)
+	assert.Equal(t, map[int]int{9: 2, 10: 1}, s.ByHour)
+	assert.InDelta(t, 1.0/3.0, s.NoShowRate, 1e-9)
+	assert.Equal(t, []ServiceStat{
+		{ServiceId: "cut", ServiceName: "Corte", Count: 2, Revenue: 1000},
+		{ServiceId: "dye", ServiceName: "Tintura", Count: 1, Revenue: 5000},
+	}, s.TopServices)
+}
+
+func TestCacheExpires(t *testing.T) {
+	c := newCache(10 * time.Millisecond)
+	c.set("k", &Summary{Total: 1})
+
+	got, ok := c.get("k")
+	assert.True(t, ok)
+	assert.Equal(t, 1, got.Total)
+
+	time.Sleep(20 * time.Millisecond)
+	_, ok = c.get("k")
+	assert.False(t, ok)
+}
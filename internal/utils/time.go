@@ -20,3 +20,12 @@ func Now() time.Time {
 func NowUTC() time.Time {
 	return time.Now().UTC()
 }
+
+// ParseDateOrTime accepts either an RFC3339 timestamp or a plain
+// "2006-01-02" date, which is taken as midnight in Argentina.
+func ParseDateOrTime(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, ArgentinaLocation); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -1,4 +1,4 @@
 #!/bin/bash
 
 echo "Starting server..."
-go run cmd/api/main.go
+go run ./cmd/api
@@ -15,4 +15,4 @@ echo "[2/3] Generating docs..."
 echo "[3/3] Starting server..."
 echo "Server will be available at http://localhost:8080"
 echo "Swagger docs available at http://localhost:8080/swagger/index.html"
-go run cmd/api/main.go
+go run ./cmd/api
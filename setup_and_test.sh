@@ -8,7 +8,7 @@ echo "Generating docs..."
 
 echo "Starting server in background..."
 export MOCK_AUTH=true
-go run cmd/api/main.go &
+go run ./cmd/api &
 PID=$!
 sleep 5
 